@@ -0,0 +1,171 @@
+// Package viper is a high level client bundling the signer, provider and relayer packages
+// Advanced use cases can still use those packages directly
+package viper
+
+import (
+	"encoding/hex"
+	"errors"
+	"sync"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/relayer"
+	"github.com/vishruthsk/viper-go/signer"
+)
+
+// DefaultAATVersion is the version of the Viper AATs created by Client
+const DefaultAATVersion = "0.0.1"
+
+var (
+	// ErrNoAppSignature error when the app is not the client and no AAT signature is provided
+	ErrNoAppSignature = errors.New("no app signature provided for AAT")
+)
+
+// ClientConfig represents the settings of a Client
+type ClientConfig struct {
+	// RPCURL is the URL of the node used for queries
+	RPCURL string
+	// Dispatchers are the URLs of the nodes used to dispatch sessions
+	Dispatchers []string
+	// PrivateKey is the hex encoded private key of the client, it signs every relay proof
+	PrivateKey string
+	// AppPublicKey is the hex encoded public key of the staked app, empty when the client is the app itself
+	AppPublicKey string
+	// AppSignature is the hex encoded AAT signature made by the app, only needed when the app is not the client
+	AppSignature string
+}
+
+// Client does relays only with a chain and its data, handling dispatch, AAT creation, node selection and signing
+type Client struct {
+	signer   *signer.Signer
+	provider *provider.Provider
+	relayer  *relayer.Relayer
+	aat      *provider.ViperAAT
+
+	sessionsMutex sync.Mutex
+	sessions      map[string]*provider.Session
+}
+
+// NewClient returns instance of Client with given config
+func NewClient(config ClientConfig) (*Client, error) {
+	clientSigner, err := signer.NewSignerFromPrivateKey(config.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aat, err := newAAT(clientSigner, config)
+	if err != nil {
+		return nil, err
+	}
+
+	clientProvider := provider.NewProvider(config.RPCURL, config.Dispatchers)
+
+	return &Client{
+		signer:   clientSigner,
+		provider: clientProvider,
+		relayer:  relayer.NewRelayer(clientSigner, clientProvider),
+		aat:      aat,
+		sessions: map[string]*provider.Session{},
+	}, nil
+}
+
+func newAAT(clientSigner *signer.Signer, config ClientConfig) (*provider.ViperAAT, error) {
+	aat := &provider.ViperAAT{
+		Version:      DefaultAATVersion,
+		AppPubKey:    config.AppPublicKey,
+		ClientPubKey: clientSigner.GetPublicKey(),
+		Signature:    config.AppSignature,
+	}
+
+	if aat.AppPubKey == "" {
+		aat.AppPubKey = clientSigner.GetPublicKey()
+	}
+
+	if aat.Signature != "" {
+		return aat, nil
+	}
+
+	if aat.AppPubKey != clientSigner.GetPublicKey() {
+		return nil, ErrNoAppSignature
+	}
+
+	hashedAAT, err := relayer.HashAAT(aat)
+	if err != nil {
+		return nil, err
+	}
+
+	decodedHash, err := hex.DecodeString(hashedAAT)
+	if err != nil {
+		return nil, err
+	}
+
+	aat.Signature, err = clientSigner.Sign(decodedHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return aat, nil
+}
+
+// Signer returns the signer used by the client
+func (c *Client) Signer() *signer.Signer {
+	return c.signer
+}
+
+// Provider returns the provider used by the client
+func (c *Client) Provider() *provider.Provider {
+	return c.provider
+}
+
+// Relayer returns the relayer used by the client
+func (c *Client) Relayer() *relayer.Relayer {
+	return c.relayer
+}
+
+// AAT returns the Viper AAT used by the client
+func (c *Client) AAT() *provider.ViperAAT {
+	return c.aat
+}
+
+func (c *Client) getSession(chain string, refresh bool) (*provider.Session, error) {
+	c.sessionsMutex.Lock()
+	defer c.sessionsMutex.Unlock()
+
+	session, ok := c.sessions[chain]
+	if ok && !refresh {
+		return session, nil
+	}
+
+	dispatch, err := c.provider.Dispatch(c.aat.AppPubKey, chain, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.sessions[chain] = dispatch.Session
+
+	return dispatch.Session, nil
+}
+
+// Relay does a relay with given data to given chain in the current session of the app
+// The session is dispatched on the first relay of each chain and dispatched again once if the node rejects it
+func (c *Client) Relay(chain string, data string) (*relayer.Output, error) {
+	output, err := c.relay(chain, data, false)
+	if err != nil && relayer.IsSessionError(err) {
+		return c.relay(chain, data, true)
+	}
+
+	return output, err
+}
+
+func (c *Client) relay(chain string, data string, refreshSession bool) (*relayer.Output, error) {
+	session, err := c.getSession(chain, refreshSession)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.relayer.Relay(&relayer.Input{
+		Blockchain: chain,
+		Data:       data,
+		ViperAAT:   c.aat,
+		Session:    session,
+	}, nil)
+}
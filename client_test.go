@@ -0,0 +1,107 @@
+package viper
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/relayer"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/utils-go/mock-client"
+)
+
+const testPrivateKey = "1f8cbde30ef5a9db0a5a9d5eb40536fc9defc318b8581d543808b7504e0902bcb243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3"
+
+func TestNewClient(t *testing.T) {
+	c := require.New(t)
+
+	client, err := NewClient(ClientConfig{PrivateKey: "pjog"})
+	c.ErrorIs(err, signer.ErrInvalidPrivateKey)
+	c.Empty(client)
+
+	client, err = NewClient(ClientConfig{
+		PrivateKey:   testPrivateKey,
+		AppPublicKey: "f6f1f166536c55d3ad7b1b2629f0bce8a0a3dbd455d576ccb235419bcbfed7fd",
+	})
+	c.Equal(ErrNoAppSignature, err)
+	c.Empty(client)
+
+	client, err = NewClient(ClientConfig{
+		PrivateKey:   testPrivateKey,
+		AppPublicKey: "f6f1f166536c55d3ad7b1b2629f0bce8a0a3dbd455d576ccb235419bcbfed7fd",
+		AppSignature: "abcd",
+	})
+	c.NoError(err)
+	c.Equal("abcd", client.AAT().Signature)
+
+	client, err = NewClient(ClientConfig{PrivateKey: testPrivateKey})
+	c.NoError(err)
+
+	aat := client.AAT()
+	c.Equal(DefaultAATVersion, aat.Version)
+	c.Equal(client.Signer().GetPublicKey(), aat.AppPubKey)
+	c.Equal(client.Signer().GetPublicKey(), aat.ClientPubKey)
+
+	hashedAAT, err := relayer.HashAAT(aat)
+	c.NoError(err)
+
+	decodedHash, err := hex.DecodeString(hashedAAT)
+	c.NoError(err)
+
+	decodedPublicKey, err := hex.DecodeString(aat.AppPubKey)
+	c.NoError(err)
+
+	decodedSignature, err := hex.DecodeString(aat.Signature)
+	c.NoError(err)
+
+	c.True(ed25519.Verify(decodedPublicKey, decodedHash, decodedSignature))
+}
+
+func TestClient_Relay(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	client, err := NewClient(ClientConfig{
+		RPCURL:       "https://dummy.com",
+		Dispatchers:  []string{"https://dummy.com"},
+		PrivateKey:   testPrivateKey,
+		AppPublicKey: "f6f1f166536c55d3ad7b1b2629f0bce8a0a3dbd455d576ccb235419bcbfed7fd",
+		AppSignature: "abcd",
+	})
+	c.NoError(err)
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", provider.ClientDispatchRoute),
+		http.StatusInternalServerError, "provider/samples/client_dispatch.json")
+
+	output, err := client.Relay("0001", `{"method":"eth_blockNumber","id":1,"jsonrpc":"2.0"}`)
+	c.Equal(provider.Err5xxOnConnection, err)
+	c.Empty(output)
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", provider.ClientDispatchRoute),
+		http.StatusOK, "provider/samples/client_dispatch.json")
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://foo.bar:8080", provider.ClientRelayRoute),
+		http.StatusOK, "provider/samples/client_relay.json")
+
+	output, err = client.Relay("0001", `{"method":"eth_blockNumber","id":1,"jsonrpc":"2.0"}`)
+	c.NoError(err)
+	c.Equal("0001", output.Proof.Blockchain)
+	c.Equal(client.AAT(), output.Proof.AAT)
+	c.Equal(1, httpmock.GetCallCountInfo()[fmt.Sprintf("%s %s%s", http.MethodPost, "https://dummy.com", provider.ClientDispatchRoute)])
+
+	mock.AddMultipleMockedPlainResponses(http.MethodPost, fmt.Sprintf("%s%s", "https://foo.bar:8080", provider.ClientRelayRoute),
+		[]int{http.StatusBadRequest, http.StatusOK},
+		[]string{`{"error": {"code": 14, "codespace": "viper", "message": "invalid session"}}`, `{"response": "{}", "signature": "abf"}`})
+
+	output, err = client.Relay("0001", `{"method":"eth_blockNumber","id":1,"jsonrpc":"2.0"}`)
+	c.NoError(err)
+	c.Equal("{}", output.RelayOutput.Response)
+	c.Equal(2, httpmock.GetCallCountInfo()[fmt.Sprintf("%s %s%s", http.MethodPost, "https://dummy.com", provider.ClientDispatchRoute)])
+}
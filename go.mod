@@ -3,6 +3,8 @@ module github.com/vishruthsk/viper-go
 go 1.18
 
 require (
+	github.com/gojektech/heimdall v5.0.2+incompatible
+	github.com/gorilla/websocket v1.4.2
 	github.com/jarcoal/httpmock v1.2.0
 	github.com/stretchr/testify v1.8.0
 	github.com/vishruthsk/utils-go v0.1.0
@@ -22,12 +24,10 @@ require (
 	github.com/go-kit/log v0.2.0 // indirect
 	github.com/go-logfmt/logfmt v0.5.1 // indirect
 	github.com/gogo/protobuf v1.3.2 // indirect
-	github.com/gojektech/heimdall v5.0.2+incompatible // indirect
 	github.com/gojektech/valkyrie v0.0.0-20190210220504-8f62c1e7ba45 // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/golang/snappy v0.0.4 // indirect
 	github.com/google/btree v1.0.0 // indirect
-	github.com/gorilla/websocket v1.4.2 // indirect
 	github.com/gtank/merlin v0.1.1 // indirect
 	github.com/gtank/ristretto255 v0.1.2 // indirect
 	github.com/hashicorp/golang-lru v0.5.4 // indirect
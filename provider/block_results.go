@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// Attribute represents a key value pair of an Event
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Event represents an event emitted by a transaction or by the begin or end of a block
+type Event struct {
+	Type       string
+	Attributes []Attribute
+}
+
+// BlockTxResult represents the execution result of a transaction of a block
+type BlockTxResult struct {
+	Code      int
+	Codespace string
+	Log       string
+	Info      string
+	GasWanted int64
+	GasUsed   int64
+	Events    []Event
+}
+
+// BlockResults represents the transaction results and the block level events of a block
+// TxResults are in the order of the block transactions
+type BlockResults struct {
+	Height           int64
+	TxResults        []*BlockTxResult
+	BeginBlockEvents []Event
+	EndBlockEvents   []Event
+}
+
+type queryBlockResultsOutput struct {
+	Height           int64                 `json:"height,string"`
+	TxsResults       []*queryBlockTxResult `json:"txs_results"`
+	BeginBlockEvents []*queryEvent         `json:"begin_block_events"`
+	EndBlockEvents   []*queryEvent         `json:"end_block_events"`
+}
+
+type queryBlockTxResult struct {
+	Code      int           `json:"code"`
+	Codespace string        `json:"codespace"`
+	Log       string        `json:"log"`
+	Info      string        `json:"info"`
+	GasWanted int64         `json:"gas_wanted,string"`
+	GasUsed   int64         `json:"gas_used,string"`
+	Events    []*queryEvent `json:"events"`
+}
+
+// queryEvent is an event as encoded by the node, attribute keys and values are base64 encoded bytes
+type queryEvent struct {
+	Type       string `json:"type"`
+	Attributes []struct {
+		Key   []byte `json:"key"`
+		Value []byte `json:"value"`
+	} `json:"attributes"`
+}
+
+// GetBlockResults returns the transaction results, with their code, gas used and events,
+// and the begin and end block events of the block at the specified height
+// The event attributes are returned decoded from the base64 encoding of the node
+func (p *Provider) GetBlockResults(height int64) (*BlockResults, error) {
+	rawOutput, err := p.doPostRequest("", map[string]int64{
+		"height": height,
+	}, QueryBlockResultsRoute)
+
+	defer closeOrLog(rawOutput)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(rawOutput.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	output := queryBlockResultsOutput{}
+
+	err = json.Unmarshal(bodyBytes, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &BlockResults{
+		Height:           output.Height,
+		TxResults:        make([]*BlockTxResult, len(output.TxsResults)),
+		BeginBlockEvents: getEvents(output.BeginBlockEvents),
+		EndBlockEvents:   getEvents(output.EndBlockEvents),
+	}
+
+	for i, txResult := range output.TxsResults {
+		results.TxResults[i] = &BlockTxResult{
+			Code:      txResult.Code,
+			Codespace: txResult.Codespace,
+			Log:       txResult.Log,
+			Info:      txResult.Info,
+			GasWanted: txResult.GasWanted,
+			GasUsed:   txResult.GasUsed,
+			Events:    getEvents(txResult.Events),
+		}
+	}
+
+	return results, nil
+}
+
+func getEvents(queryEvents []*queryEvent) []Event {
+	events := make([]Event, len(queryEvents))
+
+	for i, queryEvent := range queryEvents {
+		events[i] = Event{
+			Type:       queryEvent.Type,
+			Attributes: make([]Attribute, len(queryEvent.Attributes)),
+		}
+
+		for j, attribute := range queryEvent.Attributes {
+			events[i].Attributes[j] = Attribute{Key: string(attribute.Key), Value: string(attribute.Value)}
+		}
+	}
+
+	return events
+}
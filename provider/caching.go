@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// DefaultResponseCacheMaxEntries is the default amount of responses kept by a MemoryResponseCache
+const DefaultResponseCacheMaxEntries = 1000
+
+// ResponseCache interface representing the storage of cached responses with their ETag
+// Implementations must be safe for concurrent use
+type ResponseCache interface {
+	Get(key string) ([]byte, string, bool)
+	Set(key, etag string, body []byte)
+}
+
+type cachedResponse struct {
+	key  string
+	etag string
+	body []byte
+}
+
+// MemoryResponseCache is an in memory ResponseCache that evicts the least recently used responses
+type MemoryResponseCache struct {
+	mutex      sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+}
+
+// NewMemoryResponseCache returns instance of MemoryResponseCache, maxEntries <= 0 uses DefaultResponseCacheMaxEntries
+func NewMemoryResponseCache(maxEntries int) *MemoryResponseCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultResponseCacheMaxEntries
+	}
+
+	return &MemoryResponseCache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+// Get returns the cached body and ETag of given key and if it is cached
+func (c *MemoryResponseCache) Get(key string) ([]byte, string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, "", false
+	}
+
+	c.order.MoveToFront(element)
+
+	response := element.Value.(*cachedResponse)
+
+	return response.body, response.etag, true
+}
+
+// Set caches the body and ETag of given key
+func (c *MemoryResponseCache) Set(key, etag string, body []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if ok {
+		element.Value = &cachedResponse{key: key, etag: etag, body: body}
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cachedResponse{key: key, etag: etag, body: body})
+
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedResponse).key)
+	}
+}
+
+// CachingProvider is a Provider that caches the responses of GetBlock, GetTransaction, GetApps and GetNodes
+// Responses with an ETag are cached and revalidated with If-None-Match, a 304 response returns the cached one
+// Every other request is done by the wrapped Provider
+type CachingProvider struct {
+	*Provider
+	cache ResponseCache
+}
+
+// NewCachingProvider returns instance of CachingProvider wrapping given provider
+func NewCachingProvider(provider *Provider, cache ResponseCache) *CachingProvider {
+	return &CachingProvider{
+		Provider: provider,
+		cache:    cache,
+	}
+}
+
+func (p *CachingProvider) doCachedPostRequest(params any, route V1RPCRoute) ([]byte, error) {
+	if p.optionErr != nil {
+		return nil, p.optionErr
+	}
+
+	body, err := getJSONBody(params)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s%s", p.rpcURL, route)
+	key := fmt.Sprintf("%s %s", url, body)
+
+	cachedBody, etag, cached := p.cache.Get(key)
+
+	var config *requestConfig
+	if cached {
+		config = &requestConfig{headers: map[string]string{"If-None-Match": etag}}
+	}
+
+	rawOutput, err := p.doRequest(url, body, config)
+
+	defer closeOrLog(rawOutput)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cached && rawOutput.StatusCode == http.StatusNotModified {
+		return cachedBody, nil
+	}
+
+	_, err = checkResponseStatus(route, rawOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(rawOutput.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if responseETag := rawOutput.Header.Get("ETag"); responseETag != "" {
+		p.cache.Set(key, responseETag, bodyBytes)
+	}
+
+	return bodyBytes, nil
+}
+
+func (p *CachingProvider) getCached(params any, route V1RPCRoute, output any) error {
+	bodyBytes, err := p.doCachedPostRequest(params, route)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bodyBytes, output)
+}
+
+// GetBlock returns the block structure at the specified height, height = 0 is used as latest
+func (p *CachingProvider) GetBlock(blockNumber int) (*GetBlockOutput, error) {
+	output := GetBlockOutput{}
+
+	err := p.getCached(map[string]int{"height": blockNumber}, QueryBlockRoute, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+// GetTransaction returns the transaction by the given transaction hash
+func (p *CachingProvider) GetTransaction(transactionHash string, options *GetTransactionOptions) (*GetTransactionOutput, error) {
+	output := GetTransactionOutput{}
+
+	err := p.getCached(getTransactionParams(transactionHash, options), QueryTXRoute, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+// GetNodes returns a page of nodes known at the specified height and with options
+func (p *CachingProvider) GetNodes(options *GetNodesOptions) (*GetNodesOutput, error) {
+	output := GetNodesOutput{}
+
+	err := p.getCached(getNodesParams(options), QueryNodesRoute, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+// GetApps returns a page of applications known at the specified height and staking status
+func (p *CachingProvider) GetApps(options *GetAppsOptions) (*GetAppsOutput, error) {
+	output := GetAppsOutput{}
+
+	err := p.getCached(getAppsParams(options), QueryAppsRoute, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
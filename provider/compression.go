@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// CompressionThreshold is the default body size in bytes above which compressed requests are gzipped
+const CompressionThreshold = 1024
+
+const gzipEncoding = "gzip"
+
+func gzipBody(body []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+
+	_, err := writer.Write(body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// gzipReadCloser closes both the gzip reader and the original response body
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (r *gzipReadCloser) Close() error {
+	_ = r.Reader.Close()
+
+	return r.body.Close()
+}
+
+// decompressResponse replaces the body of gzip encoded responses with its decompressed content
+func decompressResponse(response *http.Response) error {
+	if !strings.EqualFold(response.Header.Get("Content-Encoding"), gzipEncoding) {
+		return nil
+	}
+
+	reader, err := gzip.NewReader(response.Body)
+	if err != nil {
+		return err
+	}
+
+	response.Body = &gzipReadCloser{Reader: reader, body: response.Body}
+	response.Header.Del("Content-Encoding")
+	response.ContentLength = -1
+
+	return nil
+}
@@ -0,0 +1,245 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+// validatorsPerPage is the page size GetValidatorSet requests, the maximum Tendermint-style nodes serve
+const validatorsPerPage = 100
+
+// ErrMalformedRoundState error when the consensus state height/round/step is not in the height/round/step format
+var ErrMalformedRoundState = errors.New("malformed round state")
+
+// RoundStep represents the step of a consensus round
+type RoundStep int
+
+// Consensus round steps in the order a round goes through them
+const (
+	RoundStepNewHeight RoundStep = iota + 1
+	RoundStepNewRound
+	RoundStepPropose
+	RoundStepPrevote
+	RoundStepPrevoteWait
+	RoundStepPrecommit
+	RoundStepPrecommitWait
+	RoundStepCommit
+)
+
+var roundStepNames = map[RoundStep]string{
+	RoundStepNewHeight:     "NewHeight",
+	RoundStepNewRound:      "NewRound",
+	RoundStepPropose:       "Propose",
+	RoundStepPrevote:       "Prevote",
+	RoundStepPrevoteWait:   "PrevoteWait",
+	RoundStepPrecommit:     "Precommit",
+	RoundStepPrecommitWait: "PrecommitWait",
+	RoundStepCommit:        "Commit",
+}
+
+// String returns the name of the step, Unknown for steps out of range
+func (s RoundStep) String() string {
+	name, ok := roundStepNames[s]
+	if !ok {
+		return "Unknown"
+	}
+
+	return name
+}
+
+// ValidatorPubKey represents the consensus public key of a validator
+type ValidatorPubKey struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Validator represents a validator of the consensus validator set
+type Validator struct {
+	Address          string          `json:"address"`
+	PubKey           ValidatorPubKey `json:"pub_key"`
+	VotingPower      int64           `json:"voting_power,string"`
+	ProposerPriority int64           `json:"proposer_priority,string"`
+}
+
+// ValidatorSet represents the validators of the consensus at a height
+// Proposer is the validator with the highest proposer priority, the lowest address on ties
+type ValidatorSet struct {
+	BlockHeight      int64
+	Validators       []*Validator
+	Proposer         *Validator
+	TotalVotingPower int64
+}
+
+// VotingPowerShares returns the share of the total voting power of each validator by address
+func (s *ValidatorSet) VotingPowerShares() map[string]float64 {
+	shares := make(map[string]float64, len(s.Validators))
+
+	for _, validator := range s.Validators {
+		if s.TotalVotingPower == 0 {
+			shares[validator.Address] = 0
+
+			continue
+		}
+
+		shares[validator.Address] = float64(validator.VotingPower) / float64(s.TotalVotingPower)
+	}
+
+	return shares
+}
+
+type queryValidatorsOutput struct {
+	BlockHeight int64        `json:"block_height,string"`
+	Validators  []*Validator `json:"validators"`
+	Count       int          `json:"count,string"`
+	Total       int          `json:"total,string"`
+}
+
+// ConsensusProposer represents the proposer of the current consensus round
+type ConsensusProposer struct {
+	Address string `json:"address"`
+	Index   int    `json:"index"`
+}
+
+// RoundVotes represents the votes received in a consensus round
+type RoundVotes struct {
+	Round              int      `json:"round"`
+	Prevotes           []string `json:"prevotes"`
+	PrevotesBitArray   string   `json:"prevotes_bit_array"`
+	Precommits         []string `json:"precommits"`
+	PrecommitsBitArray string   `json:"precommits_bit_array"`
+}
+
+// ConsensusState represents the round state of the consensus of a node
+type ConsensusState struct {
+	Height            int64
+	Round             int
+	Step              RoundStep
+	StartTime         time.Time
+	ProposalBlockHash string
+	LockedBlockHash   string
+	ValidBlockHash    string
+	Proposer          ConsensusProposer
+	Votes             []RoundVotes
+}
+
+type queryConsensusStateOutput struct {
+	RoundState struct {
+		HeightRoundStep   string            `json:"height/round/step"`
+		StartTime         time.Time         `json:"start_time"`
+		ProposalBlockHash string            `json:"proposal_block_hash"`
+		LockedBlockHash   string            `json:"locked_block_hash"`
+		ValidBlockHash    string            `json:"valid_block_hash"`
+		HeightVoteSet     []RoundVotes      `json:"height_vote_set"`
+		Proposer          ConsensusProposer `json:"proposer"`
+	} `json:"round_state"`
+}
+
+func (p *Provider) getValidatorsPage(height int64, page int) (*queryValidatorsOutput, error) {
+	rawOutput, err := p.doPostRequest("", map[string]any{
+		"height":   height,
+		"page":     page,
+		"per_page": validatorsPerPage,
+	}, QueryValidatorsRoute)
+
+	defer closeOrLog(rawOutput)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(rawOutput.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	output := queryValidatorsOutput{}
+
+	err = json.Unmarshal(bodyBytes, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+// GetValidatorSet returns the consensus validator set at the specified height, height = 0 is used as latest
+// Every page of the set is requested until the total the node reports is reached
+func (p *Provider) GetValidatorSet(height int64) (*ValidatorSet, error) {
+	set := &ValidatorSet{}
+
+	for page := 1; ; page++ {
+		output, err := p.getValidatorsPage(height, page)
+		if err != nil {
+			return nil, err
+		}
+
+		set.BlockHeight = output.BlockHeight
+		set.Validators = append(set.Validators, output.Validators...)
+
+		if len(output.Validators) == 0 || len(set.Validators) >= output.Total {
+			break
+		}
+	}
+
+	for _, validator := range set.Validators {
+		set.TotalVotingPower += validator.VotingPower
+
+		if isNextProposer(validator, set.Proposer) {
+			set.Proposer = validator
+		}
+	}
+
+	return set, nil
+}
+
+func isNextProposer(validator, proposer *Validator) bool {
+	if proposer == nil || validator.ProposerPriority > proposer.ProposerPriority {
+		return true
+	}
+
+	return validator.ProposerPriority == proposer.ProposerPriority && validator.Address < proposer.Address
+}
+
+// GetConsensusState returns the round state of the consensus of the node
+func (p *Provider) GetConsensusState() (*ConsensusState, error) {
+	rawOutput, err := p.doPostRequest("", nil, QueryConsensusStateRoute)
+
+	defer closeOrLog(rawOutput)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(rawOutput.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	output := queryConsensusStateOutput{}
+
+	err = json.Unmarshal(bodyBytes, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	roundState := output.RoundState
+
+	state := &ConsensusState{
+		StartTime:         roundState.StartTime,
+		ProposalBlockHash: roundState.ProposalBlockHash,
+		LockedBlockHash:   roundState.LockedBlockHash,
+		ValidBlockHash:    roundState.ValidBlockHash,
+		Proposer:          roundState.Proposer,
+		Votes:             roundState.HeightVoteSet,
+	}
+
+	_, err = fmt.Sscanf(roundState.HeightRoundStep, "%d/%d/%d", &state.Height, &state.Round, &state.Step)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformedRoundState, roundState.HeightRoundStep)
+	}
+
+	return state, nil
+}
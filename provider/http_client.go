@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gojektech/heimdall"
+	"github.com/gojektech/heimdall/httpclient"
+	"github.com/vishruthsk/utils-go/client"
+)
+
+const (
+	defaultRequestTimeout = 5 * time.Second
+	defaultRequestRetries = 0
+
+	initialBackoffTimeout = 2 * time.Millisecond
+	maxBackoffTimeout     = 9 * time.Millisecond
+	backoffExponentFactor = 2
+	maxJitterInterval     = 2 * time.Millisecond
+)
+
+var (
+	// ErrCertificatePinMismatch error when the node certificate does not match any pinned fingerprint
+	ErrCertificatePinMismatch = errors.New("certificate pin mismatch")
+	// ErrNilHTTPClient error when a nil HTTP client is given to WithHTTPClient
+	ErrNilHTTPClient = errors.New("nil HTTP client")
+	// ErrCertificatePinWithHTTPClient error when WithCertificatePin is combined with WithHTTPClient
+	ErrCertificatePinWithHTTPClient = errors.New("certificate pinning not supported with a custom HTTP client")
+	// ErrInvalidMaxAttempts error when WithRetry is given less than one attempt
+	ErrInvalidMaxAttempts = errors.New("max attempts must be at least 1")
+	// ErrNoRequestIDFunc error when WithRequestID is given no function to generate request IDs
+	ErrNoRequestIDFunc = errors.New("no request ID function provided")
+
+	defaultRetrier = heimdall.NewRetrier(heimdall.NewExponentialBackoff(initialBackoffTimeout, maxBackoffTimeout,
+		backoffExponentFactor, maxJitterInterval))
+)
+
+type requestErrorKey struct{}
+
+// errorRecordingDoer keeps the last transport error of a request in its context
+// the heimdall client only returns errors as strings, this allows returning typed errors
+// it also records the request in the provider stats and undoes the connection close forced by the heimdall client
+// when the provider reuses connections, see WithConnectionReuse
+type errorRecordingDoer struct {
+	httpClient       *http.Client
+	stats            *providerCounters
+	reuseConnections bool
+}
+
+func (d *errorRecordingDoer) Do(request *http.Request) (*http.Response, error) {
+	d.stats.start()
+
+	tracedRequest := d.stats.trace(request)
+	tracedRequest.Close = !d.reuseConnections
+
+	response, err := d.httpClient.Do(tracedRequest)
+	d.stats.finish(response)
+
+	if err != nil {
+		if requestErr, ok := request.Context().Value(requestErrorKey{}).(*error); ok {
+			*requestErr = err
+		}
+	}
+
+	return response, err
+}
+
+func withRequestErrorRecorder(ctx context.Context) (context.Context, *error) {
+	var requestErr error
+
+	return context.WithValue(ctx, requestErrorKey{}, &requestErr), &requestErr
+}
+
+// getRequestContext returns the context of a request, bounded by the request timeout when one is set
+// and derived from the context of the request config when it has one
+func (p *Provider) getRequestContext(options *requestConfig) (context.Context, context.CancelFunc) {
+	parent := context.Background()
+	if options != nil && options.ctx != nil {
+		parent = options.ctx
+	}
+
+	if p.requestTimeout <= 0 {
+		return context.WithCancel(parent)
+	}
+
+	return context.WithTimeout(parent, p.requestTimeout)
+}
+
+// getRequestContextError returns the error of a failed request, the error of the request config context
+// takes precedence over ErrRequestTimeout
+func getRequestContextError(ctx context.Context, options *requestConfig, err error) error {
+	if options != nil && options.ctx != nil && options.ctx.Err() != nil {
+		return options.ctx.Err()
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrRequestTimeout
+	}
+
+	return err
+}
+
+// cancelOnCloseBody releases the request context once the response body is closed
+// the context can not be canceled before as it also bounds the body read
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+
+	return b.ReadCloser.Close()
+}
+
+func (p *Provider) buildHTTPClient() *http.Client {
+	if p.httpClient != nil {
+		return p.httpClient
+	}
+
+	httpClient := &http.Client{
+		Timeout: p.timeout,
+	}
+
+	if len(p.certificatePins) != 0 {
+		httpClient.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: p.getTLSConfig(),
+		}
+	}
+
+	return httpClient
+}
+
+// getTLSConfig returns the TLS config of the provider connections, nil to use the default one
+func (p *Provider) getTLSConfig() *tls.Config {
+	if len(p.certificatePins) == 0 {
+		return nil
+	}
+
+	return &tls.Config{
+		// chain verification is replaced by the pinned fingerprints so self signed node certificates can be pinned
+		InsecureSkipVerify:    true, // #nosec G402
+		VerifyPeerCertificate: p.verifyCertificatePin,
+	}
+}
+
+func (p *Provider) getRetrier() heimdall.Retriable {
+	if p.retrier == nil {
+		return defaultRetrier
+	}
+
+	return p.retrier
+}
+
+// newBackoffRetrier returns a retrier calling backoff with the failed attempt number
+// the last attempt does not wait as no retry follows it
+func newBackoffRetrier(maxAttempts int, backoff BackoffFunc) heimdall.Retriable {
+	return heimdall.NewRetrierFunc(func(retry int) time.Duration {
+		attempt := retry + 1
+		if backoff == nil || attempt >= maxAttempts {
+			return 0
+		}
+
+		return backoff(attempt)
+	})
+}
+
+func (p *Provider) buildClient() {
+	if p.stats == nil {
+		p.stats = &providerCounters{}
+	}
+
+	p.client = &client.Client{
+		Client: httpclient.NewClient(
+			httpclient.WithRetryCount(p.retries),
+			httpclient.WithRetrier(p.getRetrier()),
+			httpclient.WithHTTPClient(&errorRecordingDoer{
+				httpClient:       p.buildHTTPClient(),
+				stats:            p.stats,
+				reuseConnections: p.reuseConnections,
+			}),
+		),
+	}
+}
+
+func (p *Provider) verifyCertificatePin(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return ErrCertificatePinMismatch
+	}
+
+	fingerprint := sha256.Sum256(rawCerts[0])
+
+	if !p.certificatePins[hex.EncodeToString(fingerprint[:])] {
+		return ErrCertificatePinMismatch
+	}
+
+	return nil
+}
+
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrJSONRPC error when the relayed chain answers with a JSON-RPC error object
+	ErrJSONRPC = errors.New("JSON-RPC error")
+	// ErrNoJSONRPCResult error when a JSON-RPC response has neither result nor error
+	ErrNoJSONRPCResult = errors.New("no JSON-RPC result")
+)
+
+// JSONRPCResponse represents a JSON-RPC response envelope
+// ID is kept raw since JSON-RPC allows number, string and null ids
+type JSONRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result"`
+	Error   *JSONRPCError   `json:"error"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// JSONRPCError represents the error object of a JSON-RPC response
+type JSONRPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *JSONRPCError) Error() string {
+	return fmt.Sprintf("%s: code %d, message: %s", ErrJSONRPC, e.Code, e.Message)
+}
+
+// Unwrap returns ErrJSONRPC so the error can be checked with errors.Is
+func (e *JSONRPCError) Unwrap() error {
+	return ErrJSONRPC
+}
+
+// Err returns the JSON-RPC error object of the response as *JSONRPCError or nil if it has none
+func (r *JSONRPCResponse) Err() error {
+	if r.Error == nil {
+		return nil
+	}
+
+	return r.Error
+}
+
+// Decode unmarshals the response result into result or returns the JSON-RPC error of the response
+func (r *JSONRPCResponse) Decode(result any) error {
+	err := r.Err()
+	if err != nil {
+		return err
+	}
+
+	if len(r.Result) == 0 {
+		return ErrNoJSONRPCResult
+	}
+
+	return json.Unmarshal(r.Result, result)
+}
+
+// AsJSONRPC returns the relay response unmarshalled as a JSON-RPC response envelope
+// Responses that are not JSON return ErrNonJSONResponse, a JSON-RPC error object is not an error here, use Err
+func (o *RelayOutput) AsJSONRPC() (*JSONRPCResponse, error) {
+	response := []byte(o.Response)
+
+	if !json.Valid(response) {
+		return nil, ErrNonJSONResponse
+	}
+
+	rpcResponse := JSONRPCResponse{}
+
+	err := json.Unmarshal(response, &rpcResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpcResponse, nil
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/vishruthsk/viper-go/utils"
+)
+
+// RequestLogger interface representing a logger of the provider HTTP traffic
+// Request bodies are logged before compression and response bodies after decompression
+// LogResponse is called with status code 0 and no body when the request got no response
+type RequestLogger interface {
+	LogRequest(method, url string, body []byte)
+	LogResponse(statusCode int, body []byte, duration time.Duration)
+}
+
+// StdoutRequestLogger is a RequestLogger writing every request and response to stdout
+type StdoutRequestLogger struct {
+	writer io.Writer
+}
+
+// NewStdoutRequestLogger returns instance of StdoutRequestLogger
+func NewStdoutRequestLogger() *StdoutRequestLogger {
+	return &StdoutRequestLogger{
+		writer: os.Stdout,
+	}
+}
+
+func (l *StdoutRequestLogger) getWriter() io.Writer {
+	if l.writer == nil {
+		return os.Stdout
+	}
+
+	return l.writer
+}
+
+// LogRequest writes the outgoing request to stdout
+func (l *StdoutRequestLogger) LogRequest(method, url string, body []byte) {
+	fmt.Fprintf(l.getWriter(), "viper request: %s %s %s\n", method, url, body)
+}
+
+// LogResponse writes the incoming response to stdout
+func (l *StdoutRequestLogger) LogResponse(statusCode int, body []byte, duration time.Duration) {
+	fmt.Fprintf(l.getWriter(), "viper response: %d in %s %s\n", statusCode, duration, body)
+}
+
+// logResponse logs the response, its body is read and replaced so it can still be read by the caller
+func (p *Provider) logResponse(response *http.Response, duration time.Duration) error {
+	if p.requestLogger == nil {
+		return nil
+	}
+
+	if response == nil {
+		p.requestLogger.LogResponse(0, nil, duration)
+
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+
+	utils.CloseOrLog(response.Body)
+
+	if err != nil {
+		return err
+	}
+
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	p.requestLogger.LogResponse(response.StatusCode, body, duration)
+
+	return nil
+}
@@ -1,5 +1,201 @@
 package provider
 
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// HMACHeader is the request header holding the HMAC-SHA256 of the request body
+const HMACHeader = "X-VIPER-HMAC"
+
+// ProviderOption represents an optional setting of a Provider
+type ProviderOption func(*Provider) error
+
+// WithHMACAuth signs every request body with HMAC-SHA256 using the given secret
+// The hex encoded result is sent in the X-VIPER-HMAC header, it is computed over the raw body before any compression
+func WithHMACAuth(secret string) ProviderOption {
+	return func(p *Provider) error {
+		p.hmacSecret = []byte(secret)
+
+		return nil
+	}
+}
+
+// WithCertificatePin only accepts nodes whose leaf certificate SHA-256 fingerprint is in the given hex encoded list
+// Pinning replaces the certificate chain verification, an empty list disables it
+// Requests to nodes with other certificates fail with ErrCertificatePinMismatch
+func WithCertificatePin(fingerprints []string) ProviderOption {
+	return func(p *Provider) error {
+		if len(fingerprints) == 0 {
+			p.certificatePins = nil
+
+			return nil
+		}
+
+		p.certificatePins = make(map[string]bool, len(fingerprints))
+
+		for _, fingerprint := range fingerprints {
+			p.certificatePins[normalizeFingerprint(fingerprint)] = true
+		}
+
+		return nil
+	}
+}
+
+// WithHTTPClient makes every provider request with the given HTTP client
+// The client is used as is, its timeout and transport replace the provider timeout
+// It can not be combined with WithCertificatePin, requests fail with ErrCertificatePinWithHTTPClient otherwise
+func WithHTTPClient(client *http.Client) ProviderOption {
+	return func(p *Provider) error {
+		if client == nil {
+			return ErrNilHTTPClient
+		}
+
+		p.httpClient = client
+
+		return nil
+	}
+}
+
+// WithBaseHeaders sends the given HTTP headers with every request, like the API key of a gateway
+// Per request headers take precedence over them on conflicts
+func WithBaseHeaders(headers map[string]string) ProviderOption {
+	return func(p *Provider) error {
+		p.baseHeaders = make(map[string]string, len(headers))
+
+		for key, value := range headers {
+			p.baseHeaders[key] = value
+		}
+
+		return nil
+	}
+}
+
+// WithCompression gzips the body of every request bigger than the compression threshold when enabled
+// The threshold defaults to CompressionThreshold, smaller bodies are sent as is, see WithCompressionThreshold
+// Only enable it with nodes supporting gzip requests, gzip responses are always decompressed
+func WithCompression(enabled bool) ProviderOption {
+	return func(p *Provider) error {
+		p.compress = enabled
+
+		return nil
+	}
+}
+
+// WithCompressionThreshold sets the body size in bytes above which compressed requests are gzipped
+// Defaults to CompressionThreshold, 0 gzips every non empty body
+func WithCompressionThreshold(bytes int) ProviderOption {
+	return func(p *Provider) error {
+		p.compressionThreshold = bytes
+
+		return nil
+	}
+}
+
+// WithConnectionReuse keeps the connections open after their response when enabled so the HTTP client pool
+// reuses idle connections, see ProviderStats
+// It is disabled by default, every request is sent with the Connection: close header
+func WithConnectionReuse(enabled bool) ProviderOption {
+	return func(p *Provider) error {
+		p.reuseConnections = enabled
+
+		return nil
+	}
+}
+
+// WithRequestLogger logs every request done by the provider and its response with the given logger
+func WithRequestLogger(logger RequestLogger) ProviderOption {
+	return func(p *Provider) error {
+		p.requestLogger = logger
+
+		return nil
+	}
+}
+
+// WithMaxResponseSize fails requests whose response body is bigger than maxBytes with a ResponseTooLargeError
+// The limit applies to the decompressed body, maxBytes <= 0 disables it
+func WithMaxResponseSize(maxBytes int64) ProviderOption {
+	return func(p *Provider) error {
+		p.maxResponseSize = maxBytes
+
+		return nil
+	}
+}
+
+// WithRequestTimeout bounds every provider request, including its retries and the body read, to the given duration
+// Unlike the HTTP client timeout it applies to each call as a whole, requests not finished in time fail with ErrRequestTimeout
+// timeout <= 0 disables it
+func WithRequestTimeout(timeout time.Duration) ProviderOption {
+	return func(p *Provider) error {
+		p.requestTimeout = timeout
+
+		return nil
+	}
+}
+
+// DefaultRequestIDHeader is the header used by WithRequestID when no header is given
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// RequestIDFunc returns a new unique ID for a request
+type RequestIDFunc func() string
+
+// WithUserAgent sends the given User-Agent header with every request, including relays
+func WithUserAgent(userAgent string) ProviderOption {
+	return func(p *Provider) error {
+		p.userAgent = userAgent
+
+		return nil
+	}
+}
+
+// WithRequestID sends an ID generated by generate in the given header with every request, including relays
+// The ID is generated once per request and kept between its retries, an empty header uses DefaultRequestIDHeader
+func WithRequestID(header string, generate RequestIDFunc) ProviderOption {
+	return func(p *Provider) error {
+		if generate == nil {
+			return ErrNoRequestIDFunc
+		}
+
+		if header == "" {
+			header = DefaultRequestIDHeader
+		}
+
+		p.requestIDHeader = header
+		p.requestIDFunc = generate
+
+		return nil
+	}
+}
+
+// BackoffFunc returns the time to wait before retrying a request after the given failed attempt, starting at 1
+type BackoffFunc func(attempt int) time.Duration
+
+// WithRetry retries requests failing with a network error or a 5xx status, doing at most maxAttempts attempts
+// 4xx responses are never retried, a nil backoff retries right away
+// It replaces the retries set with UpdateRequestConfig until that is called again
+func WithRetry(maxAttempts int, backoff BackoffFunc) ProviderOption {
+	return func(p *Provider) error {
+		if maxAttempts < 1 {
+			return ErrInvalidMaxAttempts
+		}
+
+		p.retries = maxAttempts - 1
+		p.retrier = newBackoffRetrier(maxAttempts, backoff)
+
+		return nil
+	}
+}
+
+func computeHMAC(body, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 // JailedStatus enum that represents jailed status
 type JailedStatus int
 
@@ -22,6 +218,13 @@ const (
 	Staked
 )
 
+const (
+	// RelayReceiptType represents the receipt of served relays
+	RelayReceiptType = "relay"
+	// ChallengeReceiptType represents the receipt of submitted challenges
+	ChallengeReceiptType = "challenge"
+)
+
 // Order enum that represents the order which RPC requests should return their outputs
 type Order string
 
@@ -75,6 +278,18 @@ type GetBlockTransactionsOptions struct {
 	Order   Order
 }
 
+// GetSigningInfoOptions represents optional arguments for GetSigningInfo request
+type GetSigningInfoOptions struct {
+	Height int
+}
+
+// GetNodeReceiptsOptions represents optional arguments for GetNodeReceipts request
+type GetNodeReceiptsOptions struct {
+	Height  int
+	Page    int
+	PerPage int
+}
+
 // GetAppOptions represents optional arguments for GetApp request
 type GetAppOptions struct {
 	Height int
@@ -113,8 +328,29 @@ type DispatchRequestOptions struct {
 }
 
 // RelayRequestOptions represents optional arguments for Relay request
+// Compress gzips request bodies bigger than the provider compression threshold, only use it with nodes supporting gzip requests
+// VerifyResponse checks the response is signed by the servicer of the relay proof
+// Headers are HTTP headers sent with the relay request, they take precedence over the provider base headers
+// ResponseValidator checks the relay response, a failure is returned as an InvalidRelayResponseError
+// Trace is not used by the provider, the relayer fills the trace of its output with the time taken by every relay step
 type RelayRequestOptions struct {
 	RejectSelfSignedCertificates bool
+	Compress                     bool
+	VerifyResponse               bool
+	Headers                      map[string]string
+	ResponseValidator            ResponseValidator
+	Trace                        bool
+}
+
+// ResponseValidator returns an error when the relay response does not have the expected shape
+type ResponseValidator func(response []byte) error
+
+// RequestOptions represents optional arguments for PostRaw request
+// Headers take precedence over the provider base headers, Compress gzips bodies bigger than
+// the provider compression threshold
+type RequestOptions struct {
+	Headers  map[string]string
+	Compress bool
 }
 
 // GetTransactionOptions represents the optional arguments for a GetTransaction request
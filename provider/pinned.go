@@ -0,0 +1,239 @@
+package provider
+
+import "math/big"
+
+// PinnedProvider is a view of a Provider whose queries default to a pinned height
+// Queries with a height set in their options keep it, so only the unset heights are pinned
+type PinnedProvider struct {
+	provider *Provider
+	height   int
+	err      error
+}
+
+// AtHeight returns a view of the provider whose queries target the given height
+// height = 0 pins the latest height, resolved once with GetBlockHeight so queries do not drift across blocks
+// An error resolving the latest height is returned by every query of the view
+func (p *Provider) AtHeight(height int64) *PinnedProvider {
+	pinned := &PinnedProvider{
+		provider: p,
+		height:   int(height),
+	}
+
+	if height == 0 {
+		pinned.height, pinned.err = p.GetBlockHeight()
+	}
+
+	return pinned
+}
+
+// Height returns the pinned height
+func (p *PinnedProvider) Height() int64 {
+	return int64(p.height)
+}
+
+func (p *PinnedProvider) pinHeight(height *int) {
+	if *height == 0 {
+		*height = p.height
+	}
+}
+
+// GetBlock returns the block structure at the pinned height
+func (p *PinnedProvider) GetBlock() (*GetBlockOutput, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return p.provider.GetBlock(p.height)
+}
+
+// GetBalance requests the balance of the specified address at the pinned height
+func (p *PinnedProvider) GetBalance(address string, options *GetBalanceOptions) (*big.Int, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	finalOptions := GetBalanceOptions{}
+	if options != nil {
+		finalOptions = *options
+	}
+
+	p.pinHeight(&finalOptions.Height)
+
+	return p.provider.GetBalance(address, &finalOptions)
+}
+
+// GetType returns type of given address at the pinned height
+func (p *PinnedProvider) GetType(address string, options *GetTypeOptions) (AddressType, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+
+	finalOptions := GetTypeOptions{}
+	if options != nil {
+		finalOptions = *options
+	}
+
+	p.pinHeight(&finalOptions.Height)
+
+	return p.provider.GetType(address, &finalOptions)
+}
+
+// GetAllParams returns the params at the pinned height
+func (p *PinnedProvider) GetAllParams(options *GetAllParamsOptions) (*AllParams, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	finalOptions := GetAllParamsOptions{}
+	if options != nil {
+		finalOptions = *options
+	}
+
+	p.pinHeight(&finalOptions.Height)
+
+	return p.provider.GetAllParams(&finalOptions)
+}
+
+// GetNodes returns a page of nodes at the pinned height
+func (p *PinnedProvider) GetNodes(options *GetNodesOptions) (*GetNodesOutput, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	finalOptions := GetNodesOptions{}
+	if options != nil {
+		finalOptions = *options
+	}
+
+	p.pinHeight(&finalOptions.Height)
+
+	return p.provider.GetNodes(&finalOptions)
+}
+
+// GetNode returns the node at the pinned height
+func (p *PinnedProvider) GetNode(address string, options *GetNodeOptions) (*GetNodeOutput, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	finalOptions := GetNodeOptions{}
+	if options != nil {
+		finalOptions = *options
+	}
+
+	p.pinHeight(&finalOptions.Height)
+
+	return p.provider.GetNode(address, &finalOptions)
+}
+
+// GetSigningInfo returns the signing info of the node at the pinned height
+func (p *PinnedProvider) GetSigningInfo(address string, options *GetSigningInfoOptions) (*SigningInfo, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	finalOptions := GetSigningInfoOptions{}
+	if options != nil {
+		finalOptions = *options
+	}
+
+	p.pinHeight(&finalOptions.Height)
+
+	return p.provider.GetSigningInfo(address, &finalOptions)
+}
+
+// GetNodeReceipts returns a page of receipts of the node at the pinned height
+func (p *PinnedProvider) GetNodeReceipts(address string, options *GetNodeReceiptsOptions) (*GetNodeReceiptsOutput, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	finalOptions := GetNodeReceiptsOptions{}
+	if options != nil {
+		finalOptions = *options
+	}
+
+	p.pinHeight(&finalOptions.Height)
+
+	return p.provider.GetNodeReceipts(address, &finalOptions)
+}
+
+// GetApps returns a page of applications at the pinned height
+func (p *PinnedProvider) GetApps(options *GetAppsOptions) (*GetAppsOutput, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	finalOptions := GetAppsOptions{}
+	if options != nil {
+		finalOptions = *options
+	}
+
+	p.pinHeight(&finalOptions.Height)
+
+	return p.provider.GetApps(&finalOptions)
+}
+
+// GetApp returns the app at the pinned height
+func (p *PinnedProvider) GetApp(address string, options *GetAppOptions) (*GetAppOutput, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	finalOptions := GetAppOptions{}
+	if options != nil {
+		finalOptions = *options
+	}
+
+	p.pinHeight(&finalOptions.Height)
+
+	return p.provider.GetApp(address, &finalOptions)
+}
+
+// GetAccount returns the account at the pinned height
+func (p *PinnedProvider) GetAccount(address string, options *GetAccountOptions) (*GetAccountOutput, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	finalOptions := GetAccountOptions{}
+	if options != nil {
+		finalOptions = *options
+	}
+
+	p.pinHeight(&finalOptions.Height)
+
+	return p.provider.GetAccount(address, &finalOptions)
+}
+
+// GetAccounts returns a page of accounts at the pinned height
+func (p *PinnedProvider) GetAccounts(options *GetAccountsOptions) (*GetAccountsOutput, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	finalOptions := GetAccountsOptions{}
+	if options != nil {
+		finalOptions = *options
+	}
+
+	p.pinHeight(&finalOptions.Height)
+
+	return p.provider.GetAccounts(&finalOptions)
+}
+
+// GetBlockTransactions returns a page of the transactions of the block at the pinned height
+func (p *PinnedProvider) GetBlockTransactions(options *GetBlockTransactionsOptions) (*GetBlockTransactionsOutput, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	finalOptions := GetBlockTransactionsOptions{}
+	if options != nil {
+		finalOptions = *options
+	}
+
+	p.pinHeight(&finalOptions.Height)
+
+	return p.provider.GetBlockTransactions(&finalOptions)
+}
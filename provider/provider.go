@@ -2,6 +2,8 @@
 package provider
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
 	"encoding/json"
 	"errors"
@@ -14,6 +16,7 @@ import (
 
 	"github.com/vishruthsk/viper-go/utils"
 
+	"github.com/gojektech/heimdall"
 	"github.com/vishruthsk/utils-go/client"
 )
 
@@ -28,34 +31,122 @@ var (
 	ErrNoDispatchers = errors.New("no dispatchers")
 	// ErrNonJSONResponse error when provider does not respond with a JSON
 	ErrNonJSONResponse = errors.New("non JSON response")
+	// ErrNodeNotFound error when given address is not a validator
+	ErrNodeNotFound = errors.New("node not found")
+	// ErrInvalidReceiptType error when receipt type is not relay nor challenge
+	ErrInvalidReceiptType = errors.New("invalid receipt type")
+	// ErrReceiptNotFound error when no receipt matches the given session
+	ErrReceiptNotFound = errors.New("receipt not found")
+	// ErrResponseTooLarge error when the response body is bigger than the max response size
+	ErrResponseTooLarge = errors.New("response too large")
+	// ErrInvalidRelayResponse error when the relay response is rejected by the response validator
+	ErrInvalidRelayResponse = errors.New("invalid relay response")
+	// ErrRequestTimeout error when a request does not finish before the request timeout
+	ErrRequestTimeout = errors.New("request timed out")
 
 	errOnRelayRequest = errors.New("error on relay request")
 )
 
+// ResponseTooLargeError represents the thrown error when a response body exceeds the max response size
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("%s: limit is %d bytes", ErrResponseTooLarge, e.Limit)
+}
+
+// Unwrap returns ErrResponseTooLarge so the error can be checked with errors.Is
+func (e *ResponseTooLargeError) Unwrap() error {
+	return ErrResponseTooLarge
+}
+
+// InvalidRelayResponseError represents the thrown error when the relay response is rejected by the response validator
+// It is a node failure, so the relay can be retried with another node
+type InvalidRelayResponseError struct {
+	Err error
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *InvalidRelayResponseError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInvalidRelayResponse, e.Err)
+}
+
+// Unwrap returns ErrInvalidRelayResponse so the error can be checked with errors.Is
+func (e *InvalidRelayResponseError) Unwrap() error {
+	return ErrInvalidRelayResponse
+}
+
 // Provider struct handler por JSON RPC provider
 type Provider struct {
 	rpcURL      string
 	dispatchers []string
 	client      *client.Client
+	optionErr   error
+	hmacSecret  []byte
+
+	retries         int
+	retrier         heimdall.Retriable
+	timeout         time.Duration
+	certificatePins map[string]bool
+	httpClient      *http.Client
+	baseHeaders     map[string]string
+	compress        bool
+	requestLogger   RequestLogger
+	maxResponseSize int64
+	requestTimeout  time.Duration
+	wsURL           string
+	userAgent       string
+	requestIDHeader string
+	requestIDFunc   RequestIDFunc
+	stats           *providerCounters
+
+	reuseConnections     bool
+	compressionThreshold int
 }
 
 // NewProvider returns Provider instance from input
-func NewProvider(rpcURL string, dispatchers []string) *Provider {
-	return &Provider{
+// An error returned by any of the options is returned by every request done with the provider
+func NewProvider(rpcURL string, dispatchers []string, options ...ProviderOption) *Provider {
+	provider := &Provider{
 		rpcURL:      rpcURL,
 		dispatchers: dispatchers,
-		client:      client.NewDefaultClient(),
+		retries:     defaultRequestRetries,
+		timeout:     defaultRequestTimeout,
+
+		compressionThreshold: CompressionThreshold,
+	}
+
+	for _, option := range options {
+		err := option(provider)
+		if err != nil && provider.optionErr == nil {
+			provider.optionErr = err
+		}
+	}
+
+	if provider.optionErr == nil && provider.httpClient != nil && len(provider.certificatePins) != 0 {
+		provider.optionErr = ErrCertificatePinWithHTTPClient
 	}
+
+	provider.buildClient()
+
+	return provider
 }
 
 // UpdateRequestConfig updates retries and timeout used for RPC requests
 func (p *Provider) UpdateRequestConfig(retries int, timeout time.Duration) {
-	p.client = client.NewCustomClient(retries, timeout)
+	p.retries = retries
+	p.timeout = timeout
+
+	p.buildClient()
 }
 
 // ResetRequestConfigToDefault resets request config to default
 func (p *Provider) ResetRequestConfigToDefault() {
-	p.client = client.NewDefaultClient()
+	p.UpdateRequestConfig(defaultRequestRetries, defaultRequestTimeout)
 }
 
 func (p *Provider) getFinalRPCURL(rpcURL string, route V1RPCRoute) (string, error) {
@@ -75,17 +166,222 @@ func (p *Provider) getFinalRPCURL(rpcURL string, route V1RPCRoute) (string, erro
 	return p.rpcURL, nil
 }
 
+func getJSONBody(params any) ([]byte, error) {
+	if params == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(params)
+}
+
+// getRequestHeaders returns the request headers, per request headers take precedence over the base headers
+func (p *Provider) getRequestHeaders(body []byte, options *requestConfig) http.Header {
+	headers := http.Header{}
+
+	headers.Set("Content-Type", "application/json")
+	if !p.reuseConnections {
+		headers.Set("Connection", "close")
+	}
+
+	headers.Set("Accept-Encoding", gzipEncoding)
+
+	p.setBaseHeaders(headers)
+
+	if options != nil {
+		for key, value := range options.headers {
+			headers.Set(key, value)
+		}
+	}
+
+	if p.hmacSecret != nil {
+		headers.Set(HMACHeader, computeHMAC(body, p.hmacSecret))
+	}
+
+	return headers
+}
+
+// setBaseHeaders sets the user agent, request ID and base headers of the provider, shared by HTTP and WebSocket requests
+func (p *Provider) setBaseHeaders(headers http.Header) {
+	if p.userAgent != "" {
+		headers.Set("User-Agent", p.userAgent)
+	}
+
+	if p.requestIDFunc != nil {
+		headers.Set(p.requestIDHeader, p.requestIDFunc())
+	}
+
+	for key, value := range p.baseHeaders {
+		headers.Set(key, value)
+	}
+}
+
+func getTransportError(err error) error {
+	if errors.Is(err, ErrCertificatePinMismatch) {
+		return ErrCertificatePinMismatch
+	}
+
+	return err
+}
+
+// requestConfig represents per request settings of the provider requests
+// ctx bounds the request together with the request timeout
+type requestConfig struct {
+	compress bool
+	headers  map[string]string
+	ctx      context.Context
+}
+
+func (p *Provider) getRequestBody(body []byte, options *requestConfig) ([]byte, bool, error) {
+	compress := p.compress || (options != nil && options.compress)
+
+	if !compress || len(body) <= p.compressionThreshold {
+		return body, false, nil
+	}
+
+	compressedBody, err := gzipBody(body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return compressedBody, true, nil
+}
+
+func (p *Provider) newRequest(ctx context.Context, url string, body []byte, options *requestConfig) (*http.Request, error) {
+	headers := p.getRequestHeaders(body, options)
+
+	body, compressed, err := p.getRequestBody(body, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if compressed {
+		headers.Set("Content-Encoding", gzipEncoding)
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header = headers
+
+	return request, nil
+}
+
+func (p *Provider) doRequest(url string, body []byte, options *requestConfig) (*http.Response, error) {
+	ctx, cancel := p.getRequestContext(options)
+
+	ctx, requestErr := withRequestErrorRecorder(ctx)
+
+	request, err := p.newRequest(ctx, url, body, options)
+	if err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	if p.requestLogger != nil {
+		p.requestLogger.LogRequest(request.Method, url, body)
+	}
+
+	startTime := time.Now()
+
+	output, err := p.sendRequest(request, requestErr)
+	if err != nil {
+		err = getRequestContextError(ctx, options, err)
+	}
+
+	if output == nil {
+		cancel()
+	} else {
+		output.Body = &cancelOnCloseBody{ReadCloser: output.Body, cancel: cancel}
+	}
+
+	logErr := p.logResponse(output, time.Since(startTime))
+	if logErr != nil {
+		return nil, logErr
+	}
+
+	return output, err
+}
+
+func (p *Provider) sendRequest(request *http.Request, requestErr *error) (*http.Response, error) {
+	output, err := p.client.Do(request)
+	if err != nil && *requestErr != nil {
+		return nil, getTransportError(*requestErr)
+	}
+
+	if output != nil {
+		responseErr := p.prepareResponse(output)
+		if responseErr != nil {
+			utils.CloseOrLog(output.Body)
+
+			return nil, responseErr
+		}
+	}
+
+	return output, err
+}
+
+// prepareResponse decompresses the response body and enforces the max response size on the decompressed body
+func (p *Provider) prepareResponse(response *http.Response) error {
+	err := decompressResponse(response)
+	if err != nil {
+		return err
+	}
+
+	if p.maxResponseSize <= 0 {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, p.maxResponseSize+1))
+	if err != nil {
+		return err
+	}
+
+	if int64(len(body)) > p.maxResponseSize {
+		return &ResponseTooLargeError{Limit: p.maxResponseSize}
+	}
+
+	utils.CloseOrLog(response.Body)
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return nil
+}
+
 func (p *Provider) doPostRequest(rpcURL string, params any, route V1RPCRoute) (*http.Response, error) {
+	return p.doPostRequestWithConfig(rpcURL, params, route, nil)
+}
+
+func (p *Provider) doPostRequestWithConfig(rpcURL string, params any, route V1RPCRoute, options *requestConfig) (*http.Response, error) {
+	if p.optionErr != nil {
+		return nil, p.optionErr
+	}
+
 	finalRPCURL, err := p.getFinalRPCURL(rpcURL, route)
 	if err != nil {
 		return nil, err
 	}
 
-	output, err := p.client.PostWithURLJSONParams(fmt.Sprintf("%s%s", finalRPCURL, route), params, http.Header{})
+	body, err := getJSONBody(params)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := p.doRequest(fmt.Sprintf("%s%s", finalRPCURL, route), body, options)
 	if err != nil {
 		return nil, err
 	}
 
+	return checkResponseStatus(route, output)
+}
+
+func checkResponseStatus(route V1RPCRoute, output *http.Response) (*http.Response, error) {
 	if output.StatusCode == http.StatusBadRequest {
 		return output, returnRPCError(route, output.Body)
 	}
@@ -330,8 +626,7 @@ func (p *Provider) GetBlock(blockNumber int) (*GetBlockOutput, error) {
 	return &output, nil
 }
 
-// GetTransaction returns the transaction by the given transaction hash
-func (p *Provider) GetTransaction(transactionHash string, options *GetTransactionOptions) (*GetTransactionOutput, error) {
+func getTransactionParams(transactionHash string, options *GetTransactionOptions) map[string]any {
 	params := map[string]any{
 		"hash": transactionHash,
 	}
@@ -340,7 +635,12 @@ func (p *Provider) GetTransaction(transactionHash string, options *GetTransactio
 		params["prove"] = options.Prove
 	}
 
-	rawOutput, err := p.doPostRequest("", params, QueryTXRoute)
+	return params
+}
+
+// GetTransaction returns the transaction by the given transaction hash
+func (p *Provider) GetTransaction(transactionHash string, options *GetTransactionOptions) (*GetTransactionOutput, error) {
+	rawOutput, err := p.doPostRequest("", getTransactionParams(transactionHash, options), QueryTXRoute)
 
 	defer closeOrLog(rawOutput)
 
@@ -388,6 +688,45 @@ func (p *Provider) GetBlockHeight() (int, error) {
 	return output.Height, nil
 }
 
+// GetChains returns the IDs of the blockchains supported by the network, an empty slice when it supports none
+func (p *Provider) GetChains(ctx context.Context) ([]string, error) {
+	rawOutput, err := p.doPostRequestWithConfig("", map[string]int{"height": 0}, QuerySupportedChainsRoute, &requestConfig{ctx: ctx})
+
+	defer closeOrLog(rawOutput)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(rawOutput.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	chains := []string{}
+
+	err = json.Unmarshal(bodyBytes, &chains)
+	if err != nil {
+		return nil, err
+	}
+
+	if chains == nil {
+		return []string{}, nil
+	}
+
+	return chains, nil
+}
+
+// CheckHealth returns an error when the node at rpcURL does not answer its block height
+// It is a lightweight probe of a session node that does not spend relays
+func (p *Provider) CheckHealth(rpcURL string) error {
+	rawOutput, err := p.doPostRequest(rpcURL, nil, QueryHeightRoute)
+
+	defer closeOrLog(rawOutput)
+
+	return err
+}
+
 // GetAllParams returns the params at the specified height
 func (p *Provider) GetAllParams(options *GetAllParamsOptions) (*AllParams, error) {
 	var height int
@@ -420,9 +759,7 @@ func (p *Provider) GetAllParams(options *GetAllParamsOptions) (*AllParams, error
 	return &allParams, nil
 }
 
-// GetNodes returns a page of nodes known at the specified height and with options
-// empty options returns all validators, page < 1 returns the first page, per_page < 1 returns 10000 elements per page
-func (p *Provider) GetNodes(options *GetNodesOptions) (*GetNodesOutput, error) {
+func getNodesParams(options *GetNodesOptions) map[string]any {
 	params := map[string]any{}
 
 	if options != nil {
@@ -436,7 +773,13 @@ func (p *Provider) GetNodes(options *GetNodesOptions) (*GetNodesOutput, error) {
 		}
 	}
 
-	rawOutput, err := p.doPostRequest("", params, QueryNodesRoute)
+	return params
+}
+
+// GetNodes returns a page of nodes known at the specified height and with options
+// empty options returns all validators, page < 1 returns the first page, per_page < 1 returns 10000 elements per page
+func (p *Provider) GetNodes(options *GetNodesOptions) (*GetNodesOutput, error) {
+	rawOutput, err := p.doPostRequest("", getNodesParams(options), QueryNodesRoute)
 
 	defer closeOrLog(rawOutput)
 
@@ -492,9 +835,120 @@ func (p *Provider) GetNode(address string, options *GetNodeOptions) (*GetNodeOut
 	return &output, nil
 }
 
-// GetApps returns a page of applications known at the specified height and staking status
-// empty ("") staking_status returns all apps, page < 1 returns the first page, per_page < 1 returns 10000 elements per page
-func (p *Provider) GetApps(options *GetAppsOptions) (*GetAppsOutput, error) {
+// GetSigningInfo returns the signing info of the validator with the given address, height = 0 is used as latest
+func (p *Provider) GetSigningInfo(address string, options *GetSigningInfoOptions) (*SigningInfo, error) {
+	params := map[string]any{
+		"address": address,
+	}
+
+	if options != nil {
+		params["height"] = options.Height
+	}
+
+	rawOutput, err := p.doPostRequest("", params, QuerySigningInfoRoute)
+
+	defer closeOrLog(rawOutput)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(rawOutput.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	output := querySigningInfoOutput{}
+
+	err = json.Unmarshal(bodyBytes, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(output.Result) == 0 {
+		return nil, ErrNodeNotFound
+	}
+
+	return output.Result[0], nil
+}
+
+// GetNodeReceipts returns a page of the relay receipts submitted by the node at the specified height
+// height = 0 is used as latest, page < 1 returns the first page
+func (p *Provider) GetNodeReceipts(address string, options *GetNodeReceiptsOptions) (*GetNodeReceiptsOutput, error) {
+	params := map[string]any{
+		"address": address,
+	}
+
+	if options != nil {
+		params["height"] = options.Height
+		params["page"] = options.Page
+		params["per_page"] = options.PerPage
+	}
+
+	rawOutput, err := p.doPostRequest("", params, QueryNodeReceiptsRoute)
+
+	defer closeOrLog(rawOutput)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(rawOutput.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	output := GetNodeReceiptsOutput{}
+
+	err = json.Unmarshal(bodyBytes, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+// GetReceipt returns the receipt of the node for the given app session
+// receiptType must be RelayReceiptType or ChallengeReceiptType
+func (p *Provider) GetReceipt(address, appPubKey, chain string, sessionHeight int64, receiptType string) (*Receipt, error) {
+	if receiptType != RelayReceiptType && receiptType != ChallengeReceiptType {
+		return nil, ErrInvalidReceiptType
+	}
+
+	rawOutput, err := p.doPostRequest("", map[string]any{
+		"address":              address,
+		"app_pubkey":           appPubKey,
+		"blockchain":           chain,
+		"session_block_height": sessionHeight,
+		"receipt_type":         receiptType,
+	}, QueryNodeReceiptRoute)
+
+	defer closeOrLog(rawOutput)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(rawOutput.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	output := Receipt{}
+
+	err = json.Unmarshal(bodyBytes, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	if output.SessionHeader == nil {
+		return nil, ErrReceiptNotFound
+	}
+
+	return &output, nil
+}
+
+func getAppsParams(options *GetAppsOptions) map[string]any {
 	params := map[string]any{}
 
 	if options != nil {
@@ -507,7 +961,13 @@ func (p *Provider) GetApps(options *GetAppsOptions) (*GetAppsOutput, error) {
 		}
 	}
 
-	rawOutput, err := p.doPostRequest("", params, QueryAppsRoute)
+	return params
+}
+
+// GetApps returns a page of applications known at the specified height and staking status
+// empty ("") staking_status returns all apps, page < 1 returns the first page, per_page < 1 returns 10000 elements per page
+func (p *Provider) GetApps(options *GetAppsOptions) (*GetAppsOutput, error) {
+	rawOutput, err := p.doPostRequest("", getAppsParams(options), QueryAppsRoute)
 
 	defer closeOrLog(rawOutput)
 
@@ -665,12 +1125,17 @@ func (p *Provider) Dispatch(appPublicKey, chain string, options *DispatchRequest
 		return nil, err
 	}
 
+	if output.SessionKey == "" && output.Session != nil {
+		output.SessionKey = output.Session.Key
+	}
+
 	return &output, nil
 }
 
 // Relay does request to be relayed to a target blockchain
+// An empty 2xx body, sent by some servicers for notification style calls, returns an output with empty response
 func (p *Provider) Relay(rpcURL string, input *RelayInput, options *RelayRequestOptions) (*RelayOutput, error) {
-	rawOutput, reqErr := p.doPostRequest(rpcURL, input, ClientRelayRoute)
+	rawOutput, reqErr := p.doPostRequestWithConfig(rpcURL, input, ClientRelayRoute, getRelayRequestConfig(options))
 
 	defer closeOrLog(rawOutput)
 
@@ -687,10 +1152,98 @@ func (p *Provider) Relay(rpcURL string, input *RelayInput, options *RelayRequest
 		return nil, parseRelayErrorOutput(bodyBytes, input.Proof.ServicerPubKey)
 	}
 
-	return parseRelaySuccesfulOutput(bodyBytes)
+	output, err := parseRelaySuccesfulOutput(bodyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.checkRelayOutput(output, input, options)
+}
+
+func (p *Provider) checkRelayOutput(output *RelayOutput, input *RelayInput, options *RelayRequestOptions) (*RelayOutput, error) {
+	if input.Proof != nil {
+		output.RequestHash = input.Proof.RequestHash
+	}
+
+	if options == nil {
+		return output, nil
+	}
+
+	if options.VerifyResponse {
+		err := p.verifyRelayOutput(output, input)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.ResponseValidator != nil {
+		err := options.ResponseValidator([]byte(output.Response))
+		if err != nil {
+			return nil, &InvalidRelayResponseError{Err: err}
+		}
+	}
+
+	return output, nil
+}
+
+func (p *Provider) verifyRelayOutput(output *RelayOutput, input *RelayInput) error {
+	servicerPubKey := ""
+	if input.Proof != nil {
+		servicerPubKey = input.Proof.ServicerPubKey
+	}
+
+	return p.VerifyRelayResponse(output, servicerPubKey)
+}
+
+// PostRaw does a POST request with given body to the provider RPC URL plus path
+// and returns the raw response body and status code, non 2xx status codes are not returned as errors
+// It is an unsupported low level escape hatch for endpoints without typed support,
+// prefer the typed requests whenever they exist
+func (p *Provider) PostRaw(path string, body []byte, options *RequestOptions) ([]byte, int, error) {
+	if p.optionErr != nil {
+		return nil, 0, p.optionErr
+	}
+
+	var config *requestConfig
+	if options != nil {
+		config = &requestConfig{
+			compress: options.Compress,
+			headers:  options.Headers,
+		}
+	}
+
+	output, err := p.doRequest(fmt.Sprintf("%s%s", p.rpcURL, path), body, config)
+
+	defer closeOrLog(output)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(output.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return bodyBytes, output.StatusCode, nil
+}
+
+func getRelayRequestConfig(options *RelayRequestOptions) *requestConfig {
+	if options == nil {
+		return nil
+	}
+
+	return &requestConfig{
+		compress: options.Compress,
+		headers:  options.Headers,
+	}
 }
 
 func parseRelaySuccesfulOutput(bodyBytes []byte) (*RelayOutput, error) {
+	if len(bytes.TrimSpace(bodyBytes)) == 0 {
+		return &RelayOutput{}, nil
+	}
+
 	output := RelayOutput{}
 
 	err := json.Unmarshal(bodyBytes, &output)
@@ -1,12 +1,28 @@
 package provider
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"math/big"
 	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/require"
 	"github.com/vishruthsk/utils-go/mock-client"
@@ -23,6 +39,214 @@ func TestRelayError_ErrorInterface(t *testing.T) {
 	c.True(ok)
 }
 
+func TestProvider_WithHMACAuth(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"}, WithHMACAuth("ohana"))
+
+	var receivedBody []byte
+	var receivedHMAC string
+
+	httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryBalanceRoute),
+		func(req *http.Request) (*http.Response, error) {
+			receivedBody, _ = ioutil.ReadAll(req.Body)
+			receivedHMAC = req.Header.Get(HMACHeader)
+
+			return httpmock.NewStringResponse(http.StatusOK, `{"balance": 21}`), nil
+		})
+
+	balance, err := provider.GetBalance("pjog", nil)
+	c.NoError(err)
+	c.Equal(big.NewInt(21), balance)
+
+	c.Equal(`{"address":"pjog"}`, string(receivedBody))
+
+	mac := hmac.New(sha256.New, []byte("ohana"))
+	mac.Write([]byte(`{"address":"pjog"}`))
+
+	c.Equal(hex.EncodeToString(mac.Sum(nil)), receivedHMAC)
+}
+
+func TestProvider_WithBaseHeaders(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"},
+		WithBaseHeaders(map[string]string{"X-API-Key": "aog", "X-Org-ID": "pjog"}))
+
+	var receivedHeaders http.Header
+
+	httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", ClientRelayRoute),
+		func(req *http.Request) (*http.Response, error) {
+			receivedHeaders = req.Header
+
+			return httpmock.NewStringResponse(http.StatusOK, `{"response": "{}", "signature": "abf"}`), nil
+		})
+
+	relay, err := provider.Relay("https://dummy.com", &RelayInput{}, nil)
+	c.NoError(err)
+	c.NotEmpty(relay)
+	c.Equal("aog", receivedHeaders.Get("X-API-Key"))
+	c.Equal("pjog", receivedHeaders.Get("X-Org-ID"))
+	c.Equal("application/json", receivedHeaders.Get("Content-Type"))
+
+	relay, err = provider.Relay("https://dummy.com", &RelayInput{}, &RelayRequestOptions{
+		Headers: map[string]string{"X-API-Key": "ohana"},
+	})
+	c.NoError(err)
+	c.NotEmpty(relay)
+	c.Equal("ohana", receivedHeaders.Get("X-API-Key"))
+	c.Equal("pjog", receivedHeaders.Get("X-Org-ID"))
+}
+
+func TestProvider_WithCertificatePin(t *testing.T) {
+	c := require.New(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"height": 21}`))
+	}))
+	defer server.Close()
+
+	fingerprint := sha256.Sum256(server.Certificate().Raw)
+
+	provider := NewProvider(server.URL, nil, WithCertificatePin([]string{hex.EncodeToString(fingerprint[:])}))
+
+	height, err := provider.GetBlockHeight()
+	c.NoError(err)
+	c.Equal(21, height)
+
+	provider = NewProvider(server.URL, nil, WithCertificatePin([]string{strings.Repeat("ab", sha256.Size)}))
+
+	height, err = provider.GetBlockHeight()
+	c.Equal(ErrCertificatePinMismatch, err)
+	c.Empty(height)
+
+	provider = NewProvider(server.URL, nil, WithCertificatePin([]string{}))
+
+	height, err = provider.GetBlockHeight()
+	c.Error(err)
+	c.NotEqual(ErrCertificatePinMismatch, err)
+	c.Empty(height)
+}
+
+type countingRoundTripper struct {
+	calls int
+}
+
+func (r *countingRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	r.calls++
+
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+func TestProvider_WithHTTPClient(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := NewProvider("https://dummy.com", nil, WithHTTPClient(nil))
+
+	height, err := provider.GetBlockHeight()
+	c.Equal(ErrNilHTTPClient, err)
+	c.Empty(height)
+
+	roundTripper := &countingRoundTripper{}
+	provider = NewProvider("https://dummy.com", nil, WithHTTPClient(&http.Client{Transport: roundTripper}))
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryHeightRoute),
+		http.StatusOK, "samples/query_height.json")
+
+	height, err = provider.GetBlockHeight()
+	c.NoError(err)
+	c.Equal(21, height)
+	c.Equal(1, roundTripper.calls)
+
+	provider.UpdateRequestConfig(0, time.Second)
+
+	_, err = provider.GetBlockHeight()
+	c.NoError(err)
+	c.Equal(2, roundTripper.calls)
+
+	for _, options := range [][]ProviderOption{
+		{WithHTTPClient(&http.Client{Transport: roundTripper}), WithCertificatePin([]string{"aa:bb"})},
+		{WithCertificatePin([]string{"aa:bb"}), WithHTTPClient(&http.Client{Transport: roundTripper})},
+	} {
+		height, err = NewProvider("https://dummy.com", nil, options...).GetBlockHeight()
+		c.Equal(ErrCertificatePinWithHTTPClient, err)
+		c.Empty(height)
+	}
+
+	c.Equal(2, roundTripper.calls)
+}
+
+func TestProvider_WithRetry(t *testing.T) {
+	c := require.New(t)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"height": 21}`))
+	}))
+	defer server.Close()
+
+	provider := NewProvider(server.URL, nil, WithRetry(0, nil))
+
+	height, err := provider.GetBlockHeight()
+	c.Equal(ErrInvalidMaxAttempts, err)
+	c.Empty(height)
+
+	var attempts []int
+
+	roundTripper := &countingRoundTripper{}
+	provider = NewProvider(server.URL, nil, WithHTTPClient(&http.Client{Transport: roundTripper}),
+		WithRetry(3, func(attempt int) time.Duration {
+			attempts = append(attempts, attempt)
+
+			return time.Millisecond
+		}))
+
+	height, err = provider.GetBlockHeight()
+	c.NoError(err)
+	c.Equal(21, height)
+	c.Equal(3, calls)
+	c.Equal(3, roundTripper.calls)
+	c.Equal([]int{1, 2}, attempts)
+
+	calls = 0
+	provider = NewProvider(server.URL, nil, WithRetry(2, nil))
+
+	height, err = provider.GetBlockHeight()
+	c.Equal(Err5xxOnConnection, err)
+	c.Empty(height)
+	c.Equal(2, calls)
+
+	badRequestCalls := 0
+	badRequestServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		badRequestCalls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer badRequestServer.Close()
+
+	provider = NewProvider(badRequestServer.URL, nil, WithRetry(3, nil))
+
+	height, err = provider.GetBlockHeight()
+	c.Error(err)
+	c.Empty(height)
+	c.Equal(1, badRequestCalls)
+}
+
 func TestProvider_GetBalance(t *testing.T) {
 	c := require.New(t)
 
@@ -168,6 +392,46 @@ func TestProvider_GetBlock(t *testing.T) {
 	c.Empty(block)
 }
 
+func TestProvider_GetBlockResults(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryBlockResultsRoute), http.StatusOK, "samples/query_block_results.json")
+
+	results, err := provider.GetBlockResults(21)
+	c.NoError(err)
+	c.Equal(int64(21), results.Height)
+	c.Len(results.TxResults, 2)
+	c.Equal(int64(21000), results.TxResults[0].GasUsed)
+	c.Equal([]Event{
+		{Type: "message", Attributes: []Attribute{
+			{Key: "action", Value: "send"},
+			{Key: "sender", Value: "b50a6e20d3733fb89631ae32385b3c85c533c560"},
+		}},
+		{Type: "transfer", Attributes: []Attribute{
+			{Key: "recipient", Value: "e3b15b922be9cabbe3b4a3a788dec8dc95c8f3dc"},
+			{Key: "amount", Value: "1000uvip"},
+		}},
+	}, results.TxResults[0].Events)
+	c.Equal(5, results.TxResults[1].Code)
+	c.Equal("sdk", results.TxResults[1].Codespace)
+	c.Empty(results.TxResults[1].Events)
+	c.Equal([]Event{{Type: "proposer", Attributes: []Attribute{
+		{Key: "address", Value: "afaa802b1736396d31825f60487e4da030ea85bb"},
+	}}}, results.BeginBlockEvents)
+	c.Empty(results.EndBlockEvents)
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryBlockResultsRoute), http.StatusInternalServerError, "samples/query_block_results.json")
+
+	results, err = provider.GetBlockResults(21)
+	c.Equal(Err5xxOnConnection, err)
+	c.Empty(results)
+}
+
 func TestProvider_GetTransaction(t *testing.T) {
 	c := require.New(t)
 
@@ -210,6 +474,114 @@ func TestProvider_GetBlockHeight(t *testing.T) {
 	c.Empty(blockNumber)
 }
 
+func TestProvider_GetChains(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QuerySupportedChainsRoute),
+		http.StatusOK, "samples/query_supported_chains.json")
+
+	chains, err := provider.GetChains(context.Background())
+	c.NoError(err)
+	c.Equal([]string{"0001", "0021", "0040"}, chains)
+
+	for _, response := range []string{"[]", "null"} {
+		mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QuerySupportedChainsRoute),
+			http.StatusOK, response)
+
+		chains, err = provider.GetChains(context.Background())
+		c.NoError(err)
+		c.NotNil(chains)
+		c.Empty(chains)
+	}
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QuerySupportedChainsRoute),
+		http.StatusInternalServerError, "samples/query_supported_chains.json")
+
+	chains, err = provider.GetChains(context.Background())
+	c.Equal(Err5xxOnConnection, err)
+	c.Empty(chains)
+}
+
+func TestProvider_GetChainsContext(t *testing.T) {
+	c := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(`["0021"]`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	chains, err := NewProvider(server.URL, nil, WithRequestTimeout(time.Second)).GetChains(ctx)
+	c.Equal(context.DeadlineExceeded, err)
+	c.Empty(chains)
+
+	chains, err = NewProvider(server.URL, nil, WithRequestTimeout(20*time.Millisecond)).GetChains(context.Background())
+	c.Equal(ErrRequestTimeout, err)
+	c.Empty(chains)
+
+	chains, err = NewProvider(server.URL, nil).GetChains(context.Background())
+	c.NoError(err)
+	c.Equal([]string{"0021"}, chains)
+}
+
+func TestWatchBlockHeight(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
+
+	mock.AddMultipleMockedPlainResponses(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryHeightRoute),
+		[]int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusInternalServerError},
+		[]string{`{"height": 21}`, `{"height": 21}`, `{"height": 22}`, `{"height": 23}`})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	heights, errs := WatchBlockHeight(ctx, provider, 20*time.Millisecond)
+
+	c.Equal(int64(21), <-heights)
+	c.Equal(int64(22), <-heights)
+	c.Equal(Err5xxOnConnection, <-errs)
+
+	cancel()
+
+	c.Eventually(func() bool {
+		_, heightsOpen := <-heights
+		_, errsOpen := <-errs
+
+		return !heightsOpen && !errsOpen
+	}, time.Second, time.Millisecond)
+}
+
+func TestWatchBlockHeight_NonBlocking(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryHeightRoute), http.StatusOK, `{"height": 22}`)
+
+	heights := make(chan int64, 1)
+	errs := make(chan error, 1)
+
+	heights <- 21
+
+	c.Equal(int64(22), pollBlockHeight(provider, 21, heights, errs))
+	c.Equal(int64(22), <-heights)
+	c.Empty(errs)
+}
+
 func TestProvider_GetAllParams(t *testing.T) {
 	c := require.New(t)
 
@@ -249,6 +621,175 @@ func TestProvider_GetNodes(t *testing.T) {
 	c.Empty(nodes)
 }
 
+func TestProvider_GetSigningInfo(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QuerySigningInfoRoute), http.StatusOK, "samples/query_signing_info.json")
+
+	signingInfo, err := provider.GetSigningInfo("05d98fbedf63cd4b4e337ef488ec2ad7e5072cb2", &GetSigningInfoOptions{Height: 21})
+	c.NoError(err)
+	c.Equal(2109, signingInfo.IndexOffset)
+	c.Equal(21, signingInfo.MissedBlocksCounter)
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QuerySigningInfoRoute), http.StatusOK, "samples/query_signing_info_empty.json")
+
+	signingInfo, err = provider.GetSigningInfo("pjog", nil)
+	c.Equal(ErrNodeNotFound, err)
+	c.Empty(signingInfo)
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QuerySigningInfoRoute), http.StatusInternalServerError, "samples/query_signing_info.json")
+
+	signingInfo, err = provider.GetSigningInfo("pjog", nil)
+	c.Equal(Err5xxOnConnection, err)
+	c.Empty(signingInfo)
+}
+
+func TestProvider_GetValidatorSet(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
+
+	var pages []int
+
+	httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryValidatorsRoute),
+		func(req *http.Request) (*http.Response, error) {
+			params := struct {
+				Height int64 `json:"height"`
+				Page   int   `json:"page"`
+			}{}
+
+			err := json.NewDecoder(req.Body).Decode(&params)
+			c.NoError(err)
+			c.Equal(int64(21), params.Height)
+
+			pages = append(pages, params.Page)
+
+			body, err := ioutil.ReadFile(fmt.Sprintf("samples/query_validators_page_%d.json", params.Page))
+			c.NoError(err)
+
+			return httpmock.NewBytesResponse(http.StatusOK, body), nil
+		})
+
+	set, err := provider.GetValidatorSet(21)
+	c.NoError(err)
+	c.Equal([]int{1, 2}, pages)
+	c.Equal(int64(21), set.BlockHeight)
+	c.Len(set.Validators, 3)
+	c.Equal(int64(1000), set.TotalVotingPower)
+	c.Equal("1C2F6A1D2E3C4B5A69788796A5B4C3D2E1F0A1B2", set.Proposer.Address)
+	c.Equal("tendermint/PubKeyEd25519", set.Validators[2].PubKey.Type)
+
+	shares := set.VotingPowerShares()
+	c.Equal(0.6, shares["05D98FBEDF63CD4B4E337EF488EC2AD7E5072CB2"])
+	c.Equal(0.1, shares["A1B2C3D4E5F60718293A4B5C6D7E8F9011223344"])
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryValidatorsRoute), http.StatusInternalServerError, "samples/query_validators_page_1.json")
+
+	set, err = provider.GetValidatorSet(21)
+	c.Equal(Err5xxOnConnection, err)
+	c.Empty(set)
+}
+
+func TestProvider_GetConsensusState(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryConsensusStateRoute), http.StatusOK, "samples/query_consensus_state.json")
+
+	state, err := provider.GetConsensusState()
+	c.NoError(err)
+	c.Equal(int64(22), state.Height)
+	c.Equal(1, state.Round)
+	c.Equal(RoundStepPrevote, state.Step)
+	c.Equal("Prevote", state.Step.String())
+	c.Equal("1C2F6A1D2E3C4B5A69788796A5B4C3D2E1F0A1B2", state.Proposer.Address)
+	c.Len(state.Votes, 2)
+	c.Equal("BA{3:x__} 600/1000 = 0.60", state.Votes[1].PrevotesBitArray)
+
+	httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryConsensusStateRoute),
+		httpmock.NewStringResponder(http.StatusOK, `{"round_state": {"height/round/step": "22"}}`))
+
+	state, err = provider.GetConsensusState()
+	c.True(errors.Is(err, ErrMalformedRoundState))
+	c.Empty(state)
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryConsensusStateRoute), http.StatusInternalServerError, "samples/query_consensus_state.json")
+
+	state, err = provider.GetConsensusState()
+	c.Equal(Err5xxOnConnection, err)
+	c.Empty(state)
+}
+
+func TestProvider_GetNodeReceipts(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryNodeReceiptsRoute), http.StatusOK, "samples/query_node_receipts.json")
+
+	receipts, err := provider.GetNodeReceipts("05d98fbedf63cd4b4e337ef488ec2ad7e5072cb2", &GetNodeReceiptsOptions{Height: 21, Page: 1})
+	c.NoError(err)
+	c.Len(receipts.Result, 2)
+	c.Equal(int64(2109), receipts.Result[0].Total)
+	c.Equal(RelayEvidence, receipts.Result[0].EvidenceType)
+	c.Equal(ChallengeEvidence, receipts.Result[1].EvidenceType)
+	c.Equal(25, receipts.Result[1].SessionHeader.SessionHeight)
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryNodeReceiptsRoute), http.StatusInternalServerError, "samples/query_node_receipts.json")
+
+	receipts, err = provider.GetNodeReceipts("05d98fbedf63cd4b4e337ef488ec2ad7e5072cb2", nil)
+	c.Equal(Err5xxOnConnection, err)
+	c.Empty(receipts)
+}
+
+func TestProvider_GetReceipt(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
+
+	receipt, err := provider.GetReceipt("05d98fbedf63cd4b4e337ef488ec2ad7e5072cb2", "f6f1f166536c55d3ad7b1b2629f0bce8a0a3dbd455d576ccb235419bcbfed7fd", "0021", 21, "pjog")
+	c.Equal(ErrInvalidReceiptType, err)
+	c.Empty(receipt)
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryNodeReceiptRoute), http.StatusOK, "samples/query_node_receipt.json")
+
+	receipt, err = provider.GetReceipt("05d98fbedf63cd4b4e337ef488ec2ad7e5072cb2", "f6f1f166536c55d3ad7b1b2629f0bce8a0a3dbd455d576ccb235419bcbfed7fd", "0021", 21, RelayReceiptType)
+	c.NoError(err)
+	c.Equal(int64(2109), receipt.Total)
+	c.Equal(RelayEvidence, receipt.EvidenceType)
+	c.Equal("0021", receipt.SessionHeader.Chain)
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryNodeReceiptRoute), http.StatusOK, "{}")
+
+	receipt, err = provider.GetReceipt("05d98fbedf63cd4b4e337ef488ec2ad7e5072cb2", "f6f1f166536c55d3ad7b1b2629f0bce8a0a3dbd455d576ccb235419bcbfed7fd", "0021", 21, ChallengeReceiptType)
+	c.Equal(ErrReceiptNotFound, err)
+	c.Empty(receipt)
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryNodeReceiptRoute), http.StatusInternalServerError, "samples/query_node_receipt.json")
+
+	receipt, err = provider.GetReceipt("05d98fbedf63cd4b4e337ef488ec2ad7e5072cb2", "f6f1f166536c55d3ad7b1b2629f0bce8a0a3dbd455d576ccb235419bcbfed7fd", "0021", 21, RelayReceiptType)
+	c.Equal(Err5xxOnConnection, err)
+	c.Empty(receipt)
+}
+
 func TestProvider_GetApps(t *testing.T) {
 	c := require.New(t)
 
@@ -354,41 +895,112 @@ func TestProvider_GetAccounts(t *testing.T) {
 	c.Empty(account)
 }
 
-func TestProvider_Dispatch(t *testing.T) {
+func TestProvider_CheckSession(t *testing.T) {
 	c := require.New(t)
 
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
 
-	provider := &Provider{
-		rpcURL: "https://dummy.com",
-	}
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
 
-	dispatch, err := provider.Dispatch("pjog", "abcd", &DispatchRequestOptions{Height: 21})
-	c.Equal(ErrNoDispatchers, err)
-	c.Empty(dispatch)
+	status, err := provider.CheckSession(&Session{})
+	c.Equal(ErrNoSessionHeader, err)
+	c.Zero(status)
 
-	provider.dispatchers = []string{"https://dummy.com"}
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", ClientDispatchRoute), http.StatusOK, "samples/client_dispatch.json")
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryAllParamsRoute), http.StatusOK, "samples/query_allparams.json")
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryHeightRoute), http.StatusOK, `{"height": 4}`)
 
-	provider.UpdateRequestConfig(0, 5*time.Second)
+	dispatch, err := provider.Dispatch("f6f1f166536c55d3ad7b1b2629f0bce8a0a3dbd455d576ccb235419bcbfed7fd", "0001", nil)
+	c.NoError(err)
 
-	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", ClientDispatchRoute), http.StatusOK, "samples/client_dispatch.json")
+	session := dispatch.Session
+	session.Nodes[0], session.Nodes[1] = session.Nodes[1], session.Nodes[0]
 
-	dispatch, err = provider.Dispatch("pjog", "abcd", nil)
+	status, err = provider.CheckSession(session)
 	c.NoError(err)
-	c.NotEmpty(dispatch)
+	c.Equal(SessionValid, status)
+	c.Equal("valid", status.String())
 
-	provider.ResetRequestConfigToDefault()
+	valid, err := provider.IsSessionValid(session)
+	c.NoError(err)
+	c.True(valid)
 
-	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", ClientDispatchRoute), http.StatusInternalServerError, "samples/client_dispatch.json")
+	rolledOver := *session
+	rolledOver.Nodes = append([]*Node{{PublicKey: "PJOG"}}, session.Nodes[1:]...)
 
-	dispatch, err = provider.Dispatch("pjog", "abcd", &DispatchRequestOptions{Height: 21})
-	c.Equal(Err5xxOnConnection, err)
-	c.Empty(dispatch)
-}
+	status, err = provider.CheckSession(&rolledOver)
+	c.NoError(err)
+	c.Equal(SessionRolledOver, status)
 
-func TestProvider_Relay(t *testing.T) {
-	c := require.New(t)
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryHeightRoute), http.StatusOK, `{"height": 5}`)
+
+	status, err = provider.CheckSession(session)
+	c.NoError(err)
+	c.Equal(SessionExpired, status)
+
+	valid, err = provider.IsSessionValid(session)
+	c.NoError(err)
+	c.False(valid)
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryAllParamsRoute), http.StatusOK, `{"node_params": []}`)
+
+	status, err = provider.CheckSession(session)
+	c.Equal(ErrInvalidBlocksPerSession, err)
+	c.Zero(status)
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryHeightRoute), http.StatusInternalServerError, `{}`)
+
+	valid, err = provider.IsSessionValid(session)
+	c.Equal(Err5xxOnConnection, err)
+	c.False(valid)
+}
+
+func TestProvider_Dispatch(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := &Provider{
+		rpcURL: "https://dummy.com",
+	}
+
+	dispatch, err := provider.Dispatch("pjog", "abcd", &DispatchRequestOptions{Height: 21})
+	c.Equal(ErrNoDispatchers, err)
+	c.Empty(dispatch)
+
+	provider.dispatchers = []string{"https://dummy.com"}
+
+	provider.UpdateRequestConfig(0, 5*time.Second)
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", ClientDispatchRoute), http.StatusOK, "samples/client_dispatch.json")
+
+	dispatch, err = provider.Dispatch("pjog", "abcd", nil)
+	c.NoError(err)
+	c.NotEmpty(dispatch)
+	c.Equal("pY2rZUEkygFRUZ21iT0XiyQDkifIQC74IkoQN2YvisU=", dispatch.SessionKey)
+	c.Empty(dispatch.BlockHash)
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", ClientDispatchRoute), http.StatusOK,
+		`{"block_height": 1, "block_hash": "ABCD", "session_key": "OHANA", "session": {"key": "PJOG"}}`)
+
+	dispatch, err = provider.Dispatch("pjog", "abcd", nil)
+	c.NoError(err)
+	c.Equal("OHANA", dispatch.SessionKey)
+	c.Equal("ABCD", dispatch.BlockHash)
+
+	provider.ResetRequestConfigToDefault()
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", ClientDispatchRoute), http.StatusInternalServerError, "samples/client_dispatch.json")
+
+	dispatch, err = provider.Dispatch("pjog", "abcd", &DispatchRequestOptions{Height: 21})
+	c.Equal(Err5xxOnConnection, err)
+	c.Empty(dispatch)
+}
+
+func TestProvider_Relay(t *testing.T) {
+	c := require.New(t)
 
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
@@ -420,4 +1032,1048 @@ func TestProvider_Relay(t *testing.T) {
 	relay, err = provider.Relay("https://dummy.com", &RelayInput{}, nil)
 	c.Equal(ErrNonJSONResponse, err)
 	c.Empty(relay)
+
+	for _, body := range []string{"", " \n"} {
+		mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", ClientRelayRoute), http.StatusOK, body)
+
+		relay, err = provider.Relay("https://dummy.com", &RelayInput{Proof: &RelayProof{RequestHash: "abcd"}}, nil)
+		c.NoError(err)
+		c.Empty(relay.Response)
+		c.Empty(relay.Signature)
+		c.Equal("abcd", relay.RequestHash)
+	}
+}
+
+func TestProvider_RelayGzip(t *testing.T) {
+	c := require.New(t)
+
+	relayBody, err := ioutil.ReadFile("samples/client_relay.json")
+	c.NoError(err)
+
+	gzippedRelayBody, err := gzipBody(relayBody)
+	c.NoError(err)
+
+	var receivedEncoding, receivedData string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedEncoding = r.Header.Get("Content-Encoding")
+
+		body := io.Reader(r.Body)
+		if receivedEncoding == gzipEncoding {
+			body, _ = gzip.NewReader(r.Body)
+		}
+
+		input := RelayInput{}
+		_ = json.NewDecoder(body).Decode(&input)
+		receivedData = input.Payload.Data
+
+		w.Header().Set("Content-Encoding", gzipEncoding)
+		_, _ = w.Write(gzippedRelayBody)
+	}))
+	defer server.Close()
+
+	provider := NewProvider(server.URL, nil)
+	largeData := strings.Repeat("a", CompressionThreshold)
+
+	relay, err := provider.Relay(server.URL, &RelayInput{Payload: &RelayPayload{Data: largeData}}, nil)
+	c.NoError(err)
+	c.Equal(`{"id":3905054414,"jsonrpc":"2.0","result":"0xdd03e4"}`, relay.Response)
+	c.Empty(receivedEncoding)
+	c.Equal(largeData, receivedData)
+
+	relay, err = provider.Relay(server.URL, &RelayInput{Payload: &RelayPayload{Data: "small"}}, &RelayRequestOptions{Compress: true})
+	c.NoError(err)
+	c.NotEmpty(relay)
+	c.Empty(receivedEncoding)
+	c.Equal("small", receivedData)
+
+	relay, err = provider.Relay(server.URL, &RelayInput{Payload: &RelayPayload{Data: largeData}}, &RelayRequestOptions{Compress: true})
+	c.NoError(err)
+	c.Equal(`{"id":3905054414,"jsonrpc":"2.0","result":"0xdd03e4"}`, relay.Response)
+	c.Equal(gzipEncoding, receivedEncoding)
+	c.Equal(largeData, receivedData)
+
+	provider = NewProvider(server.URL, nil, WithCompression(true), WithCompressionThreshold(0))
+
+	relay, err = provider.Relay(server.URL, &RelayInput{Payload: &RelayPayload{Data: "small"}}, nil)
+	c.NoError(err)
+	c.NotEmpty(relay)
+	c.Equal(gzipEncoding, receivedEncoding)
+	c.Equal("small", receivedData)
+}
+
+func TestProvider_GzipResponse(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	heightBody, err := ioutil.ReadFile("samples/query_height.json")
+	c.NoError(err)
+
+	gzippedHeightBody, err := gzipBody(heightBody)
+	c.NoError(err)
+
+	httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryHeightRoute),
+		func(request *http.Request) (*http.Response, error) {
+			response := httpmock.NewBytesResponse(http.StatusOK, gzippedHeightBody)
+			response.Header.Set("Content-Encoding", gzipEncoding)
+
+			return response, nil
+		})
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
+
+	height, err := provider.GetBlockHeight()
+	c.NoError(err)
+	c.Equal(21, height)
+
+	httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", QueryHeightRoute),
+		func(request *http.Request) (*http.Response, error) {
+			response := httpmock.NewBytesResponse(http.StatusOK, heightBody)
+			response.Header.Set("Content-Encoding", gzipEncoding)
+
+			return response, nil
+		})
+
+	height, err = provider.GetBlockHeight()
+	c.Error(err)
+	c.Empty(height)
+}
+
+func TestProvider_VerifyRelayResponse(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	c.NoError(err)
+
+	servicerPubKey := hex.EncodeToString(publicKey)
+	response := `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`
+
+	responseHash, err := HashRelayResponse(response, "abcd")
+	c.NoError(err)
+
+	signature := hex.EncodeToString(ed25519.Sign(privateKey, responseHash))
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
+
+	err = provider.VerifyRelayResponse(&RelayOutput{Response: response, RequestHash: "abcd"}, servicerPubKey)
+	c.Equal(ErrMissingResponseSignature, err)
+
+	err = provider.VerifyRelayResponse(&RelayOutput{Response: response, Signature: signature, RequestHash: "abcd"}, servicerPubKey)
+	c.NoError(err)
+
+	err = provider.VerifyRelayResponse(&RelayOutput{Response: response, Signature: signature, RequestHash: "abce"}, servicerPubKey)
+	c.Equal(ErrResponseSignatureMismatch, err)
+
+	err = provider.VerifyRelayResponse(&RelayOutput{Response: response, Signature: signature, RequestHash: "abcd"}, "pjog")
+	c.Equal(ErrResponseSignatureMismatch, err)
+
+	input := &RelayInput{Proof: &RelayProof{RequestHash: "abcd", ServicerPubKey: servicerPubKey}}
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", ClientRelayRoute), http.StatusOK,
+		fmt.Sprintf(`{"response": %q, "signature": %q}`, response, signature))
+
+	relay, err := provider.Relay("https://dummy.com", input, &RelayRequestOptions{VerifyResponse: true})
+	c.NoError(err)
+	c.Equal("abcd", relay.RequestHash)
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", ClientRelayRoute), http.StatusOK,
+		fmt.Sprintf(`{"response": %q, "signature": %q}`, `{"id":1,"jsonrpc":"2.0","result":"0xdd03e5"}`, signature))
+
+	relay, err = provider.Relay("https://dummy.com", input, &RelayRequestOptions{VerifyResponse: true})
+	c.Equal(ErrResponseSignatureMismatch, err)
+	c.Empty(relay)
+
+	relay, err = provider.Relay("https://dummy.com", input, nil)
+	c.NoError(err)
+	c.NotEmpty(relay)
+}
+
+func TestProvider_WithCompression(t *testing.T) {
+	c := require.New(t)
+
+	relayBody, err := ioutil.ReadFile("samples/client_relay.json")
+	c.NoError(err)
+
+	gzippedRelayBody, err := gzipBody(relayBody)
+	c.NoError(err)
+
+	var receivedBody []byte
+	var receivedEncoding, receivedAcceptEncoding string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedBody, _ = ioutil.ReadAll(r.Body)
+		receivedEncoding = r.Header.Get("Content-Encoding")
+		receivedAcceptEncoding = r.Header.Get("Accept-Encoding")
+
+		w.Header().Set("Content-Encoding", gzipEncoding)
+		_, _ = w.Write(gzippedRelayBody)
+	}))
+	defer server.Close()
+
+	input := &RelayInput{Payload: &RelayPayload{Data: strings.Repeat(`{"method":"eth_blockNumber","id":1,"jsonrpc":"2.0"},`, 50)}}
+
+	uncompressedBody, err := json.Marshal(input)
+	c.NoError(err)
+
+	provider := NewProvider(server.URL, nil, WithCompression(true))
+
+	relay, err := provider.Relay(server.URL, input, nil)
+	c.NoError(err)
+	c.Equal(`{"id":3905054414,"jsonrpc":"2.0","result":"0xdd03e4"}`, relay.Response)
+	c.Equal(gzipEncoding, receivedEncoding)
+	c.Equal(gzipEncoding, receivedAcceptEncoding)
+	c.Less(len(receivedBody), len(uncompressedBody))
+
+	reader, err := gzip.NewReader(bytes.NewReader(receivedBody))
+	c.NoError(err)
+
+	decompressedBody, err := ioutil.ReadAll(reader)
+	c.NoError(err)
+	c.Equal(uncompressedBody, decompressedBody)
+
+	provider = NewProvider(server.URL, nil, WithCompression(false))
+
+	relay, err = provider.Relay(server.URL, input, nil)
+	c.NoError(err)
+	c.NotEmpty(relay)
+	c.Empty(receivedEncoding)
+	c.Equal(uncompressedBody, receivedBody)
+}
+
+func TestProvider_PostRaw(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
+
+	var receivedBody []byte
+	var receivedHeader string
+
+	httpmock.RegisterResponder(http.MethodPost, "https://dummy.com/v1/query/experimental",
+		func(req *http.Request) (*http.Response, error) {
+			receivedBody, _ = ioutil.ReadAll(req.Body)
+			receivedHeader = req.Header.Get("X-Custom")
+
+			return httpmock.NewStringResponse(http.StatusOK, `{"ohana": 21}`), nil
+		})
+
+	body, status, err := provider.PostRaw("/v1/query/experimental", []byte(`{"pjog": true}`),
+		&RequestOptions{Headers: map[string]string{"X-Custom": "aog"}})
+	c.NoError(err)
+	c.Equal(http.StatusOK, status)
+	c.Equal(`{"ohana": 21}`, string(body))
+	c.Equal(`{"pjog": true}`, string(receivedBody))
+	c.Equal("aog", receivedHeader)
+
+	mock.AddMockedResponse(http.MethodPost, "https://dummy.com/v1/query/experimental", http.StatusNotFound, "not found")
+
+	body, status, err = provider.PostRaw("/v1/query/experimental", nil, nil)
+	c.NoError(err)
+	c.Equal(http.StatusNotFound, status)
+	c.Equal("not found", string(body))
+
+	body, status, err = NewProvider("https://dummy.com", nil, WithHTTPClient(nil)).PostRaw("/v1/query/experimental", nil, nil)
+	c.Equal(ErrNilHTTPClient, err)
+	c.Zero(status)
+	c.Empty(body)
+}
+
+type requestLoggerMock struct {
+	requests  []string
+	responses []string
+}
+
+func (l *requestLoggerMock) LogRequest(method, url string, body []byte) {
+	l.requests = append(l.requests, fmt.Sprintf("%s %s %s", method, url, body))
+}
+
+func (l *requestLoggerMock) LogResponse(statusCode int, body []byte, duration time.Duration) {
+	l.responses = append(l.responses, fmt.Sprintf("%d %s", statusCode, body))
+}
+
+func TestProvider_WithRequestLogger(t *testing.T) {
+	c := require.New(t)
+
+	relayBody, err := ioutil.ReadFile("samples/client_relay.json")
+	c.NoError(err)
+
+	gzippedRelayBody, err := gzipBody(relayBody)
+	c.NoError(err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", gzipEncoding)
+		_, _ = w.Write(gzippedRelayBody)
+	}))
+	defer server.Close()
+
+	logger := &requestLoggerMock{}
+	provider := NewProvider(server.URL, nil, WithRequestLogger(logger), WithCompression(true))
+
+	input := &RelayInput{Payload: &RelayPayload{Data: strings.Repeat("a", CompressionThreshold)}}
+
+	uncompressedBody, err := json.Marshal(input)
+	c.NoError(err)
+
+	relay, err := provider.Relay(server.URL, input, nil)
+	c.NoError(err)
+	c.Equal(`{"id":3905054414,"jsonrpc":"2.0","result":"0xdd03e4"}`, relay.Response)
+
+	c.Equal([]string{fmt.Sprintf("POST %s%s %s", server.URL, ClientRelayRoute, uncompressedBody)}, logger.requests)
+	c.Equal([]string{fmt.Sprintf("200 %s", relayBody)}, logger.responses)
+
+	var output bytes.Buffer
+
+	stdoutLogger := &StdoutRequestLogger{writer: &output}
+	stdoutLogger.LogRequest(http.MethodPost, "https://dummy.com", []byte("{}"))
+	stdoutLogger.LogResponse(http.StatusOK, []byte("{}"), time.Second)
+
+	c.Equal("viper request: POST https://dummy.com {}\nviper response: 200 in 1s {}\n", output.String())
+}
+
+func TestProvider_WithMaxResponseSize(t *testing.T) {
+	c := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"height": 21}`))
+	}))
+	defer server.Close()
+
+	provider := NewProvider(server.URL, nil, WithMaxResponseSize(10))
+
+	height, err := provider.GetBlockHeight()
+	c.True(errors.Is(err, ErrResponseTooLarge))
+	c.Empty(height)
+
+	var tooLargeErr *ResponseTooLargeError
+
+	c.ErrorAs(err, &tooLargeErr)
+	c.Equal(int64(10), tooLargeErr.Limit)
+
+	provider = NewProvider(server.URL, nil, WithMaxResponseSize(int64(len(`{"height": 21}`))))
+
+	height, err = provider.GetBlockHeight()
+	c.NoError(err)
+	c.Equal(21, height)
+
+	provider = NewProvider(server.URL, nil, WithMaxResponseSize(0))
+
+	height, err = provider.GetBlockHeight()
+	c.NoError(err)
+	c.Equal(21, height)
+}
+
+func TestProvider_WithRequestTimeout(t *testing.T) {
+	c := require.New(t)
+
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(time.Second):
+		}
+
+		_, _ = w.Write([]byte(`{"height": 21}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	provider := NewProvider(server.URL, nil, WithRequestTimeout(50*time.Millisecond))
+	provider.UpdateRequestConfig(0, 2*time.Second)
+
+	startTime := time.Now()
+
+	height, err := provider.GetBlockHeight()
+	c.Equal(ErrRequestTimeout, err)
+	c.Empty(height)
+	c.Less(time.Since(startTime), 500*time.Millisecond)
+
+	provider = NewProvider(server.URL, nil, WithRequestTimeout(0))
+	provider.UpdateRequestConfig(0, 2*time.Second)
+
+	height, err = provider.GetBlockHeight()
+	c.NoError(err)
+	c.Equal(21, height)
+}
+
+func TestCachingProvider(t *testing.T) {
+	c := require.New(t)
+
+	samples := map[string]string{
+		string(QueryBlockRoute): "samples/query_block.json",
+		string(QueryTXRoute):    "samples/query_tx.json",
+		string(QueryNodesRoute): "samples/query_nodes.json",
+		string(QueryAppsRoute):  "samples/query_apps.json",
+	}
+
+	var fullResponses, notModifiedResponses int64
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt64(&notModifiedResponses, 1)
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		body, err := ioutil.ReadFile(samples[r.URL.Path])
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		atomic.AddInt64(&fullResponses, 1)
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	cachingProvider := NewCachingProvider(NewProvider(server.URL, nil), NewMemoryResponseCache(0))
+
+	for i := 0; i < 2; i++ {
+		block, err := cachingProvider.GetBlock(21)
+		c.NoError(err)
+		c.NotEmpty(block)
+
+		transaction, err := cachingProvider.GetTransaction("ABCD", &GetTransactionOptions{Prove: true})
+		c.NoError(err)
+		c.NotEmpty(transaction)
+
+		nodes, err := cachingProvider.GetNodes(&GetNodesOptions{Height: 21})
+		c.NoError(err)
+		c.NotEmpty(nodes.Result)
+
+		apps, err := cachingProvider.GetApps(&GetAppsOptions{Height: 21})
+		c.NoError(err)
+		c.NotEmpty(apps.Result)
+	}
+
+	c.Equal(int64(4), fullResponses)
+	c.Equal(int64(4), notModifiedResponses)
+
+	block, err := cachingProvider.GetBlock(22)
+	c.NoError(err)
+	c.NotEmpty(block)
+	c.Equal(int64(5), fullResponses)
+
+	transaction, err := cachingProvider.GetTransaction("ABCD", nil)
+	c.NoError(err)
+	c.NotEmpty(transaction)
+	c.Equal(int64(6), fullResponses)
+
+	failingProvider := NewCachingProvider(NewProvider(server.URL+"/dummy", nil), NewMemoryResponseCache(0))
+
+	block, err = failingProvider.GetBlock(21)
+	c.Equal(Err5xxOnConnection, err)
+	c.Empty(block)
+}
+
+func TestMemoryResponseCache(t *testing.T) {
+	c := require.New(t)
+
+	cache := NewMemoryResponseCache(2)
+
+	cache.Set("a", "1", []byte("ohana"))
+	cache.Set("b", "2", []byte("aog"))
+
+	body, etag, ok := cache.Get("a")
+	c.True(ok)
+	c.Equal("1", etag)
+	c.Equal([]byte("ohana"), body)
+
+	cache.Set("c", "3", []byte("pjog"))
+
+	_, _, ok = cache.Get("b")
+	c.False(ok)
+
+	cache.Set("a", "4", []byte("ohana2"))
+
+	body, etag, ok = cache.Get("a")
+	c.True(ok)
+	c.Equal("4", etag)
+	c.Equal([]byte("ohana2"), body)
+}
+
+func TestProvider_AtHeight(t *testing.T) {
+	c := require.New(t)
+
+	samples := map[string]string{
+		string(QueryHeightRoute):    "samples/query_height.json",
+		string(QueryBalanceRoute):   "samples/query_balance.json",
+		string(QueryNodesRoute):     "samples/query_nodes.json",
+		string(QueryAllParamsRoute): "samples/query_allparams.json",
+		string(QueryBlockRoute):     "samples/query_block.json",
+	}
+
+	heights := make(chan int, 10)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := struct {
+			Height int `json:"height"`
+		}{}
+
+		_ = json.NewDecoder(r.Body).Decode(&params)
+
+		if r.URL.Path != string(QueryHeightRoute) {
+			heights <- params.Height
+		}
+
+		body, err := ioutil.ReadFile(samples[r.URL.Path])
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	pinned := NewProvider(server.URL, nil).AtHeight(0)
+	c.Equal(int64(21), pinned.Height())
+
+	balance, err := pinned.GetBalance("ABCD", nil)
+	c.NoError(err)
+	c.NotEmpty(balance)
+	c.Equal(21, <-heights)
+
+	options := &GetNodesOptions{Height: 5}
+
+	nodes, err := pinned.GetNodes(options)
+	c.NoError(err)
+	c.NotEmpty(nodes)
+	c.Equal(5, <-heights)
+
+	params, err := pinned.GetAllParams(&GetAllParamsOptions{})
+	c.NoError(err)
+	c.NotEmpty(params)
+	c.Equal(21, <-heights)
+
+	block, err := pinned.GetBlock()
+	c.NoError(err)
+	c.NotEmpty(block)
+	c.Equal(21, <-heights)
+
+	pinned = NewProvider(server.URL, nil).AtHeight(7)
+	c.Equal(int64(7), pinned.Height())
+
+	nodes, err = pinned.GetNodes(nil)
+	c.NoError(err)
+	c.NotEmpty(nodes)
+	c.Equal(7, <-heights)
+
+	pinned = NewProvider(server.URL+"/dummy", nil).AtHeight(0)
+
+	balance, err = pinned.GetBalance("ABCD", nil)
+	c.Equal(Err5xxOnConnection, err)
+	c.Empty(balance)
+}
+
+func TestProvider_RelayResponseValidator(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", ClientRelayRoute), http.StatusOK, "samples/client_relay.json")
+
+	errNoResult := errors.New("no result")
+
+	var validatedResponse string
+
+	options := &RelayRequestOptions{
+		ResponseValidator: func(response []byte) error {
+			validatedResponse = string(response)
+
+			if !strings.Contains(validatedResponse, `"result"`) {
+				return errNoResult
+			}
+
+			return nil
+		},
+	}
+
+	relay, err := provider.Relay("https://dummy.com", &RelayInput{}, options)
+	c.NoError(err)
+	c.Equal(relay.Response, validatedResponse)
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", ClientRelayRoute), http.StatusOK,
+		`{"response": "{\"id\":1,\"jsonrpc\":\"2.0\",\"error\":{}}", "signature": "abf"}`)
+
+	relay, err = provider.Relay("https://dummy.com", &RelayInput{}, options)
+	c.True(errors.Is(err, ErrInvalidRelayResponse))
+	c.Empty(relay)
+
+	var invalidResponseErr *InvalidRelayResponseError
+
+	c.ErrorAs(err, &invalidResponseErr)
+	c.Equal(errNoResult, invalidResponseErr.Err)
+}
+
+func newBlockEventMessage(height int) []byte {
+	return []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":{"query":"tm.event='NewBlock'",`+
+		`"data":{"type":"tendermint/event/NewBlock","value":{"block":{"header":{"height":"%d"}}}}}}`, height))
+}
+
+// acceptWebSocket upgrades the request to a server side WebSocket connection
+func acceptWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wsConn{conn: conn}, nil
+}
+
+// closeWebSocket sends a close frame with given code and reason, WebSocketCloseNoStatus sends a close frame without code
+func closeWebSocket(conn *wsConn, code int, reason string) {
+	_ = conn.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(time.Second))
+}
+
+func TestProvider_SubscribeNewBlocks(t *testing.T) {
+	c := require.New(t)
+
+	var connections int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/websocket" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		conn, err := acceptWebSocket(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		message, err := conn.readMessage()
+		if err != nil || !bytes.Equal(message, newBlockSubscriptionRequest) {
+			return
+		}
+
+		_ = conn.writeMessage([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+
+		connection := atomic.AddInt32(&connections, 1)
+		_ = conn.writeMessage(newBlockEventMessage(int(connection)))
+
+		if connection == 1 {
+			closeWebSocket(conn, WebSocketCloseNoStatus, "")
+
+			return
+		}
+
+		for err == nil {
+			_, err = conn.readMessage()
+		}
+	}))
+	defer server.Close()
+
+	_, _, err := NewProvider(server.URL, nil, WithWebSocketURL("ws"+strings.TrimPrefix(server.URL, "http")+"/other")).
+		SubscribeNewBlocks(context.Background())
+	c.Equal(ErrWebSocketHandshake, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	blocks, errs, err := NewProvider(server.URL, nil).SubscribeNewBlocks(ctx)
+	c.NoError(err)
+
+	for _, height := range []string{"1", "2"} {
+		select {
+		case block := <-blocks:
+			c.Equal(height, block.Header.Height)
+		case <-time.After(5 * time.Second):
+			c.FailNow("block not received")
+		}
+	}
+
+	err = <-errs
+	c.True(errors.Is(err, ErrWebSocketClosed))
+
+	var closeErr *WebSocketCloseError
+	c.ErrorAs(err, &closeErr)
+	c.Equal(WebSocketCloseNoStatus, closeErr.Code)
+
+	cancel()
+
+	for range blocks {
+	}
+
+	_, ok := <-errs
+	c.False(ok)
+}
+
+func TestProvider_SubscribeNewBlocksOptions(t *testing.T) {
+	c := require.New(t)
+
+	var receivedHeaders http.Header
+
+	var receivedHeadersMutex sync.Mutex
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHeadersMutex.Lock()
+		receivedHeaders = r.Header
+		receivedHeadersMutex.Unlock()
+
+		conn, err := acceptWebSocket(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, err = conn.readMessage()
+		if err != nil {
+			return
+		}
+
+		_ = conn.writeMessage(newBlockEventMessage(21))
+
+		for err == nil {
+			_, err = conn.readMessage()
+		}
+	}))
+	defer server.Close()
+
+	fingerprint := sha256.Sum256(server.Certificate().Raw)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	provider := NewProvider(server.URL, nil,
+		WithCertificatePin([]string{hex.EncodeToString(fingerprint[:])}),
+		WithUserAgent("indexer/1.0"),
+		WithBaseHeaders(map[string]string{"X-Api-Key": "ohana", "Upgrade": "h2c"}))
+
+	blocks, _, err := provider.SubscribeNewBlocks(ctx)
+	c.NoError(err)
+
+	select {
+	case block := <-blocks:
+		c.Equal("21", block.Header.Height)
+	case <-time.After(5 * time.Second):
+		c.FailNow("block not received")
+	}
+
+	receivedHeadersMutex.Lock()
+	c.Equal("indexer/1.0", receivedHeaders.Get("User-Agent"))
+	c.Equal("ohana", receivedHeaders.Get("X-Api-Key"))
+	c.Equal("websocket", receivedHeaders.Get("Upgrade"))
+	c.Empty(receivedHeaders.Get(HMACHeader))
+	receivedHeadersMutex.Unlock()
+
+	_, _, err = NewProvider(server.URL, nil, WithCertificatePin([]string{strings.Repeat("ab", sha256.Size)})).
+		SubscribeNewBlocks(ctx)
+	c.Equal(ErrCertificatePinMismatch, err)
+
+	blocks, _, err = NewProvider(server.URL, nil, WithHMACAuth("secret"),
+		WithCertificatePin([]string{hex.EncodeToString(fingerprint[:])})).SubscribeNewBlocks(ctx)
+	c.NoError(err)
+
+	select {
+	case block := <-blocks:
+		c.Equal("21", block.Header.Height)
+	case <-time.After(5 * time.Second):
+		c.FailNow("block not received")
+	}
+
+	receivedHeadersMutex.Lock()
+	c.Equal(computeHMAC(nil, []byte("secret")), receivedHeaders.Get(HMACHeader))
+	receivedHeadersMutex.Unlock()
+
+	err = NewProvider(server.URL, nil, WithHTTPClient(server.Client())).
+		RelaySubscribe(ctx, server.URL, &RelayInput{}, func(message []byte) {})
+	c.True(errors.Is(err, ErrWebSocketUnsupportedOption))
+}
+
+func TestProvider_WithUserAgentAndRequestID(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var receivedHeaders []http.Header
+
+	httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", ClientRelayRoute),
+		func(req *http.Request) (*http.Response, error) {
+			receivedHeaders = append(receivedHeaders, req.Header)
+
+			return httpmock.NewStringResponse(http.StatusOK, `{"response": "{}", "signature": "abf"}`), nil
+		})
+
+	var requestID int32
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"},
+		WithUserAgent("indexer/1.0"),
+		WithRequestID("", func() string {
+			return fmt.Sprintf("request-%d", atomic.AddInt32(&requestID, 1))
+		}))
+
+	for i := 0; i < 2; i++ {
+		relay, err := provider.Relay("https://dummy.com", &RelayInput{}, nil)
+		c.NoError(err)
+		c.NotEmpty(relay)
+	}
+
+	c.Len(receivedHeaders, 2)
+	c.Equal("indexer/1.0", receivedHeaders[0].Get("User-Agent"))
+	c.Equal("request-1", receivedHeaders[0].Get(DefaultRequestIDHeader))
+	c.Equal("request-2", receivedHeaders[1].Get(DefaultRequestIDHeader))
+
+	provider = NewProvider("https://dummy.com", []string{"https://dummy.com"},
+		WithRequestID("X-Correlation-ID", func() string { return "aog" }))
+
+	_, err := provider.Relay("https://dummy.com", &RelayInput{}, nil)
+	c.NoError(err)
+	c.Equal("aog", receivedHeaders[2].Get("X-Correlation-ID"))
+	c.Empty(receivedHeaders[2].Get(DefaultRequestIDHeader))
+	c.NotEqual("indexer/1.0", receivedHeaders[2].Get("User-Agent"))
+
+	_, err = NewProvider("https://dummy.com", nil, WithRequestID("", nil)).Relay("https://dummy.com", &RelayInput{}, nil)
+	c.Equal(ErrNoRequestIDFunc, err)
+}
+
+func TestProvider_RelaySubscribe(t *testing.T) {
+	c := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != string(ClientRelayWebSocketRoute) {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		conn, err := acceptWebSocket(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		message, err := conn.readMessage()
+		if err != nil {
+			return
+		}
+
+		_ = conn.writeMessage(message)
+		_ = conn.writeMessage([]byte(`{"result":"0x21"}`))
+
+		if r.URL.Query().Get("drop") != "" {
+			return
+		}
+
+		if r.URL.Query().Get("keep") == "" {
+			closeWebSocket(conn, websocket.CloseNormalClosure, "bye")
+
+			return
+		}
+
+		for err == nil {
+			_, err = conn.readMessage()
+		}
+	}))
+	defer server.Close()
+
+	input := &RelayInput{
+		Payload: &RelayPayload{Data: `{"method":"eth_subscribe"}`},
+		Proof:   &RelayProof{ServicerPubKey: "AOG"},
+	}
+
+	expectedBody, err := json.Marshal(input)
+	c.NoError(err)
+
+	provider := NewProvider("https://dummy.com", []string{"https://dummy.com"})
+
+	var messages [][]byte
+
+	err = provider.RelaySubscribe(context.Background(), server.URL, input, func(message []byte) {
+		messages = append(messages, message)
+	})
+	c.True(errors.Is(err, ErrWebSocketClosed))
+
+	var closeErr *WebSocketCloseError
+	c.ErrorAs(err, &closeErr)
+	c.Equal(&WebSocketCloseError{Code: 1000, Reason: "bye"}, closeErr)
+	c.Equal([][]byte{expectedBody, []byte(`{"result":"0x21"}`)}, messages)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	messages = nil
+
+	err = provider.RelaySubscribe(ctx, server.URL+"?keep=true", input, func(message []byte) {
+		messages = append(messages, message)
+		if len(messages) == 2 {
+			cancel()
+		}
+	})
+	c.Equal(context.Canceled, err)
+	c.Len(messages, 2)
+
+	messages = nil
+
+	err = provider.RelaySubscribe(context.Background(), server.URL+"?drop=true", input, func(message []byte) {
+		messages = append(messages, message)
+	})
+	c.True(errors.Is(err, ErrWebSocketConnectionLost))
+	c.False(errors.Is(err, ErrWebSocketClosed))
+	c.Len(messages, 2)
+
+	err = provider.RelaySubscribe(context.Background(), "ftp://dummy.com", input, func(message []byte) {})
+	c.Equal(ErrInvalidWebSocketURL, err)
+}
+
+func TestRelayOutput_AsJSONRPC(t *testing.T) {
+	c := require.New(t)
+
+	output := &RelayOutput{Response: `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`}
+
+	response, err := output.AsJSONRPC()
+	c.NoError(err)
+	c.Equal("2.0", response.JSONRPC)
+	c.Equal(json.RawMessage("1"), response.ID)
+	c.NoError(response.Err())
+
+	var blockNumber string
+
+	c.NoError(response.Decode(&blockNumber))
+	c.Equal("0xdd03e4", blockNumber)
+
+	output.Response = `{"id":2,"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found"}}`
+
+	response, err = output.AsJSONRPC()
+	c.NoError(err)
+	c.Equal(json.RawMessage("2"), response.ID)
+	c.True(errors.Is(response.Err(), ErrJSONRPC))
+	c.Equal(response.Err(), response.Decode(&blockNumber))
+
+	var rpcErr *JSONRPCError
+
+	c.ErrorAs(response.Err(), &rpcErr)
+	c.Equal(-32601, rpcErr.Code)
+	c.Equal("method not found", rpcErr.Message)
+
+	output.Response = `{"id":3,"jsonrpc":"2.0"}`
+
+	response, err = output.AsJSONRPC()
+	c.NoError(err)
+	c.Equal(ErrNoJSONRPCResult, response.Decode(&blockNumber))
+
+	output.Response = `{"id":"abc","jsonrpc":"2.0","result":"0x1"}`
+
+	response, err = output.AsJSONRPC()
+	c.NoError(err)
+	c.Equal(json.RawMessage(`"abc"`), response.ID)
+	c.NoError(response.Decode(&blockNumber))
+	c.Equal("0x1", blockNumber)
+
+	output.Response = `{"id":null,"jsonrpc":"2.0","error":{"code":-32700,"message":"parse error"}}`
+
+	response, err = output.AsJSONRPC()
+	c.NoError(err)
+	c.Equal(json.RawMessage("null"), response.ID)
+	c.True(errors.Is(response.Err(), ErrJSONRPC))
+
+	output.Response = "not json"
+
+	response, err = output.AsJSONRPC()
+	c.Equal(ErrNonJSONResponse, err)
+	c.Nil(response)
+}
+
+func TestProvider_Stats(t *testing.T) {
+	c := require.New(t)
+
+	heightBody, err := ioutil.ReadFile("samples/query_height.json")
+	c.NoError(err)
+
+	statusCode := http.StatusOK
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		_, _ = w.Write(heightBody)
+	}))
+
+	provider := NewProvider(server.URL, []string{server.URL}, WithConnectionReuse(true))
+	c.Equal(ProviderStats{}, provider.Stats())
+
+	for i := 0; i < 3; i++ {
+		blockNumber, err := provider.GetBlockHeight()
+		c.NoError(err)
+		c.Equal(21, blockNumber)
+	}
+
+	stats := provider.Stats()
+	c.Equal(int64(1), stats.NewConnections)
+	c.Equal(int64(2), stats.ReusedConnections)
+
+	statusCode = http.StatusBadRequest
+
+	_, err = provider.GetBlockHeight()
+	c.Error(err)
+
+	statusCode = http.StatusInternalServerError
+
+	_, err = provider.GetBlockHeight()
+	c.Equal(Err5xxOnConnection, err)
+
+	statusCode = http.StatusNotModified
+
+	_, err = provider.GetBlockHeight()
+	c.Equal(ErrUnexpectedCodeOnConnection, err)
+
+	server.Close()
+
+	_, err = provider.GetBlockHeight()
+	c.Error(err)
+
+	stats = provider.Stats()
+	c.Equal(int64(7), stats.TotalRequests)
+	c.Equal(int64(3), stats.Responses2xx)
+	c.Equal(int64(1), stats.Responses4xx)
+	c.Equal(int64(1), stats.Responses5xx)
+	c.Equal(int64(1), stats.OtherResponses)
+	c.Equal(int64(1), stats.ConnectionErrors)
+	c.Zero(stats.InFlight)
+	c.Equal(stats.TotalRequests, stats.Responses2xx+stats.Responses4xx+stats.Responses5xx+
+		stats.OtherResponses+stats.ConnectionErrors)
+	c.Equal(int64(6), stats.NewConnections+stats.ReusedConnections)
+
+	provider.ResetStats()
+	c.Equal(ProviderStats{}, provider.Stats())
+
+	c.Equal(ProviderStats{}, (&Provider{}).Stats())
+
+	statusCode = http.StatusOK
+
+	var connectionHeader string
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connectionHeader = r.Header.Get("Connection")
+		_, _ = w.Write(heightBody)
+	}))
+	defer server.Close()
+
+	provider = NewProvider(server.URL, []string{server.URL})
+
+	for i := 0; i < 3; i++ {
+		_, err = provider.GetBlockHeight()
+		c.NoError(err)
+	}
+
+	stats = provider.Stats()
+	c.Equal("close", connectionHeader)
+	c.Equal(int64(3), stats.NewConnections)
+	c.Zero(stats.ReusedConnections)
 }
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"errors"
 	"fmt"
 )
 
@@ -12,9 +13,26 @@ type RelayInput struct {
 }
 
 // RelayOutput represents the Relay RPC output
+// RequestHash is not part of the RPC output, it is the hash of the relayed request needed to verify the signature
 type RelayOutput struct {
-	Response  string `json:"response"`
-	Signature string `json:"signature"`
+	Response    string `json:"response"`
+	Signature   string `json:"signature"`
+	RequestHash string `json:"-"`
+}
+
+// ChallengeInput represents the input of a challenge of a minority relay response
+// Address is the address of the reporter and must be set by the caller before submitting it
+type ChallengeInput struct {
+	MajorityResponses []*ChallengeResponse `json:"majority_responses"`
+	MinorityResponse  *ChallengeResponse   `json:"minority_response"`
+	Address           string               `json:"address"`
+}
+
+// ChallengeResponse represents a relay response signed by a servicer with the proof it answered
+type ChallengeResponse struct {
+	Signature string      `json:"signature"`
+	Payload   string      `json:"payload"`
+	Proof     *RelayProof `json:"proof"`
 }
 
 // RelayMeta represents metadata of a relay
@@ -22,7 +40,7 @@ type RelayMeta struct {
 	BlockHeight int `json:"block_height"`
 }
 
-// RelayHeaders map of relay headers
+// RelayHeaders map of relay headers, encoded to JSON with sorted keys so request hashes are stable
 type RelayHeaders map[string]string
 
 // RelayPayload represents payload of a relay
@@ -106,8 +124,8 @@ const (
 
 // IsErrorCode returns if error has the same relay error code as input
 func IsErrorCode(code RelayErrorCode, err error) bool {
-	castedErr, ok := err.(*RelayError)
-	if !ok {
+	var castedErr *RelayError
+	if !errors.As(err, &castedErr) {
 		return false
 	}
 
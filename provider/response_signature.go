@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	// ErrMissingResponseSignature error when relay output has no signature
+	ErrMissingResponseSignature = errors.New("missing response signature")
+	// ErrResponseSignatureMismatch error when relay output is not signed by the servicer
+	ErrResponseSignatureMismatch = errors.New("response signature mismatch")
+)
+
+// Order of fields matters for signature
+type relayResponseForSignature struct {
+	Signature   string `json:"signature"`
+	Response    string `json:"response"`
+	RequestHash string `json:"request_hash"`
+}
+
+// HashRelayResponse returns the hash signed by the servicer for a relay response
+func HashRelayResponse(response, requestHash string) ([]byte, error) {
+	marshaledResponse, err := json.Marshal(&relayResponseForSignature{
+		Signature:   "",
+		Response:    response,
+		RequestHash: requestHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hasher := sha3.New256()
+
+	_, err = hasher.Write(marshaledResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return hasher.Sum(nil), nil
+}
+
+// VerifyRelayResponse verifies the relay output is signed by the servicer with given public key
+// output.RequestHash is set by Relay, outputs built by other means must set it before verifying
+func (p *Provider) VerifyRelayResponse(output *RelayOutput, servicerPublicKey string) error {
+	if output.Signature == "" {
+		return ErrMissingResponseSignature
+	}
+
+	publicKey, err := hex.DecodeString(servicerPublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return ErrResponseSignatureMismatch
+	}
+
+	signature, err := hex.DecodeString(output.Signature)
+	if err != nil {
+		return ErrResponseSignatureMismatch
+	}
+
+	responseHash, err := HashRelayResponse(output.Response, output.RequestHash)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(publicKey, responseHash, signature) {
+		return ErrResponseSignatureMismatch
+	}
+
+	return nil
+}
@@ -46,43 +46,65 @@ type GetNodesOutput struct {
 	TotalPages int     `json:"total_pages"`
 }
 
-// GetBlockOutput represents output for GetBlock request
-type GetBlockOutput struct {
-	Block struct {
-		Data struct {
-			Txs []string `json:"txs"`
-		} `json:"data"`
-		Evidence struct {
-			Evidence []any `json:"evidence"`
-		} `json:"evidence"`
-		Header struct {
-			AppHash       string `json:"app_hash"`
-			ChainID       string `json:"chain_id"`
-			ConsensusHash string `json:"consensus_hash"`
-			DataHash      string `json:"data_hash"`
-			EvidenceHash  string `json:"evidence_hash"`
-			Height        string `json:"height"`
-			LastBlockID   struct {
+// SigningInfo represents the missed blocks tracking info of a validator
+type SigningInfo struct {
+	Address             string    `json:"address"`
+	StartHeight         int       `json:"start_height"`
+	IndexOffset         int       `json:"index_offset"`
+	JailedUntil         time.Time `json:"jailed_until"`
+	MissedBlocksCounter int       `json:"missed_blocks_counter"`
+}
+
+type querySigningInfoOutput struct {
+	Result     []*SigningInfo `json:"result"`
+	Page       int            `json:"page"`
+	TotalPages int            `json:"total_pages"`
+}
+
+// Block represents a block of the Viper chain
+type Block struct {
+	Data struct {
+		Txs []string `json:"txs"`
+	} `json:"data"`
+	Evidence struct {
+		Evidence []any `json:"evidence"`
+	} `json:"evidence"`
+	Header struct {
+		AppHash       string `json:"app_hash"`
+		ChainID       string `json:"chain_id"`
+		ConsensusHash string `json:"consensus_hash"`
+		DataHash      string `json:"data_hash"`
+		EvidenceHash  string `json:"evidence_hash"`
+		Height        string `json:"height"`
+		LastBlockID   struct {
+			Hash  string `json:"hash"`
+			Parts struct {
 				Hash  string `json:"hash"`
-				Parts struct {
-					Hash  string `json:"hash"`
-					Total string `json:"total"`
-				} `json:"parts"`
-			} `json:"last_block_id"`
-			LastCommitHash     string    `json:"last_commit_hash"`
-			LastResultsHash    string    `json:"last_results_hash"`
-			NextValidatorsHash string    `json:"next_validators_hash"`
-			NumTxs             string    `json:"num_txs"`
-			ProposerAddress    string    `json:"proposer_address"`
-			Time               time.Time `json:"time"`
-			TotalTxs           string    `json:"total_txs"`
-			ValidatorsHash     string    `json:"validators_hash"`
-			Version            struct {
-				App   string `json:"app"`
-				Block string `json:"block"`
-			} `json:"version"`
-		} `json:"header"`
-		LastCommit struct {
+				Total string `json:"total"`
+			} `json:"parts"`
+		} `json:"last_block_id"`
+		LastCommitHash     string    `json:"last_commit_hash"`
+		LastResultsHash    string    `json:"last_results_hash"`
+		NextValidatorsHash string    `json:"next_validators_hash"`
+		NumTxs             string    `json:"num_txs"`
+		ProposerAddress    string    `json:"proposer_address"`
+		Time               time.Time `json:"time"`
+		TotalTxs           string    `json:"total_txs"`
+		ValidatorsHash     string    `json:"validators_hash"`
+		Version            struct {
+			App   string `json:"app"`
+			Block string `json:"block"`
+		} `json:"version"`
+	} `json:"header"`
+	LastCommit struct {
+		BlockID struct {
+			Hash  string `json:"hash"`
+			Parts struct {
+				Hash  string `json:"hash"`
+				Total string `json:"total"`
+			} `json:"parts"`
+		} `json:"block_id"`
+		Precommits []struct {
 			BlockID struct {
 				Hash  string `json:"hash"`
 				Parts struct {
@@ -90,24 +112,20 @@ type GetBlockOutput struct {
 					Total string `json:"total"`
 				} `json:"parts"`
 			} `json:"block_id"`
-			Precommits []struct {
-				BlockID struct {
-					Hash  string `json:"hash"`
-					Parts struct {
-						Hash  string `json:"hash"`
-						Total string `json:"total"`
-					} `json:"parts"`
-				} `json:"block_id"`
-				Height           string    `json:"height"`
-				Round            string    `json:"round"`
-				Signature        string    `json:"signature"`
-				Timestamp        time.Time `json:"timestamp"`
-				Type             int       `json:"type"`
-				ValidatorAddress string    `json:"validator_address"`
-				ValidatorIndex   string    `json:"validator_index"`
-			} `json:"precommits"`
-		} `json:"last_commit"`
-	} `json:"block"`
+			Height           string    `json:"height"`
+			Round            string    `json:"round"`
+			Signature        string    `json:"signature"`
+			Timestamp        time.Time `json:"timestamp"`
+			Type             int       `json:"type"`
+			ValidatorAddress string    `json:"validator_address"`
+			ValidatorIndex   string    `json:"validator_index"`
+		} `json:"precommits"`
+	} `json:"last_commit"`
+}
+
+// GetBlockOutput represents output for GetBlock request
+type GetBlockOutput struct {
+	Block   Block `json:"block"`
 	BlockID struct {
 		Hash  string `json:"hash"`
 		Parts struct {
@@ -166,9 +184,37 @@ type AllParams struct {
 	ViperParams ParamGroup `json:"viper_params"`
 }
 
+// EvidenceType enum that represents the kind of evidence a receipt was built from
+type EvidenceType int
+
+const (
+	// RelayEvidence represents evidence of served relays
+	RelayEvidence EvidenceType = iota + 1
+	// ChallengeEvidence represents evidence of submitted challenges
+	ChallengeEvidence
+)
+
+// Receipt represents the proof of work submitted by a node for a session
+type Receipt struct {
+	SessionHeader   *SessionHeader `json:"session_header"`
+	ServicerAddress string         `json:"servicer_address"`
+	Total           int64          `json:"total"`
+	EvidenceType    EvidenceType   `json:"evidence_type"`
+}
+
+// GetNodeReceiptsOutput represents output for GetNodeReceipts request
+type GetNodeReceiptsOutput struct {
+	Result     []*Receipt `json:"result"`
+	Page       int        `json:"page"`
+	TotalPages int        `json:"total_pages"`
+}
+
 // DispatchOutput represents output for Dispatch request
+// SessionKey and BlockHash are empty when the node does not return them
 type DispatchOutput struct {
 	BlockHeight int      `json:"block_height"`
+	BlockHash   string   `json:"block_hash"`
+	SessionKey  string   `json:"session_key"`
 	Session     *Session `json:"session"`
 }
 
@@ -199,6 +245,16 @@ type Node struct {
 	OutputAddress string    `json:"output_address"`
 }
 
+// StakedTokens returns the staked tokens of the node parsed from Tokens, false when it has no positive stake
+func (n *Node) StakedTokens() (*big.Int, bool) {
+	tokens, ok := new(big.Int).SetString(n.Tokens, 10)
+	if !ok || tokens.Sign() <= 0 {
+		return nil, false
+	}
+
+	return tokens, true
+}
+
 // RPCError reprensents error output from RPC request
 type RPCError struct {
 	Code    int    `json:"code"`
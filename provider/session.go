@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"errors"
+	"strconv"
+)
+
+// BlocksPerSessionParam is the node param key of the number of blocks a session lasts
+const BlocksPerSessionParam = "pos/BlocksPerSession"
+
+var (
+	// ErrNoSessionHeader error when checking a session without header
+	ErrNoSessionHeader = errors.New("session has no header")
+	// ErrInvalidBlocksPerSession error when the blocks per session param is missing or not a positive number
+	ErrInvalidBlocksPerSession = errors.New("invalid blocks per session param")
+)
+
+// SessionStatus represents if a session can still be relayed with
+type SessionStatus int
+
+const (
+	// SessionValid is a session of the current session window whose nodes are still the dispatched ones
+	SessionValid SessionStatus = iota + 1
+	// SessionExpired is a session whose window does not contain the current height
+	SessionExpired
+	// SessionRolledOver is a session of the current window whose nodes are no longer the dispatched ones
+	SessionRolledOver
+)
+
+// String returns the name of the status
+func (s SessionStatus) String() string {
+	switch s {
+	case SessionValid:
+		return "valid"
+	case SessionExpired:
+		return "expired"
+	case SessionRolledOver:
+		return "rolled over"
+	default:
+		return "unknown"
+	}
+}
+
+// CheckSession returns if given session is still current
+// The current height must be in [sessionHeight, sessionHeight + pos/BlocksPerSession) and a new dispatch
+// for the session app and chain must return the same nodes, otherwise the session should be refreshed
+func (p *Provider) CheckSession(session *Session) (SessionStatus, error) {
+	if session == nil || session.Header == nil {
+		return 0, ErrNoSessionHeader
+	}
+
+	height, err := p.GetBlockHeight()
+	if err != nil {
+		return 0, err
+	}
+
+	blocksPerSession, err := p.getBlocksPerSession()
+	if err != nil {
+		return 0, err
+	}
+
+	sessionHeight := session.Header.SessionHeight
+	if height < sessionHeight || height >= sessionHeight+blocksPerSession {
+		return SessionExpired, nil
+	}
+
+	dispatch, err := p.Dispatch(session.Header.AppPublicKey, session.Header.Chain, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if !isSameSession(session, dispatch.Session) {
+		return SessionRolledOver, nil
+	}
+
+	return SessionValid, nil
+}
+
+// IsSessionValid returns if CheckSession finds given session valid
+func (p *Provider) IsSessionValid(session *Session) (bool, error) {
+	status, err := p.CheckSession(session)
+	if err != nil {
+		return false, err
+	}
+
+	return status == SessionValid, nil
+}
+
+func (p *Provider) getBlocksPerSession() (int, error) {
+	params, err := p.GetAllParams(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	value, ok := params.NodeParams.Get(BlocksPerSessionParam)
+	if !ok {
+		return 0, ErrInvalidBlocksPerSession
+	}
+
+	blocksPerSession, err := strconv.Atoi(value)
+	if err != nil || blocksPerSession <= 0 {
+		return 0, ErrInvalidBlocksPerSession
+	}
+
+	return blocksPerSession, nil
+}
+
+// isSameSession returns if both sessions have the same height and nodes, the node order does not matter
+func isSameSession(session, dispatched *Session) bool {
+	if dispatched == nil || dispatched.Header == nil || dispatched.Header.SessionHeight != session.Header.SessionHeight ||
+		len(dispatched.Nodes) != len(session.Nodes) {
+		return false
+	}
+
+	publicKeys := make(map[string]bool, len(session.Nodes))
+	for _, node := range session.Nodes {
+		publicKeys[node.PublicKey] = true
+	}
+
+	for _, node := range dispatched.Nodes {
+		if !publicKeys[node.PublicKey] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+)
+
+// ProviderStats represents a snapshot of the HTTP requests done by a Provider
+// Every request sent counts, including each retry of the provider retrier
+// Responses are counted by status class, 1xx and 3xx responses count as other responses so the response counts
+// plus the connection errors add up to TotalRequests once no request is in flight
+// Requests failing before a response count as connection errors
+// InFlight is the amount of requests waiting for a response when the snapshot is taken
+// ReusedConnections and NewConnections tell whether requests got an idle connection of the pool,
+// connections are only reused with WithConnectionReuse, otherwise every request gets a new connection
+type ProviderStats struct {
+	TotalRequests     int64
+	Responses2xx      int64
+	Responses4xx      int64
+	Responses5xx      int64
+	OtherResponses    int64
+	ConnectionErrors  int64
+	InFlight          int64
+	ReusedConnections int64
+	NewConnections    int64
+}
+
+// providerCounters holds atomic counters, it is allocated on its own so 64 bit fields stay aligned on 32 bit platforms
+type providerCounters struct {
+	totalRequests     int64
+	responses2xx      int64
+	responses4xx      int64
+	responses5xx      int64
+	otherResponses    int64
+	connectionErrors  int64
+	inFlight          int64
+	reusedConnections int64
+	newConnections    int64
+}
+
+// trace returns the request with a client trace counting whether it got a reused connection
+func (c *providerCounters) trace(request *http.Request) *http.Request {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				atomic.AddInt64(&c.reusedConnections, 1)
+			} else {
+				atomic.AddInt64(&c.newConnections, 1)
+			}
+		},
+	}
+
+	return request.WithContext(httptrace.WithClientTrace(request.Context(), trace))
+}
+
+func (c *providerCounters) start() {
+	atomic.AddInt64(&c.totalRequests, 1)
+	atomic.AddInt64(&c.inFlight, 1)
+}
+
+func (c *providerCounters) finish(response *http.Response) {
+	atomic.AddInt64(&c.inFlight, -1)
+
+	switch {
+	case response == nil:
+		atomic.AddInt64(&c.connectionErrors, 1)
+	case response.StatusCode >= http.StatusInternalServerError:
+		atomic.AddInt64(&c.responses5xx, 1)
+	case response.StatusCode >= http.StatusBadRequest:
+		atomic.AddInt64(&c.responses4xx, 1)
+	case response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices:
+		atomic.AddInt64(&c.responses2xx, 1)
+	default:
+		atomic.AddInt64(&c.otherResponses, 1)
+	}
+}
+
+func (c *providerCounters) reset() {
+	atomic.StoreInt64(&c.totalRequests, 0)
+	atomic.StoreInt64(&c.responses2xx, 0)
+	atomic.StoreInt64(&c.responses4xx, 0)
+	atomic.StoreInt64(&c.responses5xx, 0)
+	atomic.StoreInt64(&c.otherResponses, 0)
+	atomic.StoreInt64(&c.connectionErrors, 0)
+	atomic.StoreInt64(&c.reusedConnections, 0)
+	atomic.StoreInt64(&c.newConnections, 0)
+}
+
+// Stats returns a snapshot of the HTTP requests done by the provider since its creation or the last ResetStats
+func (p *Provider) Stats() ProviderStats {
+	if p.stats == nil {
+		return ProviderStats{}
+	}
+
+	return ProviderStats{
+		TotalRequests:     atomic.LoadInt64(&p.stats.totalRequests),
+		Responses2xx:      atomic.LoadInt64(&p.stats.responses2xx),
+		Responses4xx:      atomic.LoadInt64(&p.stats.responses4xx),
+		Responses5xx:      atomic.LoadInt64(&p.stats.responses5xx),
+		OtherResponses:    atomic.LoadInt64(&p.stats.otherResponses),
+		ConnectionErrors:  atomic.LoadInt64(&p.stats.connectionErrors),
+		InFlight:          atomic.LoadInt64(&p.stats.inFlight),
+		ReusedConnections: atomic.LoadInt64(&p.stats.reusedConnections),
+		NewConnections:    atomic.LoadInt64(&p.stats.newConnections),
+	}
+}
+
+// ResetStats zeroes the request statistics of the provider, it can be called while requests are in flight
+// InFlight is not reset since it is the current amount of requests waiting for a response
+func (p *Provider) ResetStats() {
+	if p.stats == nil {
+		return
+	}
+
+	p.stats.reset()
+}
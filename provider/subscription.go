@@ -0,0 +1,288 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultWebSocketPath = "/websocket"
+
+	subscriptionInitialBackoff = 100 * time.Millisecond
+	subscriptionMaxBackoff     = 10 * time.Second
+)
+
+var newBlockSubscriptionRequest = []byte(`{"jsonrpc":"2.0","method":"subscribe","id":1,"params":{"query":"tm.event='NewBlock'"}}`)
+
+// ErrSubscriptionRejected error when the node answers a subscription with an error
+var ErrSubscriptionRejected = errors.New("subscription rejected")
+
+type newBlockEvent struct {
+	Result struct {
+		Data struct {
+			Value struct {
+				Block *Block `json:"block"`
+			} `json:"value"`
+		} `json:"data"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// WithWebSocketURL sets the event subscription endpoint used by SubscribeNewBlocks
+// By default it is the RPC URL with a ws or wss scheme and /websocket appended to its path
+func WithWebSocketURL(wsURL string) ProviderOption {
+	return func(p *Provider) error {
+		p.wsURL = wsURL
+
+		return nil
+	}
+}
+
+func (p *Provider) getWebSocketURL() (string, error) {
+	if p.wsURL != "" {
+		return p.wsURL, nil
+	}
+
+	return toWebSocketURL(p.rpcURL, defaultWebSocketPath)
+}
+
+// toWebSocketURL returns the URL with a ws or wss scheme instead of http or https and with given path appended
+func toWebSocketURL(rawURL, path string) (string, error) {
+	wsURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	switch wsURL.Scheme {
+	case "http":
+		wsURL.Scheme = "ws"
+	case "https":
+		wsURL.Scheme = "wss"
+	default:
+		return "", ErrInvalidWebSocketURL
+	}
+
+	wsURL.Path = strings.TrimSuffix(wsURL.Path, "/") + path
+
+	return wsURL.String(), nil
+}
+
+// parseNewBlockEvent returns the block of a NewBlock event, or nil for other messages like the subscription ack
+func parseNewBlockEvent(message []byte) (*Block, error) {
+	event := newBlockEvent{}
+
+	err := json.Unmarshal(message, &event)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Error != nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrSubscriptionRejected, event.Error.Message, event.Error.Data)
+	}
+
+	return event.Result.Data.Value.Block, nil
+}
+
+// SubscribeNewBlocks opens a WebSocket to the event subscription endpoint of the node and streams every new block
+// Errors of the first connection are returned, after it disconnects are sent on the error channel without blocking
+// and the subscription is done again with an exponential backoff
+// Both channels are closed when ctx is done
+// The connection uses the certificate pins, headers and HMAC secret of the provider, the HMAC signs the handshake only,
+// with WithHTTPClient it fails with ErrWebSocketUnsupportedOption
+func (p *Provider) SubscribeNewBlocks(ctx context.Context) (<-chan *Block, <-chan error, error) {
+	if p.optionErr != nil {
+		return nil, nil, p.optionErr
+	}
+
+	conn, err := p.subscribeNewBlocks(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blocks := make(chan *Block)
+	errs := make(chan error, 1)
+
+	go p.streamNewBlocks(ctx, conn, blocks, errs)
+
+	return blocks, errs, nil
+}
+
+func (p *Provider) subscribeNewBlocks(ctx context.Context) (*wsConn, error) {
+	wsURL, err := p.getWebSocketURL()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := p.dialWebSocket(ctx, wsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn.writeMessage(newBlockSubscriptionRequest)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (p *Provider) streamNewBlocks(ctx context.Context, conn *wsConn, blocks chan *Block, errs chan error) {
+	defer close(blocks)
+	defer close(errs)
+
+	for conn != nil {
+		err := readNewBlocks(ctx, conn, blocks)
+		if ctx.Err() != nil {
+			return
+		}
+
+		sendSubscriptionError(errs, err)
+
+		conn = p.resubscribeNewBlocks(ctx, errs)
+	}
+}
+
+// resubscribeNewBlocks subscribes again until it succeeds, returns nil when ctx is done
+func (p *Provider) resubscribeNewBlocks(ctx context.Context, errs chan error) *wsConn {
+	backoff := subscriptionInitialBackoff
+
+	for {
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil
+		case <-timer.C:
+		}
+
+		conn, err := p.subscribeNewBlocks(ctx)
+		if err == nil {
+			return conn
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		sendSubscriptionError(errs, err)
+
+		backoff *= 2
+		if backoff > subscriptionMaxBackoff {
+			backoff = subscriptionMaxBackoff
+		}
+	}
+}
+
+// closeOnDone closes conn when ctx is done or when the returned function is called
+func closeOnDone(ctx context.Context, conn *wsConn) func() {
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+
+		_ = conn.Close()
+	}()
+
+	return func() { close(done) }
+}
+
+// readNewBlocks sends the blocks received on conn until it fails or ctx is done, conn is always closed
+func readNewBlocks(ctx context.Context, conn *wsConn, blocks chan *Block) error {
+	stop := closeOnDone(ctx, conn)
+	defer stop()
+
+	for {
+		message, err := conn.readMessage()
+		if err != nil {
+			return err
+		}
+
+		block, err := parseNewBlockEvent(message)
+		if err != nil {
+			return err
+		}
+
+		if block == nil {
+			continue
+		}
+
+		select {
+		case blocks <- block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func sendSubscriptionError(errs chan error, err error) {
+	select {
+	case errs <- err:
+	default:
+	}
+}
+
+// RelaySubscribe sends the relay to the WebSocket relay route of the servicer at rpcURL and calls handler with every message received
+// It returns the ctx error once ctx is done, a close of the servicer is returned as a WebSocketCloseError
+// and a connection dropped without close frame as an error wrapping ErrWebSocketConnectionLost
+// The connection uses the certificate pins and headers of the provider like SubscribeNewBlocks
+func (p *Provider) RelaySubscribe(ctx context.Context, rpcURL string, input *RelayInput, handler func(message []byte)) error {
+	if p.optionErr != nil {
+		return p.optionErr
+	}
+
+	wsURL, err := toWebSocketURL(rpcURL, string(ClientRelayWebSocketRoute))
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	conn, err := p.dialWebSocket(ctx, wsURL)
+	if err != nil {
+		return err
+	}
+
+	stop := closeOnDone(ctx, conn)
+	defer stop()
+
+	err = conn.writeMessage(body)
+
+	for err == nil {
+		var message []byte
+
+		message, err = conn.readMessage()
+		if err == nil {
+			handler(message)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	var closeErr *WebSocketCloseError
+	if errors.As(err, &closeErr) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", ErrWebSocketConnectionLost, err)
+}
@@ -12,6 +12,8 @@ const (
 	ClientRawTXRoute V1RPCRoute = "/v1/client/rawtx"
 	// ClientRelayRoute represents client realy route
 	ClientRelayRoute V1RPCRoute = "/v1/client/relay"
+	// ClientRelayWebSocketRoute represents client relay WebSocket route
+	ClientRelayWebSocketRoute V1RPCRoute = "/v1/client/relay/websocket"
 	// QueryAccountRoute represents query account route
 	QueryAccountRoute V1RPCRoute = "/v1/query/account"
 	// QueryAccountsRoute represents query accounts route
@@ -30,8 +32,12 @@ const (
 	QueryBalanceRoute V1RPCRoute = "/v1/query/balance"
 	// QueryBlockRoute represents query block route
 	QueryBlockRoute V1RPCRoute = "/v1/query/block"
+	// QueryBlockResultsRoute represents query block results route
+	QueryBlockResultsRoute V1RPCRoute = "/v1/query/blockresults"
 	// QueryBlockTXsRoute represents query block TXs route
 	QueryBlockTXsRoute V1RPCRoute = "/v1/query/blocktxs"
+	// QueryConsensusStateRoute represents query consensus state route
+	QueryConsensusStateRoute V1RPCRoute = "/v1/query/consensusstate"
 	// QueryHeightRoute represents query height route
 	QueryHeightRoute V1RPCRoute = "/v1/query/height"
 	// QueryNodeRoute represents query node route
@@ -50,6 +56,8 @@ const (
 	QueryNodesRoute V1RPCRoute = "/v1/query/nodes"
 	// QueryViperParamsRoute represents query viper params route
 	QueryViperParamsRoute V1RPCRoute = "/v1/query/viperparams"
+	// QuerySigningInfoRoute represents query signing info route
+	QuerySigningInfoRoute V1RPCRoute = "/v1/query/signinginfo"
 	// QuerySupplyRoute represents query supply route
 	QuerySupplyRoute V1RPCRoute = "/v1/query/supply"
 	// QuerySupportedChainsRoute represents query supported chains route
@@ -58,4 +66,6 @@ const (
 	QueryTXRoute V1RPCRoute = "/v1/query/tx"
 	// QueryUpgradeRoute represents query upgrade route
 	QueryUpgradeRoute V1RPCRoute = "/v1/query/upgrade"
+	// QueryValidatorsRoute represents query validators route
+	QueryValidatorsRoute V1RPCRoute = "/v1/query/validators"
 )
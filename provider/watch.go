@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"time"
+)
+
+// WatchBlockHeight polls the block height of given provider every poll interval
+// New heights are sent on the first channel and request errors on the second one
+// Sending never blocks: a height not read yet is replaced by the newest one and errors are dropped if the last one was not read
+// Both channels are closed when ctx is done
+func WatchBlockHeight(ctx context.Context, p *Provider, pollInterval time.Duration) (<-chan int64, <-chan error) {
+	heights := make(chan int64, 1)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(heights)
+		defer close(errs)
+
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		var lastHeight int64
+
+		for {
+			lastHeight = pollBlockHeight(p, lastHeight, heights, errs)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return heights, errs
+}
+
+func pollBlockHeight(p *Provider, lastHeight int64, heights chan int64, errs chan error) int64 {
+	height, err := p.GetBlockHeight()
+	if err != nil {
+		select {
+		case errs <- err:
+		default:
+		}
+
+		return lastHeight
+	}
+
+	if int64(height) == lastHeight {
+		return lastHeight
+	}
+
+	for {
+		select {
+		case heights <- int64(height):
+			return int64(height)
+		default:
+		}
+
+		select {
+		case <-heights:
+		default:
+		}
+	}
+}
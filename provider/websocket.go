@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsMaxMessageSize = 16 << 20
+
+	// WebSocketCloseNoStatus is the close code of WebSocket close frames without code
+	WebSocketCloseNoStatus = websocket.CloseNoStatusReceived
+)
+
+var (
+	// ErrInvalidWebSocketURL error when the WebSocket URL does not have a ws or wss scheme
+	ErrInvalidWebSocketURL = errors.New("invalid WebSocket URL")
+	// ErrWebSocketHandshake error when the node does not accept the WebSocket upgrade
+	ErrWebSocketHandshake = errors.New("WebSocket handshake failed")
+	// ErrWebSocketClosed error when the node closes the WebSocket connection
+	ErrWebSocketClosed = errors.New("WebSocket connection closed")
+	// ErrWebSocketMessageTooLarge error when a WebSocket message is bigger than the max message size
+	ErrWebSocketMessageTooLarge = errors.New("WebSocket message too large")
+	// ErrWebSocketConnectionLost error when the WebSocket connection drops without a close frame, like on EOF or reset
+	ErrWebSocketConnectionLost = errors.New("WebSocket connection lost")
+	// ErrWebSocketUnsupportedOption error when the provider has an option WebSocket connections cannot honor
+	ErrWebSocketUnsupportedOption = errors.New("provider option not supported by WebSocket connections")
+)
+
+// wsHandshakeHeaders are set by the handshake itself, base headers cannot replace them
+var wsHandshakeHeaders = []string{"Host", "Upgrade", "Connection", "Sec-Websocket-Key", "Sec-Websocket-Version",
+	"Sec-Websocket-Extensions"}
+
+// WebSocketCloseError represents the thrown error when the node closes the WebSocket connection
+type WebSocketCloseError struct {
+	Code   int
+	Reason string
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *WebSocketCloseError) Error() string {
+	return fmt.Sprintf("%s: code %d: %s", ErrWebSocketClosed, e.Code, e.Reason)
+}
+
+// Unwrap returns ErrWebSocketClosed so the error can be checked with errors.Is
+func (e *WebSocketCloseError) Unwrap() error {
+	return ErrWebSocketClosed
+}
+
+// wsConn is a WebSocket connection sending text messages
+// pings are answered and close frames echoed by the underlying connection while reading
+type wsConn struct {
+	conn *websocket.Conn
+}
+
+// dialWebSocket opens a WebSocket connection to the given ws or wss URL with the TLS config and headers of the provider
+// With WithHMACAuth the handshake carries the HMAC of its empty body, custom HTTP clients cannot apply
+// to the connection so they fail with ErrWebSocketUnsupportedOption instead of silently being skipped
+func (p *Provider) dialWebSocket(ctx context.Context, rawURL string) (*wsConn, error) {
+	if p.httpClient != nil {
+		return nil, fmt.Errorf("%w: WithHTTPClient", ErrWebSocketUnsupportedOption)
+	}
+
+	wsURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if wsURL.Scheme != "ws" && wsURL.Scheme != "wss" {
+		return nil, ErrInvalidWebSocketURL
+	}
+
+	headers := http.Header{}
+	p.setBaseHeaders(headers)
+
+	for _, header := range wsHandshakeHeaders {
+		headers.Del(header)
+	}
+
+	if p.hmacSecret != nil {
+		headers.Set(HMACHeader, computeHMAC(nil, p.hmacSecret))
+	}
+
+	dialer := &websocket.Dialer{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: p.getTLSConfig(),
+	}
+
+	conn, response, err := dialer.DialContext(ctx, wsURL.String(), headers)
+	if response != nil {
+		_ = response.Body.Close()
+	}
+
+	if errors.Is(err, websocket.ErrBadHandshake) {
+		return nil, ErrWebSocketHandshake
+	}
+
+	if err != nil {
+		return nil, getTransportError(err)
+	}
+
+	conn.SetReadLimit(wsMaxMessageSize)
+
+	return &wsConn{conn: conn}, nil
+}
+
+// getWebSocketError returns a close frame of the node as a WebSocketCloseError
+// a connection dropped without close frame is reported with the abnormal closure code, it is returned as is
+func getWebSocketError(err error) error {
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) && closeErr.Code != websocket.CloseAbnormalClosure {
+		return &WebSocketCloseError{Code: closeErr.Code, Reason: closeErr.Text}
+	}
+
+	if errors.Is(err, websocket.ErrReadLimit) {
+		return ErrWebSocketMessageTooLarge
+	}
+
+	return err
+}
+
+// readMessage returns the next text or binary message
+func (c *wsConn) readMessage() ([]byte, error) {
+	_, message, err := c.conn.ReadMessage()
+	if err != nil {
+		return nil, getWebSocketError(err)
+	}
+
+	return message, nil
+}
+
+func (c *wsConn) writeMessage(message []byte) error {
+	return c.conn.WriteMessage(websocket.TextMessage, message)
+}
+
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
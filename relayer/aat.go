@@ -0,0 +1,48 @@
+package relayer
+
+import "github.com/vishruthsk/viper-go/provider"
+
+// aatHash is a memoized HashAAT result, it is only valid for the AAT version and signature it was computed with
+type aatHash struct {
+	version   string
+	signature string
+	hash      string
+}
+
+// WithAATHashCache enables or disables memoizing the Viper AAT hash used in every relay proof
+// It is enabled by default, the cache keeps one hash per app and client public keys
+func WithAATHashCache(enabled bool) RelayerOption {
+	return func(r *Relayer) {
+		r.skipAATHashCache = !enabled
+	}
+}
+
+// hashAAT returns the hash of given AAT with the relayer hasher, reusing the last hash of the same app and client
+// while the AAT version and signature did not change
+func (r *Relayer) hashAAT(aat *provider.ViperAAT) (string, error) {
+	if r.skipAATHashCache {
+		return HashAATWithHasher(r.hasher, aat)
+	}
+
+	key := aat.AppPubKey + "/" + aat.ClientPubKey
+
+	if cached, ok := r.aatHashes.Load(key); ok {
+		entry := cached.(*aatHash)
+		if entry.version == aat.Version && entry.signature == aat.Signature {
+			return entry.hash, nil
+		}
+	}
+
+	hash, err := HashAATWithHasher(r.hasher, aat)
+	if err != nil {
+		return "", err
+	}
+
+	r.aatHashes.Store(key, &aatHash{
+		version:   aat.Version,
+		signature: aat.Signature,
+		hash:      hash,
+	})
+
+	return hash, nil
+}
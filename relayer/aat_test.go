@@ -0,0 +1,94 @@
+package relayer
+
+import (
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayer_hashAAT(t *testing.T) {
+	c := require.New(t)
+
+	aat := &provider.ViperAAT{
+		Version:      "0.0.1",
+		AppPubKey:    "ABCD",
+		ClientPubKey: "EFGH",
+		Signature:    "IJKL",
+	}
+
+	expectedHash, err := HashAAT(aat)
+	c.NoError(err)
+
+	relayer := NewRelayer(nil, nil)
+
+	hash, err := relayer.hashAAT(aat)
+	c.NoError(err)
+	c.Equal(expectedHash, hash)
+
+	cached, ok := relayer.aatHashes.Load("ABCD/EFGH")
+	c.True(ok)
+	c.Equal("IJKL", cached.(*aatHash).signature)
+
+	cached.(*aatHash).hash = "cached"
+
+	hash, err = relayer.hashAAT(aat)
+	c.NoError(err)
+	c.Equal("cached", hash)
+
+	aat.Signature = "MNOP"
+
+	hash, err = relayer.hashAAT(aat)
+	c.NoError(err)
+	c.Equal(expectedHash, hash)
+
+	cached, ok = relayer.aatHashes.Load("ABCD/EFGH")
+	c.True(ok)
+	c.Equal("MNOP", cached.(*aatHash).signature)
+
+	relayer = NewRelayer(nil, nil, WithAATHashCache(false))
+
+	hash, err = relayer.hashAAT(aat)
+	c.NoError(err)
+	c.Equal(expectedHash, hash)
+
+	_, ok = relayer.aatHashes.Load("ABCD/EFGH")
+	c.False(ok)
+}
+
+func BenchmarkRelayer_RelayAATHashCache(b *testing.B) {
+	signer, err := signer.NewRandomSigner()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	input := newBatchInputs(1)[0]
+	input.ViperAAT = &provider.ViperAAT{
+		Version:      "0.0.1",
+		AppPubKey:    signer.GetPublicKey(),
+		ClientPubKey: signer.GetPublicKey(),
+		Signature:    "ABCD",
+	}
+
+	for _, enabled := range []bool{true, false} {
+		relayer := NewRelayer(signer, &providerMock{}, WithAATHashCache(enabled))
+
+		name := "cached"
+		if !enabled {
+			name = "uncached"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				_, err := relayer.Relay(input, nil)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
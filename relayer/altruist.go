@@ -0,0 +1,170 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// DefaultAltruistTimeout is the timeout of the requests to an altruist
+const DefaultAltruistTimeout = 10 * time.Second
+
+// DefaultAltruistMaxResponseSize is the default max size of an altruist response body
+const DefaultAltruistMaxResponseSize = 4 * 1024 * 1024
+
+// ErrAltruistRelay error when the relay to an altruist fails
+var ErrAltruistRelay = errors.New("altruist relay failed")
+
+var defaultAltruistClient = &http.Client{Timeout: DefaultAltruistTimeout}
+
+// AltruistError represents the thrown error when the altruist fails after every session node failed
+// It unwraps to the error of the session nodes so the error can be checked with errors.Is and errors.As
+type AltruistError struct {
+	RelayErr error
+	Err      error
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *AltruistError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrAltruistRelay, e.Err, e.RelayErr)
+}
+
+// Unwrap returns the error of the session nodes
+func (e *AltruistError) Unwrap() error {
+	return e.RelayErr
+}
+
+// WithAltruist sets a plain unauthenticated RPC url of given blockchain used as last resort by RelayWithRetries
+// The raw payload is sent to it only after every session node was tried and failed, the output it returns
+// has no proof and ServedByAltruist set, inputs with DisableAltruist never use it
+func WithAltruist(blockchain, url string) RelayerOption {
+	return func(r *Relayer) {
+		if r.altruists == nil {
+			r.altruists = map[string]string{}
+		}
+
+		r.altruists[blockchain] = url
+	}
+}
+
+// WithAltruistClient sets the HTTP client used for altruist relays, defaults to a client with DefaultAltruistTimeout
+// Altruist relays do not go through the provider so its TLS, client and response size options do not apply to them
+func WithAltruistClient(client *http.Client) RelayerOption {
+	return func(r *Relayer) {
+		r.altruistClient = client
+	}
+}
+
+// WithAltruistMaxResponseSize fails altruist relays whose response body is bigger than maxBytes
+// with a provider.ResponseTooLargeError, defaults to DefaultAltruistMaxResponseSize and maxBytes <= 0 disables the limit
+func WithAltruistMaxResponseSize(maxBytes int64) RelayerOption {
+	return func(r *Relayer) {
+		r.altruistMaxResponseSize = maxBytes
+	}
+}
+
+// getAltruist returns the altruist url for the input when every session node was tried
+func (r *Relayer) getAltruist(input *Input, tried map[string]bool) (string, bool) {
+	if input.DisableAltruist {
+		return "", false
+	}
+
+	altruistURL, ok := r.altruists[input.Blockchain]
+	if !ok {
+		return "", false
+	}
+
+	for _, node := range input.Session.Nodes {
+		if !tried[node.PublicKey] {
+			return "", false
+		}
+	}
+
+	return altruistURL, true
+}
+
+// relayToAltruist sends the raw payload of the input to the altruist, the response is not signed
+func (r *Relayer) relayToAltruist(ctx context.Context, altruistURL string, input *Input) (*Output, error) {
+	method := input.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, altruistURL+input.Path, strings.NewReader(input.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range r.getRelayHeaders(input) {
+		req.Header.Set(key, value)
+	}
+
+	startTime := time.Now()
+
+	client := r.altruistClient
+	if client == nil {
+		client = defaultAltruistClient
+	}
+
+	response, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	body, err := r.readAltruistBody(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = getAltruistStatusError(response.StatusCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Output{
+		RelayOutput:      &provider.RelayOutput{Response: string(body)},
+		Duration:         time.Since(startTime),
+		ServedByAltruist: true,
+	}, nil
+}
+
+// readAltruistBody reads the altruist response body up to the altruist max response size
+func (r *Relayer) readAltruistBody(body io.Reader) ([]byte, error) {
+	if r.altruistMaxResponseSize <= 0 {
+		return ioutil.ReadAll(body)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(body, r.altruistMaxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > r.altruistMaxResponseSize {
+		return nil, &provider.ResponseTooLargeError{Limit: r.altruistMaxResponseSize}
+	}
+
+	return data, nil
+}
+
+func getAltruistStatusError(statusCode int) error {
+	switch {
+	case statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices:
+		return nil
+	case statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError:
+		return provider.Err4xxOnConnection
+	case statusCode >= http.StatusInternalServerError:
+		return provider.Err5xxOnConnection
+	default:
+		return provider.ErrUnexpectedCodeOnConnection
+	}
+}
@@ -0,0 +1,147 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/utils-go/mock-client"
+)
+
+func TestRelayer_WithAltruist(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+		WithAltruist("0021", "https://altruist.com"))
+	input := newConsensusInput()
+	retryOptions := &RetryOptions{MaxAttempts: 3, Backoff: time.Millisecond, SwitchNodes: true}
+
+	var altruistBodies []string
+
+	altruistStatus := http.StatusOK
+
+	httpmock.RegisterResponder(http.MethodPost, "https://altruist.com",
+		func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+			altruistBodies = append(altruistBodies, string(body))
+
+			return httpmock.NewStringResponse(altruistStatus, `{"id":1,"jsonrpc":"2.0","result":"0x15"}`), nil
+		})
+
+	addMockedNodeRelay("https://aog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+	addMockedNodeRelay("https://pjog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+	addMockedNodeRelay("https://ohana.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	output, _, err := relayer.RelayWithRetries(input, nil, retryOptions)
+	c.NoError(err)
+	c.False(output.ServedByAltruist)
+	c.NotNil(output.Proof)
+	c.Empty(altruistBodies)
+
+	for _, node := range input.Session.Nodes {
+		mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", node.ServiceURL, provider.ClientRelayRoute),
+			http.StatusInternalServerError, "{}")
+	}
+
+	output, attempts, err := relayer.RelayWithRetries(input, nil, &RetryOptions{MaxAttempts: 2,
+		Backoff: time.Millisecond, SwitchNodes: true})
+	c.True(errors.Is(err, provider.Err5xxOnConnection))
+	c.Empty(output)
+	c.Len(attempts, 2)
+	c.Empty(altruistBodies)
+
+	output, attempts, err = relayer.RelayWithRetries(input, nil, retryOptions)
+	c.NoError(err)
+	c.Len(attempts, 3)
+	c.True(output.ServedByAltruist)
+	c.Nil(output.Proof)
+	c.Equal(`{"id":1,"jsonrpc":"2.0","result":"0x15"}`, output.RelayOutput.Response)
+	c.Equal([]string{input.Data}, altruistBodies)
+
+	input.DisableAltruist = true
+
+	output, _, err = relayer.RelayWithRetries(input, nil, retryOptions)
+	c.True(errors.Is(err, provider.Err5xxOnConnection))
+	c.Empty(output)
+	c.Len(altruistBodies, 1)
+
+	input.DisableAltruist = false
+	altruistStatus = http.StatusBadGateway
+
+	output, _, err = relayer.RelayWithRetries(input, nil, retryOptions)
+	c.True(errors.Is(err, provider.Err5xxOnConnection))
+	c.Empty(output)
+
+	var altruistErr *AltruistError
+
+	c.ErrorAs(err, &altruistErr)
+	c.Equal(provider.Err5xxOnConnection, altruistErr.Err)
+
+	var retryErr *RetryError
+
+	c.ErrorAs(err, &retryErr)
+	c.Len(retryErr.Attempts, 3)
+
+	input.Blockchain = "0001"
+
+	output, _, err = relayer.RelayWithRetries(input, nil, retryOptions)
+	c.False(errors.As(err, &altruistErr))
+	c.Empty(output)
+	c.Len(altruistBodies, 2)
+}
+
+func TestRelayer_WithAltruistClient(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodPost, "https://altruist.com",
+		httpmock.NewStringResponder(http.StatusOK, `{"id":1,"jsonrpc":"2.0","result":"0x15"}`))
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+		WithAltruist("0021", "https://altruist.com"), WithAltruistClient(&http.Client{Transport: transport}),
+		WithAltruistMaxResponseSize(10))
+	input := newConsensusInput()
+	retryOptions := &RetryOptions{MaxAttempts: 3, Backoff: time.Millisecond, SwitchNodes: true}
+
+	for _, node := range input.Session.Nodes {
+		mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", node.ServiceURL, provider.ClientRelayRoute),
+			http.StatusInternalServerError, "{}")
+	}
+
+	output, _, err := relayer.RelayWithRetries(input, nil, retryOptions)
+	c.Empty(output)
+
+	var altruistErr *AltruistError
+
+	c.ErrorAs(err, &altruistErr)
+	c.True(errors.Is(altruistErr.Err, provider.ErrResponseTooLarge))
+	c.Equal(1, transport.GetTotalCallCount())
+
+	WithAltruistMaxResponseSize(0)(relayer)
+
+	output, _, err = relayer.RelayWithRetries(input, nil, retryOptions)
+	c.NoError(err)
+	c.True(output.ServedByAltruist)
+	c.Equal(`{"id":1,"jsonrpc":"2.0","result":"0x15"}`, output.RelayOutput.Response)
+	c.Equal(2, transport.GetTotalCallCount())
+}
@@ -0,0 +1,170 @@
+package relayer
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// OutputFormatVersion is the version byte prefixed to outputs encoded by Output.MarshalBinary
+// Adding fields keeps the version, older readers ignore the fields they do not know
+const OutputFormatVersion byte = 1
+
+var (
+	// ErrEmptyStoredOutput error when decoding an output from empty data
+	ErrEmptyStoredOutput = errors.New("empty stored output")
+	// ErrUnsupportedOutputVersion error when a stored output has a format version this package can not decode
+	ErrUnsupportedOutputVersion = errors.New("unsupported stored output version")
+	// ErrIncompleteStoredOutput error when a stored output lacks the proof, node or relay output needed to verify it
+	ErrIncompleteStoredOutput = errors.New("incomplete stored output")
+	// ErrInvalidProofSignature error when a relay proof is not signed by the client of its AAT
+	ErrInvalidProofSignature = errors.New("invalid proof signature")
+	// ErrHashAlgorithmMismatch error when a stored output was hashed with another algorithm than the verifying one
+	ErrHashAlgorithmMismatch = errors.New("hash algorithm mismatch")
+)
+
+// storedOutput is the stable encoding of an Output, the response signature needs the response untouched
+type storedOutput struct {
+	Response         string               `json:"response"`
+	Signature        string               `json:"signature"`
+	Proof            *provider.RelayProof `json:"proof"`
+	Node             *provider.Node       `json:"node"`
+	Duration         time.Duration        `json:"duration"`
+	RefreshedSession *provider.Session    `json:"refreshed_session,omitempty"`
+	Trace            *RelayTrace          `json:"trace,omitempty"`
+	HashAlgorithm    string               `json:"hash_algorithm,omitempty"`
+	ServedByAltruist bool                 `json:"served_by_altruist,omitempty"`
+	Attempts         int                  `json:"attempts,omitempty"`
+	TriedNodes       []string             `json:"tried_nodes,omitempty"`
+	TotalDuration    time.Duration        `json:"total_duration,omitempty"`
+}
+
+// MarshalBinary encodes the output with its proof, node, response, signature, attempts and timing for later audits
+// The encoding is OutputFormatVersion followed by JSON
+func (o *Output) MarshalBinary() ([]byte, error) {
+	stored := storedOutput{
+		Proof:            o.Proof,
+		Node:             o.Node,
+		Duration:         o.Duration,
+		RefreshedSession: o.RefreshedSession,
+		Trace:            o.Trace,
+		HashAlgorithm:    o.HashAlgorithm,
+		ServedByAltruist: o.ServedByAltruist,
+		Attempts:         o.Attempts,
+		TriedNodes:       o.TriedNodes,
+		TotalDuration:    o.TotalDuration,
+	}
+
+	if o.RelayOutput != nil {
+		stored.Response = o.RelayOutput.Response
+		stored.Signature = o.RelayOutput.Signature
+	}
+
+	marshaledOutput, err := json.Marshal(&stored)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{OutputFormatVersion}, marshaledOutput...), nil
+}
+
+// UnmarshalBinary decodes an output encoded by MarshalBinary, unknown fields are ignored
+func (o *Output) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return ErrEmptyStoredOutput
+	}
+
+	if data[0] != OutputFormatVersion {
+		return ErrUnsupportedOutputVersion
+	}
+
+	stored := storedOutput{}
+
+	err := json.Unmarshal(data[1:], &stored)
+	if err != nil {
+		return err
+	}
+
+	requestHash := ""
+	if stored.Proof != nil {
+		requestHash = stored.Proof.RequestHash
+	}
+
+	*o = Output{
+		RelayOutput: &provider.RelayOutput{
+			Response:    stored.Response,
+			Signature:   stored.Signature,
+			RequestHash: requestHash,
+		},
+		Proof:            stored.Proof,
+		Node:             stored.Node,
+		Duration:         stored.Duration,
+		RefreshedSession: stored.RefreshedSession,
+		Trace:            stored.Trace,
+		HashAlgorithm:    stored.HashAlgorithm,
+		ServedByAltruist: stored.ServedByAltruist,
+		Attempts:         stored.Attempts,
+		TriedNodes:       stored.TriedNodes,
+		TotalDuration:    stored.TotalDuration,
+	}
+
+	return nil
+}
+
+// VerifyStoredOutput verifies a restored output, its proof must be signed by the client of the proof AAT
+// and its response by the node of the proof
+// An invalid response signature returns an InvalidResponseSignatureError
+// The proof is hashed with DefaultHasher, use VerifyStoredOutputWithHasher for outputs of other algorithms
+func VerifyStoredOutput(output *Output) error {
+	return VerifyStoredOutputWithHasher(DefaultHasher, output)
+}
+
+// VerifyStoredOutputWithHasher does VerifyStoredOutput hashing the proof with given hasher
+// Outputs recorded with another hash algorithm return ErrHashAlgorithmMismatch
+func VerifyStoredOutputWithHasher(hasher Hasher, output *Output) error {
+	if output.RelayOutput == nil || output.Proof == nil || output.Proof.AAT == nil || output.Node == nil {
+		return ErrIncompleteStoredOutput
+	}
+
+	if output.HashAlgorithm != "" && output.HashAlgorithm != hasher.Name() {
+		return ErrHashAlgorithmMismatch
+	}
+
+	err := verifyProofSignature(hasher, output.Proof)
+	if err != nil {
+		return err
+	}
+
+	if output.Proof.ServicerPubKey != output.Node.PublicKey || !IsValidResponseSignature(output) {
+		return &InvalidResponseSignatureError{Output: output}
+	}
+
+	return nil
+}
+
+func verifyProofSignature(hasher Hasher, proof *provider.RelayProof) error {
+	publicKey, err := hex.DecodeString(proof.AAT.ClientPubKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return ErrInvalidProofSignature
+	}
+
+	signature, err := hex.DecodeString(proof.Signature)
+	if err != nil {
+		return ErrInvalidProofSignature
+	}
+
+	proofBytes, err := GenerateProofBytesWithHasher(hasher, proof)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(publicKey, proofBytes, signature) {
+		return ErrInvalidProofSignature
+	}
+
+	return nil
+}
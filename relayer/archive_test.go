@@ -0,0 +1,124 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/utils-go/mock-client"
+)
+
+func TestOutput_MarshalBinary(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	clientSigner, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	nodeSigner, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	input := &Input{
+		Blockchain: "0021",
+		Data:       `{"method":"eth_blockNumber","id":1,"jsonrpc":"2.0"}`,
+		ViperAAT:   &provider.ViperAAT{Version: "0.0.1", AppPubKey: "ABCD", ClientPubKey: clientSigner.GetPublicKey()},
+		Session: &provider.Session{
+			Header: &provider.SessionHeader{SessionHeight: 21},
+			Nodes:  []*provider.Node{{PublicKey: nodeSigner.GetPublicKey(), ServiceURL: "https://dummy.com"}},
+		},
+	}
+
+	requestHash, err := HashRequest(&RequestHash{
+		Payload: &provider.RelayPayload{Data: input.Data},
+		Meta:    &provider.RelayMeta{BlockHeight: 21},
+	})
+	c.NoError(err)
+
+	response := `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`
+
+	responseBytes, err := GenerateResponseBytes(response, requestHash)
+	c.NoError(err)
+
+	signature, err := nodeSigner.Sign(responseBytes)
+	c.NoError(err)
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", provider.ClientRelayRoute), http.StatusOK,
+		fmt.Sprintf(`{"response": %q, "signature": %q}`, response, signature))
+
+	relayer := NewRelayer(clientSigner, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+
+	output, err := relayer.Relay(input, &provider.RelayRequestOptions{Trace: true})
+	c.NoError(err)
+
+	data, err := output.MarshalBinary()
+	c.NoError(err)
+	c.Equal(OutputFormatVersion, data[0])
+
+	restored := &Output{}
+
+	c.NoError(restored.UnmarshalBinary(data))
+	c.Equal(output.RelayOutput.Response, restored.RelayOutput.Response)
+	c.Equal(output.RelayOutput.Signature, restored.RelayOutput.Signature)
+	c.Equal(output.Proof, restored.Proof)
+	c.Equal(output.Node, restored.Node)
+	c.Equal(output.Duration, restored.Duration)
+	c.Equal(output.Trace, restored.Trace)
+	c.Equal(1, restored.Attempts)
+	c.Equal(output.TriedNodes, restored.TriedNodes)
+	c.Equal(output.TotalDuration, restored.TotalDuration)
+	c.NoError(VerifyStoredOutput(restored))
+
+	retried := *output
+	retried.Attempts = 3
+	retried.TriedNodes = []string{"AOG", "PJOG", "OHANA"}
+
+	data, err = retried.MarshalBinary()
+	c.NoError(err)
+
+	restored = &Output{}
+
+	c.NoError(restored.UnmarshalBinary(data))
+	c.Equal(3, restored.Attempts)
+	c.Equal([]string{"AOG", "PJOG", "OHANA"}, restored.TriedNodes)
+
+	data, err = (&Output{}).MarshalBinary()
+	c.NoError(err)
+	c.NotContains(string(data), "attempts")
+	c.NotContains(string(data), "tried_nodes")
+	c.NotContains(string(data), "total_duration")
+
+	data, err = output.MarshalBinary()
+	c.NoError(err)
+
+	futureData := append([]byte(`{"future_field":{"ignored":true},`), data[2:]...)
+
+	restored = &Output{}
+
+	c.NoError(restored.UnmarshalBinary(append([]byte{OutputFormatVersion}, futureData...)))
+	c.Equal(output.Proof, restored.Proof)
+	c.NoError(VerifyStoredOutput(restored))
+
+	c.Equal(ErrEmptyStoredOutput, restored.UnmarshalBinary(nil))
+	c.Equal(ErrUnsupportedOutputVersion, restored.UnmarshalBinary(append([]byte{OutputFormatVersion + 1}, futureData...)))
+
+	tampered := *restored
+	tampered.RelayOutput = &provider.RelayOutput{Response: `{"id":1,"jsonrpc":"2.0","result":"0xdd03e5"}`,
+		Signature: restored.RelayOutput.Signature}
+	c.True(errors.Is(VerifyStoredOutput(&tampered), ErrInvalidResponseSignature))
+
+	tamperedProof := *restored.Proof
+	tamperedProof.Entropy++
+	tampered = *restored
+	tampered.Proof = &tamperedProof
+	c.Equal(ErrInvalidProofSignature, VerifyStoredOutput(&tampered))
+
+	c.Equal(ErrIncompleteStoredOutput, VerifyStoredOutput(&Output{}))
+}
@@ -0,0 +1,55 @@
+package relayer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// RelayAll does the relay requests of all inputs concurrently with at most concurrency relays in flight
+// Outputs and errors are indexed the same way as inputs and a failing relay does not stop the others
+// Every proof is signed independently, or all in one SignAll call when the signer is a BatchSigner,
+// concurrency <= 0 does all relays at once
+func (r *Relayer) RelayAll(inputs []*Input, options *provider.RelayRequestOptions, concurrency int) ([]*Output, []error) {
+	return r.RelayAllWithContext(context.Background(), inputs, options, concurrency)
+}
+
+// RelayAllWithContext does RelayAll with every relay bounded by ctx like RelayWithContext
+func (r *Relayer) RelayAllWithContext(ctx context.Context, inputs []*Input, options *provider.RelayRequestOptions,
+	concurrency int) ([]*Output, []error) {
+	outputs := make([]*Output, len(inputs))
+	errs := make([]error, len(inputs))
+	batchInputs, relays := r.batchSignInputs(inputs, errs)
+
+	if concurrency <= 0 || concurrency > len(inputs) {
+		concurrency = len(inputs)
+	}
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				if errs[i] == nil {
+					outputs[i], errs[i] = r.relayWithContext(ctx, batchInputs[i], relays[i], options)
+				}
+			}
+		}()
+	}
+
+	for i := range inputs {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	return outputs, errs
+}
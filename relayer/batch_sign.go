@@ -0,0 +1,198 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// ErrBatchSignatureCount error when a batch signer does not return one signature per payload
+var ErrBatchSignatureCount = errors.New("batch signer returned a wrong amount of signatures")
+
+// BatchSigner interface representing a Signer able to sign many payloads in one call, like a remote HSM
+// RelayAll and RelayWithConsensus sign all their proofs with a single SignAll call when the relayer signer
+// implements it, other relays keep using Sign
+// SignAll returns the signatures in the order of the payloads, when a payload cannot be signed it should return
+// a BatchSignError with the payload index
+type BatchSigner interface {
+	Signer
+	SignAll(payloads [][]byte) ([]string, error)
+}
+
+// BatchSignError represents the thrown error when the batch signature of the proofs fails, every relay
+// of the batch fails with it
+// Index is the one of the input in RelayAll and of the node in RelayWithConsensus, -1 when the signer
+// did not tell which payload failed
+type BatchSignError struct {
+	Index int
+	Err   error
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *BatchSignError) Error() string {
+	return fmt.Sprintf("batch signing failed at payload %d: %s", e.Index, e.Err)
+}
+
+// Unwrap returns the signer error so the error can be checked with errors.Is and errors.As
+func (e *BatchSignError) Unwrap() error {
+	return e.Err
+}
+
+// batchRelay is a relay built to be signed with the others of a batch
+// index is the position of the relay in the RelayAll inputs or the RelayWithConsensus nodes
+type batchRelay struct {
+	index      int
+	input      *Input
+	relay      *provider.RelayInput
+	proofBytes []byte
+}
+
+// isRelayFor returns if the built relay is for given input session and node, a nil relay is for none
+func isRelayFor(relay *provider.RelayInput, input *Input, node *provider.Node) bool {
+	return relay != nil && relay.Proof.ServicerPubKey == node.PublicKey &&
+		relay.Proof.SessionBlockHeight == input.Session.Header.SessionHeight
+}
+
+// buildBatchRelay returns the unsigned relay of input to node, the node is chosen when it is nil
+// The input of the batch relay is a copy of input with the node set so the relay is sent to the node of the proof
+func (r *Relayer) buildBatchRelay(index int, input *Input, node *provider.Node) (*batchRelay, error) {
+	err := r.validateRelayRequest(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if node == nil {
+		node, err = r.getNode(input)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	relay, proofBytes, err := r.buildUnsignedRelay(input, node, &RelayTrace{})
+	if err != nil {
+		return nil, err
+	}
+
+	batchInput := *input
+	batchInput.Node = node
+
+	return &batchRelay{index: index, input: &batchInput, relay: relay, proofBytes: proofBytes}, nil
+}
+
+// signBatch signs the proofs of given relays with a single SignAll call, a failure fails the whole batch
+func signBatch(signer BatchSigner, relays []*batchRelay) error {
+	payloads := make([][]byte, len(relays))
+	for i, relay := range relays {
+		payloads[i] = relay.proofBytes
+	}
+
+	signatures, err := signer.SignAll(payloads)
+	if err != nil {
+		return getBatchSignError(relays, err)
+	}
+
+	if len(signatures) != len(relays) {
+		return &BatchSignError{Index: -1, Err: ErrBatchSignatureCount}
+	}
+
+	for i, signature := range signatures {
+		if signature == "" {
+			return &BatchSignError{Index: relays[i].index, Err: ErrMissingProofSignature}
+		}
+
+		relays[i].relay.Proof.Signature = signature
+	}
+
+	return nil
+}
+
+// getBatchSignError returns the SignAll error with the index of the failing relay instead of its payload index
+func getBatchSignError(relays []*batchRelay, err error) *BatchSignError {
+	var batchErr *BatchSignError
+	if !errors.As(err, &batchErr) {
+		return &BatchSignError{Index: -1, Err: err}
+	}
+
+	if batchErr.Index < 0 || batchErr.Index >= len(relays) {
+		return &BatchSignError{Index: -1, Err: batchErr.Err}
+	}
+
+	return &BatchSignError{Index: relays[batchErr.Index].index, Err: batchErr.Err}
+}
+
+// batchSignInputs signs the relays of given inputs in one batch when the signer is a BatchSigner
+// It returns the input and signed relay to relay every input with, inputs whose relay cannot be built have
+// a nil relay and are relayed as usual so they fail like Relay, and a failed batch signature is set
+// as the error of every batched input
+func (r *Relayer) batchSignInputs(inputs []*Input, errs []error) ([]*Input, []*provider.RelayInput) {
+	batchInputs := append([]*Input(nil), inputs...)
+	signedRelays := make([]*provider.RelayInput, len(inputs))
+
+	batchSigner, ok := r.signer.(BatchSigner)
+	if !ok {
+		return batchInputs, signedRelays
+	}
+
+	relays := []*batchRelay{}
+
+	for i, input := range inputs {
+		relay, err := r.buildBatchRelay(i, input, nil)
+		if err == nil {
+			relays = append(relays, relay)
+		}
+	}
+
+	if len(relays) == 0 {
+		return batchInputs, signedRelays
+	}
+
+	err := signBatch(batchSigner, relays)
+
+	for _, relay := range relays {
+		if err != nil {
+			errs[relay.index] = err
+
+			continue
+		}
+
+		batchInputs[relay.index] = relay.input
+		signedRelays[relay.index] = relay.relay
+	}
+
+	return batchInputs, signedRelays
+}
+
+// batchSignNodes signs the relays of input to every node in one batch when the signer is a BatchSigner
+// It returns the signed relay to every node, nil relays when the signer is not a BatchSigner
+func (r *Relayer) batchSignNodes(input *Input, nodes []*provider.Node) ([]*provider.RelayInput, error) {
+	signedRelays := make([]*provider.RelayInput, len(nodes))
+
+	batchSigner, ok := r.signer.(BatchSigner)
+	if !ok {
+		return signedRelays, nil
+	}
+
+	relays := make([]*batchRelay, len(nodes))
+
+	for i, node := range nodes {
+		relay, err := r.buildBatchRelay(i, input, node)
+		if err != nil {
+			return nil, err
+		}
+
+		relays[i] = relay
+	}
+
+	err := signBatch(batchSigner, relays)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, relay := range relays {
+		signedRelays[i] = relay.relay
+	}
+
+	return signedRelays, nil
+}
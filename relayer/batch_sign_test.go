@@ -0,0 +1,204 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+type batchSignerMock struct {
+	signer        *signer.Signer
+	signCalls     int64
+	signAllCalls  int64
+	failAt        int
+	dropSignature bool
+}
+
+func (s *batchSignerMock) Sign(payload []byte) (string, error) {
+	atomic.AddInt64(&s.signCalls, 1)
+
+	return s.signer.Sign(payload)
+}
+
+func (s *batchSignerMock) SignAll(payloads [][]byte) ([]string, error) {
+	atomic.AddInt64(&s.signAllCalls, 1)
+
+	signatures := make([]string, len(payloads))
+
+	for i, payload := range payloads {
+		if i == s.failAt {
+			return nil, &BatchSignError{Index: i, Err: errors.New("hsm unavailable")}
+		}
+
+		signature, err := s.signer.Sign(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		signatures[i] = signature
+	}
+
+	if s.dropSignature {
+		return signatures[1:], nil
+	}
+
+	return signatures, nil
+}
+
+func requireBatchSignedProof(c *require.Assertions, signer *signer.Signer, output *Output) {
+	proofBytes, err := GenerateProofBytes(output.Proof)
+	c.NoError(err)
+
+	signature, err := signer.Sign(proofBytes)
+	c.NoError(err)
+	c.Equal(signature, output.Proof.Signature)
+	c.Equal(output.Node.PublicKey, output.Proof.ServicerPubKey)
+}
+
+func TestRelayer_RelayAllBatchSigner(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	batchSigner := &batchSignerMock{signer: signer, failAt: -1}
+	relayer := NewRelayer(batchSigner, &providerMock{})
+	inputs := newBatchInputs(8)
+
+	outputs, errs := relayer.RelayAll(inputs, nil, 3)
+	c.Equal(int64(1), batchSigner.signAllCalls)
+	c.Zero(batchSigner.signCalls)
+
+	for i, output := range outputs {
+		c.NoError(errs[i])
+		c.Equal(inputs[i].Data, output.RelayOutput.Response)
+		c.Nil(inputs[i].Node)
+		requireBatchSignedProof(c, signer, output)
+	}
+
+	inputs[0].Session = nil
+	batchSigner.failAt = 1
+
+	outputs, errs = relayer.RelayAll(inputs, nil, 3)
+	c.Equal(int64(2), batchSigner.signAllCalls)
+	c.Zero(batchSigner.signCalls)
+	c.Equal(ErrNoSession, errs[0])
+
+	for i := 1; i < len(inputs); i++ {
+		var batchErr *BatchSignError
+
+		c.ErrorAs(errs[i], &batchErr)
+		c.Equal(2, batchErr.Index)
+		c.EqualError(batchErr.Err, "hsm unavailable")
+		c.Empty(outputs[i])
+	}
+
+	batchSigner.failAt = -1
+	batchSigner.dropSignature = true
+
+	_, errs = relayer.RelayAll(inputs[1:], nil, 0)
+
+	for _, err := range errs {
+		c.True(errors.Is(err, ErrBatchSignatureCount))
+	}
+
+	outputs, errs = NewRelayer(signer, &providerMock{}).RelayAll(inputs[1:], nil, 0)
+
+	for i, output := range outputs {
+		c.NoError(errs[i])
+		requireBatchSignedProof(c, signer, output)
+	}
+}
+
+func TestRelayer_RelayWithConsensusBatchSigner(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	batchSigner := &batchSignerMock{signer: signer, failAt: -1}
+	relayer := NewRelayer(batchSigner, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+	input := newConsensusInput()
+
+	addMockedNodeRelay("https://aog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+	addMockedNodeRelay("https://pjog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+	addMockedNodeRelay("https://ohana.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	output, err := relayer.RelayWithConsensus(input, nil)
+	c.NoError(err)
+	c.Equal(int64(1), batchSigner.signAllCalls)
+	c.Zero(batchSigner.signCalls)
+
+	for i, nodeOutput := range output.Outputs {
+		c.Equal(output.Nodes[i], nodeOutput.Node)
+		requireBatchSignedProof(c, signer, nodeOutput)
+	}
+
+	batchSigner.failAt = 2
+
+	output, err = relayer.RelayWithConsensus(input, nil)
+	c.Equal(&BatchSignError{Index: 2, Err: errors.New("hsm unavailable")}, err)
+	c.Empty(output)
+}
+
+type callerContextKey struct{}
+
+func TestRelayer_BatchSignerWithContext(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	var callerContexts int64
+
+	batchSigner := &batchSignerMock{signer: signer, failAt: -1}
+	relayer := NewRelayer(batchSigner, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+		WithRelayMiddleware(func(next RelayHandler) RelayHandler {
+			return func(ctx context.Context, input *Input, node *provider.Node,
+				options *provider.RelayRequestOptions) (*Output, error) {
+				if ctx.Value(callerContextKey{}) != nil {
+					atomic.AddInt64(&callerContexts, 1)
+				}
+
+				return next(ctx, input, node, options)
+			}
+		}))
+	ctx := context.WithValue(context.Background(), callerContextKey{}, true)
+
+	addMockedNodeRelay("https://aog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+	addMockedNodeRelay("https://pjog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+	addMockedNodeRelay("https://ohana.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	output, err := relayer.RelayWithConsensusContext(ctx, newConsensusInput(), nil)
+	c.NoError(err)
+	c.Equal(int64(3), callerContexts)
+
+	for _, nodeOutput := range output.Outputs {
+		requireBatchSignedProof(c, signer, nodeOutput)
+	}
+
+	inputs := []*Input{newConsensusInput(), newConsensusInput()}
+
+	outputs, errs := relayer.RelayAllWithContext(ctx, inputs, nil, 0)
+	c.Equal(int64(5), callerContexts)
+	c.Equal(int64(2), batchSigner.signAllCalls)
+	c.Zero(batchSigner.signCalls)
+
+	for i, output := range outputs {
+		c.NoError(errs[i])
+		requireBatchSignedProof(c, signer, output)
+	}
+}
@@ -0,0 +1,114 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/stretchr/testify/require"
+)
+
+type providerMock struct {
+	delay       time.Duration
+	inFlight    int64
+	maxInFlight int64
+}
+
+func (p *providerMock) Relay(rpcURL string, input *provider.RelayInput, options *provider.RelayRequestOptions) (*provider.RelayOutput, error) {
+	inFlight := atomic.AddInt64(&p.inFlight, 1)
+	defer atomic.AddInt64(&p.inFlight, -1)
+
+	for {
+		maxInFlight := atomic.LoadInt64(&p.maxInFlight)
+		if inFlight <= maxInFlight || atomic.CompareAndSwapInt64(&p.maxInFlight, maxInFlight, inFlight) {
+			break
+		}
+	}
+
+	time.Sleep(p.delay)
+
+	if input.Payload.Data == "fail" {
+		return nil, provider.Err5xxOnConnection
+	}
+
+	return &provider.RelayOutput{Response: input.Payload.Data, Signature: "abf"}, nil
+}
+
+func newBatchInputs(count int) []*Input {
+	session := newSelectorSession()
+	session.Header.SessionHeight = 21
+
+	inputs := make([]*Input, count)
+	for i := range inputs {
+		inputs[i] = &Input{
+			Blockchain: "0021",
+			Data:       fmt.Sprintf(`{"id":%d}`, i),
+			ViperAAT:   &provider.ViperAAT{},
+			Session:    session,
+		}
+	}
+
+	return inputs
+}
+
+func TestRelayer_RelayAll(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	mockProvider := &providerMock{delay: 5 * time.Millisecond}
+	relayer := NewRelayer(signer, mockProvider)
+
+	inputs := newBatchInputs(20)
+	inputs[7].Data = "fail"
+	inputs[12].Session = nil
+
+	outputs, errs := relayer.RelayAll(inputs, nil, 4)
+	c.Len(outputs, 20)
+	c.Len(errs, 20)
+	c.LessOrEqual(mockProvider.maxInFlight, int64(4))
+
+	signatures := map[string]bool{}
+
+	for i, output := range outputs {
+		switch i {
+		case 7:
+			c.True(errors.Is(errs[i], provider.Err5xxOnConnection))
+			c.Empty(output)
+		case 12:
+			c.Equal(ErrNoSession, errs[i])
+			c.Empty(output)
+		default:
+			c.NoError(errs[i])
+			c.Equal(fmt.Sprintf(`{"id":%d}`, i), output.RelayOutput.Response)
+			signatures[output.Proof.Signature] = true
+		}
+	}
+	c.Len(signatures, 18)
+
+	outputs, errs = relayer.RelayAll(nil, nil, 4)
+	c.Empty(outputs)
+	c.Empty(errs)
+}
+
+func BenchmarkRelayer_RelayAll(b *testing.B) {
+	signer, err := signer.NewRandomSigner()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	relayer := NewRelayer(signer, &providerMock{})
+	inputs := newBatchInputs(50)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		relayer.RelayAll(inputs, nil, 10)
+	}
+}
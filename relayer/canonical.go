@@ -0,0 +1,96 @@
+package relayer
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// canonicalField is a key of a canonical JSON object with its value
+type canonicalField struct {
+	key   string
+	value interface{}
+}
+
+// CanonicalProofJSON returns the JSON of given relay proof hashed by GenerateProofBytes, with the AAT hashed
+// with DefaultHasher, use it to compare with the proofs logged by servicers when a signature is rejected
+// Returns ErrNoProof for a nil proof and ErrNoViperAAT for a proof without AAT
+func CanonicalProofJSON(proof *provider.RelayProof) ([]byte, error) {
+	if proof == nil {
+		return nil, ErrNoProof
+	}
+
+	token, err := HashAAT(proof.AAT)
+	if err != nil {
+		return nil, err
+	}
+
+	return canonicalProofJSON(proof, token)
+}
+
+// canonicalProofJSON returns the JSON of given relay proof signed by the client using the already hashed AAT token
+// The field order is the one expected by the network and the signature is always empty
+func canonicalProofJSON(proof *provider.RelayProof, token string) ([]byte, error) {
+	return encodeCanonicalObject(
+		canonicalField{key: "entropy", value: proof.Entropy},
+		canonicalField{key: "session_block_height", value: proof.SessionBlockHeight},
+		canonicalField{key: "servicer_pub_key", value: proof.ServicerPubKey},
+		canonicalField{key: "blockchain", value: proof.Blockchain},
+		canonicalField{key: "signature", value: ""},
+		canonicalField{key: "token", value: token},
+		canonicalField{key: "request_hash", value: proof.RequestHash},
+	)
+}
+
+// canonicalAATJSON returns the JSON of given AAT hashed into the proof token, the signature is always empty
+func canonicalAATJSON(aat *provider.ViperAAT) ([]byte, error) {
+	return encodeCanonicalObject(
+		canonicalField{key: "version", value: aat.Version},
+		canonicalField{key: "app_pub_key", value: aat.AppPubKey},
+		canonicalField{key: "client_pub_key", value: aat.ClientPubKey},
+		canonicalField{key: "signature", value: ""},
+	)
+}
+
+// encodeCanonicalObject returns the JSON object of given fields in their order, without whitespace
+// and without escaping HTML characters, so struct changes can never change the signed bytes
+func encodeCanonicalObject(fields ...canonicalField) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+
+	buffer.WriteByte('{')
+
+	for i, field := range fields {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		if err := encodeCanonicalValue(&buffer, encoder, field.key); err != nil {
+			return nil, err
+		}
+
+		buffer.WriteByte(':')
+
+		if err := encodeCanonicalValue(&buffer, encoder, field.value); err != nil {
+			return nil, err
+		}
+	}
+
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+// encodeCanonicalValue writes the JSON of value to buffer without the newline added by the encoder
+func encodeCanonicalValue(buffer *bytes.Buffer, encoder *json.Encoder, value interface{}) error {
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+
+	buffer.Truncate(buffer.Len() - 1)
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package relayer
+
+import (
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newCanonicalProofVectors() map[string]*provider.RelayProof {
+	request, aat := newHasherVectors()
+
+	requestHash, _ := HashRequest(request)
+
+	return map[string]*provider.RelayProof{
+		"samples/canonical_proof.json": {
+			RequestHash:        requestHash,
+			Entropy:            21,
+			SessionBlockHeight: 21,
+			ServicerPubKey:     "AOG",
+			Blockchain:         "0021",
+			AAT:                aat,
+			Signature:          "IJKL",
+		},
+		"samples/canonical_proof_escaping.json": {
+			RequestHash:        "<a&b>",
+			Entropy:            -9223372036854775808,
+			SessionBlockHeight: 0,
+			ServicerPubKey:     "\"quoted\"\n",
+			Blockchain:         "\u2028",
+			AAT:                &provider.ViperAAT{Version: "<0.0.1>", AppPubKey: "&", ClientPubKey: "\\"},
+		},
+	}
+}
+
+func TestCanonicalProofJSON_Golden(t *testing.T) {
+	c := require.New(t)
+
+	for file, proof := range newCanonicalProofVectors() {
+		golden, err := os.ReadFile(file)
+		c.NoError(err)
+
+		canonical, err := CanonicalProofJSON(proof)
+		c.NoError(err)
+		c.Equal(string(golden), string(canonical), file)
+
+		proofBytes, err := GenerateProofBytes(proof)
+		c.NoError(err)
+		c.Equal(DefaultHasher.Hash(golden), proofBytes, file)
+	}
+
+	_, err := CanonicalProofJSON(nil)
+	c.Equal(ErrNoProof, err)
+
+	_, err = CanonicalProofJSON(&provider.RelayProof{})
+	c.Equal(ErrNoViperAAT, err)
+}
+
+func TestCanonicalAATJSON_Golden(t *testing.T) {
+	c := require.New(t)
+
+	_, aat := newHasherVectors()
+
+	golden, err := os.ReadFile("samples/canonical_aat.json")
+	c.NoError(err)
+
+	canonical, err := canonicalAATJSON(aat)
+	c.NoError(err)
+	c.Equal(string(golden), string(canonical))
+
+	hash, err := HashAAT(aat)
+	c.NoError(err)
+	c.Equal(hex.EncodeToString(DefaultHasher.Hash(golden)), hash)
+}
@@ -0,0 +1,103 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// minChallengeMajority is the least number of majority outputs a challenge needs
+const minChallengeMajority = 2
+
+var (
+	// ErrNotEnoughMajorityOutputs error when a challenge has less than two majority outputs
+	ErrNotEnoughMajorityOutputs = errors.New("not enough majority outputs for challenge")
+	// ErrIncompleteChallengeOutput error when a challenge output lacks its relay output, proof or node
+	ErrIncompleteChallengeOutput = errors.New("incomplete challenge output")
+	// ErrDuplicateChallengeNode error when two challenge outputs come from the same node
+	ErrDuplicateChallengeNode = errors.New("challenge outputs must come from distinct nodes")
+	// ErrChallengeRequestHashMismatch error when challenge outputs are for different requests
+	ErrChallengeRequestHashMismatch = errors.New("challenge outputs request hash mismatch")
+	// ErrChallengeSessionMismatch error when challenge outputs are from different sessions
+	ErrChallengeSessionMismatch = errors.New("challenge outputs session mismatch")
+)
+
+// BuildChallenge returns the challenge of the minority output against the majority outputs
+// like the ones of a ConsensusError
+// There must be at least two majority outputs and every output must come from a distinct node
+// and be for the same request hash, session height, blockchain and app
+func BuildChallenge(majority []*Output, minority *Output) (*provider.ChallengeInput, error) {
+	if len(majority) < minChallengeMajority {
+		return nil, ErrNotEnoughMajorityOutputs
+	}
+
+	outputs := append(append([]*Output{}, majority...), minority)
+
+	err := validateChallengeOutputs(outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &provider.ChallengeInput{
+		MajorityResponses: make([]*provider.ChallengeResponse, 0, len(majority)),
+		MinorityResponse:  newChallengeResponse(minority),
+	}
+
+	for _, output := range majority {
+		challenge.MajorityResponses = append(challenge.MajorityResponses, newChallengeResponse(output))
+	}
+
+	return challenge, nil
+}
+
+func validateChallengeOutputs(outputs []*Output) error {
+	nodes := map[string]bool{}
+
+	for _, output := range outputs {
+		if !isCompleteChallengeOutput(output) {
+			return ErrIncompleteChallengeOutput
+		}
+
+		if nodes[output.Node.PublicKey] {
+			return fmt.Errorf("%w: node %s", ErrDuplicateChallengeNode, output.Node.PublicKey)
+		}
+
+		nodes[output.Node.PublicKey] = true
+
+		err := validateChallengeProof(outputs[0].Proof, output.Proof)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isCompleteChallengeOutput(output *Output) bool {
+	return output != nil && output.RelayOutput != nil && output.Proof != nil && output.Proof.AAT != nil && output.Node != nil
+}
+
+func validateChallengeProof(expected, proof *provider.RelayProof) error {
+	if proof.RequestHash != expected.RequestHash {
+		return fmt.Errorf("%w: node %s request hash %s, expected %s", ErrChallengeRequestHashMismatch,
+			proof.ServicerPubKey, proof.RequestHash, expected.RequestHash)
+	}
+
+	if proof.SessionBlockHeight != expected.SessionBlockHeight || proof.Blockchain != expected.Blockchain ||
+		proof.AAT.AppPubKey != expected.AAT.AppPubKey {
+		return fmt.Errorf("%w: node %s session height %d chain %s app %s, expected height %d chain %s app %s",
+			ErrChallengeSessionMismatch, proof.ServicerPubKey, proof.SessionBlockHeight, proof.Blockchain,
+			proof.AAT.AppPubKey, expected.SessionBlockHeight, expected.Blockchain, expected.AAT.AppPubKey)
+	}
+
+	return nil
+}
+
+func newChallengeResponse(output *Output) *provider.ChallengeResponse {
+	return &provider.ChallengeResponse{
+		Signature: output.RelayOutput.Signature,
+		Payload:   output.RelayOutput.Response,
+		Proof:     output.Proof,
+	}
+}
@@ -0,0 +1,95 @@
+package relayer
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildChallenge(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+	input := newConsensusInput()
+
+	addMockedNodeRelay("https://aog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+	addMockedNodeRelay("https://pjog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e5"}`)
+	addMockedNodeRelay("https://ohana.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	_, err = relayer.RelayWithConsensus(input, &ConsensusOptions{Threshold: 3})
+	c.True(errors.Is(err, ErrNoConsensus))
+
+	var consensusErr *ConsensusError
+
+	c.ErrorAs(err, &consensusErr)
+
+	var majority []*Output
+	var minority *Output
+
+	for _, output := range consensusErr.Outputs {
+		if output.Node.PublicKey == "PJOG" {
+			minority = output
+		} else {
+			majority = append(majority, output)
+		}
+	}
+
+	challenge, err := BuildChallenge(majority, minority)
+	c.NoError(err)
+	c.Len(challenge.MajorityResponses, 2)
+	c.Empty(challenge.Address)
+
+	for i, response := range challenge.MajorityResponses {
+		c.Equal(`{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`, response.Payload)
+		c.Equal("abf", response.Signature)
+		c.Equal(majority[i].Proof, response.Proof)
+	}
+
+	c.Equal(`{"id":1,"jsonrpc":"2.0","result":"0xdd03e5"}`, challenge.MinorityResponse.Payload)
+	c.Equal("PJOG", challenge.MinorityResponse.Proof.ServicerPubKey)
+	c.Equal(challenge.MajorityResponses[0].Proof.RequestHash, challenge.MinorityResponse.Proof.RequestHash)
+
+	marshaledChallenge, err := json.Marshal(challenge)
+	c.NoError(err)
+
+	fields := map[string]json.RawMessage{}
+	c.NoError(json.Unmarshal(marshaledChallenge, &fields))
+	c.Contains(fields, "majority_responses")
+	c.Contains(fields, "minority_response")
+	c.Contains(fields, "address")
+
+	_, err = BuildChallenge(majority[:1], minority)
+	c.Equal(ErrNotEnoughMajorityOutputs, err)
+
+	_, err = BuildChallenge([]*Output{majority[0], majority[0]}, minority)
+	c.True(errors.Is(err, ErrDuplicateChallengeNode))
+
+	_, err = BuildChallenge(majority, &Output{Node: minority.Node})
+	c.Equal(ErrIncompleteChallengeOutput, err)
+
+	otherRequest := *minority.Proof
+	otherRequest.RequestHash = "ABCD"
+
+	_, err = BuildChallenge(majority, &Output{RelayOutput: minority.RelayOutput, Proof: &otherRequest, Node: minority.Node})
+	c.True(errors.Is(err, ErrChallengeRequestHashMismatch))
+	c.Contains(err.Error(), "ABCD")
+
+	otherSession := *minority.Proof
+	otherSession.SessionBlockHeight = 25
+
+	_, err = BuildChallenge(majority, &Output{RelayOutput: minority.RelayOutput, Proof: &otherSession, Node: minority.Node})
+	c.True(errors.Is(err, ErrChallengeSessionMismatch))
+	c.Contains(err.Error(), "PJOG")
+}
@@ -0,0 +1,58 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// ErrNodeBusy error when a node has no free relay slot before the context is done
+var ErrNodeBusy = errors.New("node busy")
+
+// NodeBusyError represents the thrown error when the concurrency limit of a node is reached
+type NodeBusyError struct {
+	Node *provider.Node
+	Err  error
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *NodeBusyError) Error() string {
+	return fmt.Sprintf("%s: %s with ServicerPubKey: %s", ErrNodeBusy, e.Err, e.Node.PublicKey)
+}
+
+// Unwrap returns ErrNodeBusy so the error can be checked with errors.Is
+func (e *NodeBusyError) Unwrap() error {
+	return ErrNodeBusy
+}
+
+// SetPerNodeConcurrencyLimit sets the max amount of in flight relays per node, n <= 0 disables the limit
+func (r *Relayer) SetPerNodeConcurrencyLimit(n int) {
+	atomic.StoreInt64(&r.perNodeConcurrencyLimit, int64(n))
+
+	r.nodeSemaphores.Range(func(key, _ any) bool {
+		r.nodeSemaphores.Delete(key)
+		return true
+	})
+}
+
+// acquireNodeSlot waits for a free slot of the given node and returns the function to release it
+func (r *Relayer) acquireNodeSlot(ctx context.Context, node *provider.Node) (func(), error) {
+	limit := atomic.LoadInt64(&r.perNodeConcurrencyLimit)
+	if limit <= 0 {
+		return func() {}, nil
+	}
+
+	rawSemaphore, _ := r.nodeSemaphores.LoadOrStore(node.PublicKey, make(chan struct{}, limit))
+	semaphore := rawSemaphore.(chan struct{})
+
+	select {
+	case semaphore <- struct{}{}:
+		return func() { <-semaphore }, nil
+	case <-ctx.Done():
+		return nil, &NodeBusyError{Node: node, Err: ctx.Err()}
+	}
+}
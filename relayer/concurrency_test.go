@@ -0,0 +1,73 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/utils-go/mock-client"
+)
+
+func TestRelayer_SetPerNodeConcurrencyLimit(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+	node := &provider.Node{PublicKey: "AOG", ServiceURL: "https://dummy.com"}
+	input := &Input{
+		ViperAAT: &provider.ViperAAT{},
+		Node:     node,
+		Session: &provider.Session{
+			Header: &provider.SessionHeader{},
+			Nodes:  []*provider.Node{node},
+		},
+	}
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", provider.ClientRelayRoute),
+		http.StatusOK, "../provider/samples/client_relay.json")
+
+	relayer.SetPerNodeConcurrencyLimit(1)
+
+	release, err := relayer.acquireNodeSlot(context.Background(), node)
+	c.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	relay, err := relayer.RelayWithContext(ctx, input, nil)
+	c.ErrorIs(err, ErrNodeBusy)
+	c.Empty(relay)
+
+	var nodeBusyErr *NodeBusyError
+
+	c.ErrorAs(err, &nodeBusyErr)
+	c.Equal(node, nodeBusyErr.Node)
+	c.ErrorIs(nodeBusyErr.Err, context.DeadlineExceeded)
+
+	release()
+
+	relay, err = relayer.RelayWithContext(context.Background(), input, nil)
+	c.NoError(err)
+	c.NotEmpty(relay)
+
+	relayer.SetPerNodeConcurrencyLimit(0)
+
+	_, err = relayer.acquireNodeSlot(context.Background(), node)
+	c.NoError(err)
+
+	relay, err = relayer.RelayWithContext(ctx, input, nil)
+	c.NoError(err)
+	c.NotEmpty(relay)
+}
@@ -0,0 +1,327 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+var (
+	// ErrNoConsensus error when not enough session nodes agree on a relay response
+	ErrNoConsensus = errors.New("no consensus between session nodes")
+	// ErrNotEnoughSessionNodes error when consensus requires more nodes than the session has
+	ErrNotEnoughSessionNodes = errors.New("not enough session nodes for consensus")
+	// ErrInvalidConsensusThreshold error when consensus threshold is negative or greater than the node count
+	ErrInvalidConsensusThreshold = errors.New("invalid consensus threshold")
+	// ErrInvalidNodeCount error when a negative amount of session nodes is requested
+	ErrInvalidNodeCount = errors.New("invalid node count")
+)
+
+// ResponseComparator reports if two relay responses of different nodes agree
+type ResponseComparator func(first, second string) bool
+
+// ConsensusOptions represents optional arguments for RelayWithConsensus request
+// NodeCount = 0 relays to all session nodes, Threshold = 0 requires a strict majority of NodeCount
+// Responses agree when their NormalizeResponse forms without the IgnoredFields object keys are equal,
+// Comparator replaces that comparison when set
+type ConsensusOptions struct {
+	NodeCount     int
+	Threshold     int
+	RelayOptions  *provider.RelayRequestOptions
+	IgnoredFields []string
+	Comparator    ResponseComparator
+}
+
+// ConsensusOutput struct for data needed as output for consensus relay request
+// Outputs and Errors are indexed the same way as Nodes, failed relays have a nil output
+type ConsensusOutput struct {
+	Output  *Output
+	Nodes   []*provider.Node
+	Outputs []*Output
+	Errors  []error
+}
+
+// ConsensusError represents the thrown error when session nodes do not agree on a response
+// It carries every relay output so a challenge can be built from them
+type ConsensusError struct {
+	Nodes   []*provider.Node
+	Outputs []*Output
+	Errors  []error
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *ConsensusError) Error() string {
+	return fmt.Sprintf("%s: %d nodes relayed", ErrNoConsensus, len(e.Nodes))
+}
+
+// Unwrap returns ErrNoConsensus so the error can be checked with errors.Is
+func (e *ConsensusError) Unwrap() error {
+	return ErrNoConsensus
+}
+
+func getConsensusCounts(session *provider.Session, options *ConsensusOptions) (int, int, error) {
+	nodeCount := len(session.Nodes)
+	threshold := 0
+
+	if options != nil {
+		if options.NodeCount != 0 {
+			nodeCount = options.NodeCount
+		}
+
+		threshold = options.Threshold
+	}
+
+	if nodeCount < 0 {
+		return 0, 0, ErrInvalidNodeCount
+	}
+
+	if nodeCount > len(session.Nodes) {
+		return 0, 0, ErrNotEnoughSessionNodes
+	}
+
+	if threshold == 0 {
+		threshold = nodeCount/2 + 1
+	}
+
+	if threshold < 0 || threshold > nodeCount {
+		return 0, 0, ErrInvalidConsensusThreshold
+	}
+
+	return nodeCount, threshold, nil
+}
+
+// GetRandomSessionNodes returns the given amount of distinct random nodes from given session
+// Returns ErrInvalidNodeCount for a negative count and ErrNotEnoughSessionNodes when the session has less nodes
+func GetRandomSessionNodes(session *provider.Session, count int) ([]*provider.Node, error) {
+	if count < 0 {
+		return nil, ErrInvalidNodeCount
+	}
+
+	if count > len(session.Nodes) {
+		return nil, ErrNotEnoughSessionNodes
+	}
+
+	nodes := make([]*provider.Node, len(session.Nodes))
+	copy(nodes, session.Nodes)
+
+	for i := len(nodes) - 1; i > 0; i-- {
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return nil, err
+		}
+
+		j := index.Int64()
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+
+	return nodes[:count], nil
+}
+
+// NormalizeResponse returns the relay response in a form that can be compared between nodes
+// JSON responses are compacted with sorted object keys, other responses are just trimmed
+func NormalizeResponse(response string) string {
+	return NormalizeResponseWithoutFields(response, nil)
+}
+
+// NormalizeResponseWithoutFields does NormalizeResponse dropping the object keys in ignoredFields at any depth,
+// like per node request IDs or timestamps
+func NormalizeResponseWithoutFields(response string, ignoredFields []string) string {
+	var decoded any
+
+	decoder := json.NewDecoder(strings.NewReader(response))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&decoded); err != nil || decoder.Decode(&struct{}{}) != io.EOF {
+		return strings.TrimSpace(response)
+	}
+
+	if len(ignoredFields) != 0 {
+		ignored := make(map[string]bool, len(ignoredFields))
+		for _, field := range ignoredFields {
+			ignored[field] = true
+		}
+
+		dropFields(decoded, ignored)
+	}
+
+	normalized, err := json.Marshal(decoded)
+	if err != nil {
+		return strings.TrimSpace(response)
+	}
+
+	return string(bytes.TrimSpace(normalized))
+}
+
+// dropFields deletes the ignored keys of the objects in given decoded JSON value
+func dropFields(value any, ignored map[string]bool) {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		for key, field := range typedValue {
+			if ignored[key] {
+				delete(typedValue, key)
+
+				continue
+			}
+
+			dropFields(field, ignored)
+		}
+	case []any:
+		for _, element := range typedValue {
+			dropFields(element, ignored)
+		}
+	}
+}
+
+// RelayWithConsensus does the same relay request to many session nodes concurrently
+// and returns the response only if at least the threshold of nodes agree on it
+// The proofs are signed in one SignAll call when the signer is a BatchSigner, see BatchSignError
+func (r *Relayer) RelayWithConsensus(input *Input, options *ConsensusOptions) (*ConsensusOutput, error) {
+	return r.RelayWithConsensusContext(context.Background(), input, options)
+}
+
+// RelayWithConsensusContext does RelayWithConsensus with every relay bounded by ctx like RelayWithContext
+func (r *Relayer) RelayWithConsensusContext(ctx context.Context, input *Input,
+	options *ConsensusOptions) (*ConsensusOutput, error) {
+	defer r.trackRelay()()
+
+	startTime := time.Now()
+
+	err := r.validateRelayRequest(input)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeCount, threshold, err := getConsensusCounts(input.Session, options)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := GetRandomSessionNodes(input.Session, nodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	var relayOptions *provider.RelayRequestOptions
+	if options != nil {
+		relayOptions = options.RelayOptions
+	}
+
+	relays, err := r.batchSignNodes(input, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	comparator := getResponseComparator(options)
+
+	output := &ConsensusOutput{
+		Nodes:   nodes,
+		Outputs: make([]*Output, len(nodes)),
+		Errors:  make([]error, len(nodes)),
+	}
+
+	var wg sync.WaitGroup
+
+	for i, node := range nodes {
+		wg.Add(1)
+
+		go func(i int, node *provider.Node) {
+			defer wg.Done()
+
+			output.Outputs[i], output.Errors[i] = r.relayToNode(ctx, input, node, relays[i], relayOptions, i)
+		}(i, node)
+	}
+
+	wg.Wait()
+
+	output.Output = getMajorityOutput(output.Outputs, threshold, comparator)
+	if output.Output == nil {
+		return nil, &ConsensusError{
+			Nodes:   output.Nodes,
+			Outputs: output.Outputs,
+			Errors:  output.Errors,
+		}
+	}
+
+	output.Output.Attempts = len(nodes)
+	output.Output.TriedNodes = getPublicKeys(nodes)
+	output.Output.TotalDuration = time.Since(startTime)
+
+	return output, nil
+}
+
+// getPublicKeys returns the public keys of given nodes in their order
+func getPublicKeys(nodes []*provider.Node) []string {
+	publicKeys := make([]string, len(nodes))
+
+	for i, node := range nodes {
+		publicKeys[i] = node.PublicKey
+	}
+
+	return publicKeys
+}
+
+// getResponseComparator returns the comparator of the options or the comparison of the normalized responses
+func getResponseComparator(options *ConsensusOptions) ResponseComparator {
+	if options != nil && options.Comparator != nil {
+		return options.Comparator
+	}
+
+	var ignoredFields []string
+	if options != nil {
+		ignoredFields = options.IgnoredFields
+	}
+
+	return func(first, second string) bool {
+		return NormalizeResponseWithoutFields(first, ignoredFields) == NormalizeResponseWithoutFields(second, ignoredFields)
+	}
+}
+
+type responseVotes struct {
+	output *Output
+	votes  int
+}
+
+// getMajorityOutput returns the first output whose response agrees with threshold outputs, nil if there is none
+func getMajorityOutput(outputs []*Output, threshold int, comparator ResponseComparator) *Output {
+	groups := []*responseVotes{}
+
+	for _, output := range outputs {
+		if output == nil {
+			continue
+		}
+
+		group := getResponseGroup(groups, output, comparator)
+		if group == nil {
+			group = &responseVotes{output: output}
+			groups = append(groups, group)
+		}
+
+		group.votes++
+		if group.votes >= threshold {
+			return group.output
+		}
+	}
+
+	return nil
+}
+
+func getResponseGroup(groups []*responseVotes, output *Output, comparator ResponseComparator) *responseVotes {
+	for _, group := range groups {
+		if comparator(group.output.RelayOutput.Response, output.RelayOutput.Response) {
+			return group
+		}
+	}
+
+	return nil
+}
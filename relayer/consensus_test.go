@@ -0,0 +1,235 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/utils-go/mock-client"
+)
+
+func addMockedNodeRelay(serviceURL, response string) {
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", serviceURL, provider.ClientRelayRoute), http.StatusOK,
+		fmt.Sprintf(`{"response": %q, "signature": "abf"}`, response))
+}
+
+func newConsensusInput() *Input {
+	return &Input{
+		Blockchain: "0021",
+		Data:       `{"method":"eth_blockNumber","id":1,"jsonrpc":"2.0"}`,
+		ViperAAT:   &provider.ViperAAT{},
+		Session: &provider.Session{
+			Header: &provider.SessionHeader{SessionHeight: 21},
+			Nodes: []*provider.Node{
+				{PublicKey: "AOG", ServiceURL: "https://aog.com"},
+				{PublicKey: "PJOG", ServiceURL: "https://pjog.com"},
+				{PublicKey: "OHANA", ServiceURL: "https://ohana.com"},
+			},
+		},
+	}
+}
+
+func TestRelayer_RelayWithConsensus(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+	input := newConsensusInput()
+
+	output, err := relayer.RelayWithConsensus(input, &ConsensusOptions{NodeCount: 4})
+	c.Equal(ErrNotEnoughSessionNodes, err)
+	c.Empty(output)
+
+	output, err = relayer.RelayWithConsensus(input, &ConsensusOptions{NodeCount: 2, Threshold: 3})
+	c.Equal(ErrInvalidConsensusThreshold, err)
+	c.Empty(output)
+
+	addMockedNodeRelay("https://aog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+	addMockedNodeRelay("https://pjog.com", `{"jsonrpc": "2.0", "result": "0xdd03e4", "id": 1}`)
+	addMockedNodeRelay("https://ohana.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	output, err = relayer.RelayWithConsensus(input, nil)
+	c.NoError(err)
+	c.Len(output.Outputs, 3)
+	c.Equal(`{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`, NormalizeResponse(output.Output.RelayOutput.Response))
+	c.Equal(3, output.Output.Attempts)
+	c.Equal(getPublicKeys(output.Nodes), output.Output.TriedNodes)
+	c.Positive(output.Output.TotalDuration)
+
+	proofPubKeys := map[string]bool{}
+	for i, nodeOutput := range output.Outputs {
+		c.NoError(output.Errors[i])
+		c.Equal(output.Nodes[i].PublicKey, nodeOutput.Proof.ServicerPubKey)
+		proofPubKeys[nodeOutput.Proof.ServicerPubKey] = true
+	}
+	c.Len(proofPubKeys, 3)
+
+	addMockedNodeRelay("https://pjog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e5"}`)
+
+	output, err = relayer.RelayWithConsensus(input, nil)
+	c.NoError(err)
+	c.Equal(`{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`, NormalizeResponse(output.Output.RelayOutput.Response))
+
+	output, err = relayer.RelayWithConsensus(input, &ConsensusOptions{Threshold: 3})
+	c.True(errors.Is(err, ErrNoConsensus))
+	c.Empty(output)
+
+	addMockedNodeRelay("https://ohana.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e6"}`)
+
+	output, err = relayer.RelayWithConsensus(input, nil)
+	c.True(errors.Is(err, ErrNoConsensus))
+	c.Empty(output)
+
+	var consensusErr *ConsensusError
+
+	c.ErrorAs(err, &consensusErr)
+	c.Len(consensusErr.Outputs, 3)
+
+	responses := map[string]bool{}
+	for _, nodeOutput := range consensusErr.Outputs {
+		responses[nodeOutput.RelayOutput.Response] = true
+	}
+	c.Len(responses, 3)
+}
+
+func TestGetConsensusCounts(t *testing.T) {
+	c := require.New(t)
+
+	session := newConsensusInput().Session
+
+	tests := []struct {
+		options           *ConsensusOptions
+		expectedNodeCount int
+		expectedThreshold int
+		expectedErr       error
+	}{
+		{options: nil, expectedNodeCount: 3, expectedThreshold: 2},
+		{options: &ConsensusOptions{NodeCount: 2}, expectedNodeCount: 2, expectedThreshold: 2},
+		{options: &ConsensusOptions{NodeCount: 3, Threshold: 1}, expectedNodeCount: 3, expectedThreshold: 1},
+		{options: &ConsensusOptions{NodeCount: 4}, expectedErr: ErrNotEnoughSessionNodes},
+		{options: &ConsensusOptions{NodeCount: -1}, expectedErr: ErrInvalidNodeCount},
+		{options: &ConsensusOptions{NodeCount: 2, Threshold: 3}, expectedErr: ErrInvalidConsensusThreshold},
+		{options: &ConsensusOptions{Threshold: -1}, expectedErr: ErrInvalidConsensusThreshold},
+		{options: &ConsensusOptions{NodeCount: -1, Threshold: -1}, expectedErr: ErrInvalidNodeCount},
+	}
+
+	for _, tt := range tests {
+		nodeCount, threshold, err := getConsensusCounts(session, tt.options)
+		c.Equal(tt.expectedErr, err, "options %+v", tt.options)
+		c.Equal(tt.expectedNodeCount, nodeCount, "options %+v", tt.options)
+		c.Equal(tt.expectedThreshold, threshold, "options %+v", tt.options)
+	}
+}
+
+func TestGetRandomSessionNodes(t *testing.T) {
+	c := require.New(t)
+
+	session := newConsensusInput().Session
+
+	tests := []struct {
+		count       int
+		expectedErr error
+	}{
+		{count: 0},
+		{count: 2},
+		{count: 3},
+		{count: 4, expectedErr: ErrNotEnoughSessionNodes},
+		{count: -1, expectedErr: ErrInvalidNodeCount},
+	}
+
+	for _, tt := range tests {
+		nodes, err := GetRandomSessionNodes(session, tt.count)
+		c.Equal(tt.expectedErr, err, "count %d", tt.count)
+
+		if tt.expectedErr != nil {
+			c.Empty(nodes)
+
+			continue
+		}
+
+		c.Len(nodes, tt.count)
+
+		distinct := map[string]bool{}
+		for _, node := range nodes {
+			distinct[node.PublicKey] = true
+		}
+		c.Len(distinct, tt.count)
+	}
+}
+
+func TestNormalizeResponse(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal(`{"a":1,"b":[1,2]}`, NormalizeResponse(`{ "b": [1, 2], "a": 1 }`))
+	c.Equal("not json", NormalizeResponse(" not json\n"))
+	c.Equal(`{"a":1} trailing`, NormalizeResponse(`{"a":1} trailing`))
+	c.Equal(`{"a":{"c":2},"b":[{"c":3}]}`, NormalizeResponseWithoutFields(
+		`{"b": [{"c": 3, "id": "pjog-1"}], "id": 21, "a": {"id": "aog-7", "c": 2}}`, []string{"id"}))
+	c.Equal("not json", NormalizeResponseWithoutFields("not json", []string{"id"}))
+}
+
+func TestRelayer_RelayWithConsensusComparison(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+	input := newConsensusInput()
+
+	addMockedNodeRelay("https://aog.com", `{"id":"aog-1","jsonrpc":"2.0","result":{"number":"0x15","hash":"0xab"}}`)
+	addMockedNodeRelay("https://pjog.com", `{"result":{"hash":"0xab","number":"0x15"},"jsonrpc":"2.0","id":"pjog-2"}`)
+	addMockedNodeRelay("https://ohana.com", `{"id":"ohana-3","jsonrpc":"2.0","result":{"number":"0x16","hash":"0xcd"}}`)
+
+	output, err := relayer.RelayWithConsensus(input, nil)
+	c.True(errors.Is(err, ErrNoConsensus))
+	c.Empty(output)
+
+	output, err = relayer.RelayWithConsensus(input, &ConsensusOptions{IgnoredFields: []string{"id"}})
+	c.NoError(err)
+	c.Equal(`{"jsonrpc":"2.0","result":{"hash":"0xab","number":"0x15"}}`,
+		NormalizeResponseWithoutFields(output.Output.RelayOutput.Response, []string{"id"}))
+
+	output, err = relayer.RelayWithConsensus(input, &ConsensusOptions{IgnoredFields: []string{"id"}, Threshold: 3})
+	c.True(errors.Is(err, ErrNoConsensus))
+	c.Empty(output)
+
+	var compared int
+
+	output, err = relayer.RelayWithConsensus(input, &ConsensusOptions{
+		Threshold: 3,
+		Comparator: func(first, second string) bool {
+			compared++
+
+			return strings.Contains(first, `"jsonrpc":"2.0"`) == strings.Contains(second, `"jsonrpc":"2.0"`)
+		},
+	})
+	c.NoError(err)
+	c.NotNil(output.Output)
+	c.Equal(2, compared)
+
+	nonJSONOutputs := []*Output{
+		{RelayOutput: &provider.RelayOutput{Response: "0x16"}},
+		{RelayOutput: &provider.RelayOutput{Response: "0x15"}},
+		nil,
+		{RelayOutput: &provider.RelayOutput{Response: "0x15\n"}},
+	}
+
+	c.Equal(nonJSONOutputs[1], getMajorityOutput(nonJSONOutputs, 2, getResponseComparator(nil)))
+	c.Nil(getMajorityOutput(nonJSONOutputs, 3, getResponseComparator(nil)))
+}
@@ -0,0 +1,47 @@
+package relayer
+
+import "context"
+
+// Drain blocks until every relay in flight is done or ctx is done, in which case the ctx error is returned
+// It covers Relay, RelayAll, RelayWithRetries, RelayWithConsensus, RelaySubscribe until its ctx is done and the relays
+// left running in background after a timeout, it is meant to be called on shutdown once no new relays are started
+func (r *Relayer) Drain(ctx context.Context) error {
+	r.inFlightMutex.Lock()
+	idle := r.inFlightIdle
+	inFlight := r.inFlight
+	r.inFlightMutex.Unlock()
+
+	if inFlight == 0 {
+		return nil
+	}
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// trackRelay counts a relay as in flight for Drain until the returned function is called
+// A counter is used instead of a sync.WaitGroup so relays can start while a Drain that timed out is still waiting
+func (r *Relayer) trackRelay() func() {
+	r.inFlightMutex.Lock()
+	defer r.inFlightMutex.Unlock()
+
+	if r.inFlight == 0 {
+		r.inFlightIdle = make(chan struct{})
+	}
+
+	r.inFlight++
+
+	return func() {
+		r.inFlightMutex.Lock()
+		defer r.inFlightMutex.Unlock()
+
+		r.inFlight--
+		if r.inFlight == 0 {
+			close(r.inFlightIdle)
+		}
+	}
+}
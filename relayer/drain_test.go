@@ -0,0 +1,62 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayer_Drain(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	provider := &providerMock{delay: 100 * time.Millisecond}
+	relayer := NewRelayer(signer, provider)
+
+	c.NoError(relayer.Drain(context.Background()))
+
+	inputs := newBatchInputs(3)
+	inputs[2].Timeout = 10 * time.Millisecond
+
+	var done int64
+
+	go func() {
+		relayer.RelayAll(inputs[:2], nil, 0)
+		atomic.AddInt64(&done, 1)
+	}()
+
+	_, err = relayer.Relay(inputs[2], nil)
+	c.True(errors.Is(err, ErrTimeout))
+	c.Equal(int64(0), atomic.LoadInt64(&done))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	c.Equal(context.DeadlineExceeded, relayer.Drain(ctx))
+
+	c.NoError(relayer.Drain(context.Background()))
+	c.Equal(int64(0), atomic.LoadInt64(&provider.inFlight))
+	c.Equal(int64(3), relayer.Stats().TotalAttempts)
+
+	input := newBatchInputs(1)[0]
+	input.Timeout = time.Millisecond
+
+	_, err = relayer.Relay(input, nil)
+	c.True(errors.Is(err, ErrTimeout))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	c.Equal(context.DeadlineExceeded, relayer.Drain(ctx))
+
+	c.NoError(relayer.Drain(context.Background()))
+	c.Equal(int64(0), atomic.LoadInt64(&provider.inFlight))
+}
@@ -0,0 +1,84 @@
+package relayer
+
+import (
+	"fmt"
+)
+
+// HeightSource interface representing the source of the current block height, like provider.Provider
+type HeightSource interface {
+	GetBlockHeight() (int, error)
+}
+
+// SessionDriftError represents a session error annotated with how many blocks the session is behind
+// the current height, see WithDebugSessionDrift
+// Drift is CurrentHeight - SessionHeight, it is negative when the session is ahead of the current height
+type SessionDriftError struct {
+	SessionHeight int
+	CurrentHeight int
+	Drift         int
+	Err           error
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *SessionDriftError) Error() string {
+	return fmt.Sprintf("%s: session is %d blocks stale (session height %d, current height %d)", e.Err, e.Drift,
+		e.SessionHeight, e.CurrentHeight)
+}
+
+// Unwrap returns the session error so the error can be checked with errors.Is and errors.As
+func (e *SessionDriftError) Unwrap() error {
+	return e.Err
+}
+
+// WithDebugSessionDrift annotates the session errors returned by Relay and RelayWithRetries with the blocks between
+// the session height of the relay and the current height in a SessionDriftError, see IsSessionError
+// The current height is the one of the height source, see WithHeightSource, or the input current height without it
+// Disabled by default, the height source is queried on every session error
+func WithDebugSessionDrift(enabled bool) RelayerOption {
+	return func(r *Relayer) {
+		r.debugSessionDrift = enabled
+	}
+}
+
+// WithHeightSource sets the source of the current height used by WithDebugSessionDrift
+func WithHeightSource(heightSource HeightSource) RelayerOption {
+	return func(r *Relayer) {
+		r.heightSource = heightSource
+	}
+}
+
+// annotateSessionDrift returns err as a SessionDriftError when it is a session error and the current height is known
+// otherwise err is returned unchanged
+func (r *Relayer) annotateSessionDrift(input *Input, err error) error {
+	if !r.debugSessionDrift || err == nil || !IsSessionError(err) ||
+		input == nil || input.Session == nil || input.Session.Header == nil {
+		return err
+	}
+
+	currentHeight, ok := r.getCurrentHeight(input)
+	if !ok {
+		return err
+	}
+
+	sessionHeight := input.Session.Header.SessionHeight
+
+	return &SessionDriftError{
+		SessionHeight: sessionHeight,
+		CurrentHeight: currentHeight,
+		Drift:         currentHeight - sessionHeight,
+		Err:           err,
+	}
+}
+
+// getCurrentHeight returns the height of the height source or the input current height, false when none is known
+func (r *Relayer) getCurrentHeight(input *Input) (int, bool) {
+	if r.heightSource != nil {
+		height, err := r.heightSource.GetBlockHeight()
+		if err == nil {
+			return height, true
+		}
+	}
+
+	return input.CurrentHeight, input.CurrentHeight > 0
+}
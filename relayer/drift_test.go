@@ -0,0 +1,96 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/utils-go/mock-client"
+)
+
+type heightSourceMock struct {
+	height int
+	err    error
+}
+
+func (s *heightSourceMock) GetBlockHeight() (int, error) {
+	return s.height, s.err
+}
+
+func TestRelayer_WithDebugSessionDrift(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	heightSource := &heightSourceMock{height: 30}
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+		WithDebugSessionDrift(true), WithHeightSource(heightSource))
+
+	input := newConsensusInput()
+	input.Node = input.Session.Nodes[0]
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://aog.com", provider.ClientRelayRoute),
+		http.StatusBadRequest, `{"error": {"code": 14, "codespace": "vipercore", "message": "invalid session"}}`)
+
+	output, err := relayer.Relay(input, nil)
+	c.Empty(output)
+	c.True(provider.IsErrorCode(provider.InvalidSessionError, err))
+	c.Contains(err.Error(), "session is 9 blocks stale")
+
+	var driftErr *SessionDriftError
+
+	c.ErrorAs(err, &driftErr)
+	c.Equal(21, driftErr.SessionHeight)
+	c.Equal(30, driftErr.CurrentHeight)
+	c.Equal(9, driftErr.Drift)
+
+	output, _, err = relayer.RelayWithRetries(input, nil, &RetryOptions{MaxAttempts: 2, Backoff: time.Millisecond})
+	c.Empty(output)
+	c.ErrorAs(err, &driftErr)
+	c.Equal(9, driftErr.Drift)
+
+	var attemptsErr *RelayAttemptsError
+
+	c.ErrorAs(err, &attemptsErr)
+
+	heightSource.err = errors.New("height unavailable")
+	input.CurrentHeight = 26
+
+	_, err = relayer.Relay(input, nil)
+	c.True(errors.Is(err, ErrSessionExpired))
+	c.ErrorAs(err, &driftErr)
+	c.Equal(5, driftErr.Drift)
+
+	input.CurrentHeight = 0
+
+	_, err = relayer.Relay(input, nil)
+	c.False(errors.As(err, &driftErr))
+	c.True(provider.IsErrorCode(provider.InvalidSessionError, err))
+
+	relayer = NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+		WithHeightSource(&heightSourceMock{height: 30}))
+
+	_, err = relayer.Relay(input, nil)
+	c.False(errors.As(err, &driftErr))
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://aog.com", provider.ClientRelayRoute),
+		http.StatusInternalServerError, "{}")
+
+	relayer = NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+		WithDebugSessionDrift(true), WithHeightSource(&heightSourceMock{height: 30}))
+
+	_, err = relayer.Relay(input, nil)
+	c.False(errors.As(err, &driftErr))
+	c.True(errors.Is(err, provider.Err5xxOnConnection))
+}
@@ -0,0 +1,154 @@
+package relayer
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+const (
+	// DefaultEntropyTrackerMaxEntries is the default amount of used entropies remembered by an EntropyTracker
+	DefaultEntropyTrackerMaxEntries = 100000
+	// DefaultEntropyMaxRerolls is the default amount of new entropies tried after a collision
+	DefaultEntropyMaxRerolls = 8
+)
+
+// ErrEntropyCollision error when every rolled proof entropy was already used in the session
+var ErrEntropyCollision = errors.New("entropy already used in session")
+
+// EntropyCollisionError represents the thrown error when a unique proof entropy could not be found
+// for the session after the max rerolls of the EntropyTracker
+type EntropyCollisionError struct {
+	SessionKey string
+	Rerolls    int
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *EntropyCollisionError) Error() string {
+	return fmt.Sprintf("%s: session %s, %d rerolls", ErrEntropyCollision, e.SessionKey, e.Rerolls)
+}
+
+// Unwrap returns ErrEntropyCollision so the error can be checked with errors.Is
+func (e *EntropyCollisionError) Unwrap() error {
+	return ErrEntropyCollision
+}
+
+// EntropySequence interface representing a caller supplied source of proof entropies,
+// like a counter for deterministic pipelines
+// Next is called by one relay at a time
+type EntropySequence interface {
+	Next() (int64, error)
+}
+
+// WithEntropySequence takes the proof entropies from given sequence instead of the entropy reader
+// The values are mapped to [0, entropyMax) by their remainder, see WithEntropyMax
+func WithEntropySequence(sequence EntropySequence) RelayerOption {
+	return func(r *Relayer) {
+		r.entropySequence = sequence
+	}
+}
+
+type entropyKey struct {
+	sessionKey string
+	entropy    int64
+}
+
+// EntropyTracker remembers the most recently used proof entropies of every session so a relayer does not
+// send two proofs with the same entropy in a session, which servicers reject as duplicate proofs
+// It is safe for concurrent use and can be shared by many relayers, see WithEntropyTracker
+type EntropyTracker struct {
+	mutex      sync.Mutex
+	maxEntries int
+	maxRerolls int
+	entries    map[entropyKey]*list.Element
+	order      *list.List
+}
+
+// NewEntropyTracker returns instance of EntropyTracker remembering at most maxEntries entropies
+// and rolling at most maxRerolls new entropies after a collision
+// maxEntries <= 0 uses DefaultEntropyTrackerMaxEntries and maxRerolls < 0 uses DefaultEntropyMaxRerolls
+func NewEntropyTracker(maxEntries, maxRerolls int) *EntropyTracker {
+	if maxEntries <= 0 {
+		maxEntries = DefaultEntropyTrackerMaxEntries
+	}
+
+	if maxRerolls < 0 {
+		maxRerolls = DefaultEntropyMaxRerolls
+	}
+
+	return &EntropyTracker{
+		maxEntries: maxEntries,
+		maxRerolls: maxRerolls,
+		entries:    map[entropyKey]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+// add remembers the entropy as used in the session, returns false when it already was
+func (t *EntropyTracker) add(sessionKey string, entropy int64) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := entropyKey{sessionKey: sessionKey, entropy: entropy}
+
+	element, ok := t.entries[key]
+	if ok {
+		t.order.MoveToFront(element)
+
+		return false
+	}
+
+	t.entries[key] = t.order.PushFront(key)
+
+	for t.order.Len() > t.maxEntries {
+		oldest := t.order.Back()
+		t.order.Remove(oldest)
+		delete(t.entries, oldest.Value.(entropyKey))
+	}
+
+	return true
+}
+
+// WithEntropyTracker rerolls the proof entropy of a relay when it was already used in the session
+// A relay whose entropy still collides after the tracker max rerolls fails with an EntropyCollisionError
+func WithEntropyTracker(tracker *EntropyTracker) RelayerOption {
+	return func(r *Relayer) {
+		r.entropyTracker = tracker
+	}
+}
+
+// getEntropySessionKey returns the session key, or the session header fields when the session has no key
+func getEntropySessionKey(session *provider.Session) string {
+	if session.Key != "" {
+		return session.Key
+	}
+
+	return fmt.Sprintf("%s/%s/%d", session.Header.AppPublicKey, session.Header.Chain, session.Header.SessionHeight)
+}
+
+// generateUniqueEntropy returns a proof entropy not used yet in the input session when the relayer has
+// an entropy tracker, otherwise any generated entropy
+func (r *Relayer) generateUniqueEntropy(input *Input) (int64, error) {
+	if r.entropyTracker == nil {
+		return r.generateEntropy()
+	}
+
+	sessionKey := getEntropySessionKey(input.Session)
+
+	for i := 0; i <= r.entropyTracker.maxRerolls; i++ {
+		entropy, err := r.generateEntropy()
+		if err != nil {
+			return 0, err
+		}
+
+		if r.entropyTracker.add(sessionKey, entropy) {
+			return entropy, nil
+		}
+	}
+
+	return 0, &EntropyCollisionError{SessionKey: sessionKey, Rerolls: r.entropyTracker.maxRerolls}
+}
@@ -0,0 +1,135 @@
+package relayer
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/stretchr/testify/require"
+)
+
+type entropySequenceMock struct {
+	values []int64
+	next   int
+}
+
+func (s *entropySequenceMock) Next() (int64, error) {
+	if s.next >= len(s.values) {
+		return 0, errors.New("sequence exhausted")
+	}
+
+	s.next++
+
+	return s.values[s.next-1], nil
+}
+
+func TestRelayer_WithEntropySequence(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, &providerMock{}, WithEntropyMax(100),
+		WithEntropySequence(&entropySequenceMock{values: []int64{21, 121, -1}}))
+	input := newBatchInputs(1)[0]
+
+	for _, expectedEntropy := range []int64{21, 21, 99} {
+		output, err := relayer.Relay(input, nil)
+		c.NoError(err)
+		c.Equal(expectedEntropy, output.Proof.Entropy)
+	}
+
+	output, err := relayer.Relay(input, nil)
+	c.EqualError(err, "sequence exhausted")
+	c.Empty(output)
+}
+
+func TestRelayer_WithEntropyTracker(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, &providerMock{}, WithEntropyTracker(NewEntropyTracker(0, 2)),
+		WithEntropySequence(&entropySequenceMock{values: []int64{1, 1, 1, 2, 2, 2, 2, 2, 2}}))
+	inputs := newBatchInputs(2)
+	session := *inputs[1].Session
+	session.Key = "PJOG"
+	inputs[1].Session = &session
+
+	output, err := relayer.Relay(inputs[0], nil)
+	c.NoError(err)
+	c.Equal(int64(1), output.Proof.Entropy)
+
+	output, err = relayer.Relay(inputs[0], nil)
+	c.NoError(err)
+	c.Equal(int64(2), output.Proof.Entropy)
+
+	output, err = relayer.Relay(inputs[1], nil)
+	c.NoError(err)
+	c.Equal(int64(2), output.Proof.Entropy)
+
+	output, err = relayer.Relay(inputs[0], nil)
+	c.True(errors.Is(err, ErrEntropyCollision))
+	c.Equal(&EntropyCollisionError{SessionKey: getEntropySessionKey(inputs[0].Session), Rerolls: 2}, err)
+	c.Empty(output)
+}
+
+func TestEntropyTracker_Bounded(t *testing.T) {
+	c := require.New(t)
+
+	tracker := NewEntropyTracker(2, -1)
+	c.Equal(DefaultEntropyMaxRerolls, tracker.maxRerolls)
+
+	c.True(tracker.add("AOG", 1))
+	c.True(tracker.add("AOG", 2))
+	c.False(tracker.add("AOG", 1))
+	c.True(tracker.add("PJOG", 1))
+	c.False(tracker.add("AOG", 1))
+	c.True(tracker.add("AOG", 2))
+}
+
+func TestRelayer_WithEntropyTrackerConcurrent(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	tracker := NewEntropyTracker(0, 1000)
+	relayers := []*Relayer{
+		NewRelayer(signer, &providerMock{}, WithEntropyMax(256), WithEntropyTracker(tracker)),
+		NewRelayer(signer, &providerMock{}, WithEntropyMax(256), WithEntropyTracker(tracker)),
+	}
+	input := newBatchInputs(1)[0]
+
+	var (
+		wg        sync.WaitGroup
+		mutex     sync.Mutex
+		entropies = map[int64]bool{}
+	)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(relayer *Relayer) {
+			defer wg.Done()
+
+			for j := 0; j < 16; j++ {
+				output, err := relayer.Relay(input, nil)
+				if err != nil {
+					continue
+				}
+
+				mutex.Lock()
+				entropies[output.Proof.Entropy] = true
+				mutex.Unlock()
+			}
+		}(relayers[i%len(relayers)])
+	}
+
+	wg.Wait()
+
+	c.Len(entropies, 128)
+}
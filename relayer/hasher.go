@@ -0,0 +1,46 @@
+package relayer
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// HashAlgorithmSHA3256 is the name of the sha3-256 hash algorithm used by default in relay proofs
+const HashAlgorithmSHA3256 = "sha3-256"
+
+// ErrNoHasher error when the relayer has no hasher
+var ErrNoHasher = errors.New("no hasher provided")
+
+// Hasher interface representing the hash algorithm of request hashes, AAT hashes and proof bytes
+// Name identifies the algorithm and is recorded on every Output
+type Hasher interface {
+	Name() string
+	Hash(data []byte) []byte
+}
+
+// SHA3Hasher is the sha3-256 Hasher of the current network version
+type SHA3Hasher struct{}
+
+// Name returns HashAlgorithmSHA3256
+func (SHA3Hasher) Name() string {
+	return HashAlgorithmSHA3256
+}
+
+// Hash returns the sha3-256 hash of data
+func (SHA3Hasher) Hash(data []byte) []byte {
+	hash := sha3.Sum256(data)
+
+	return hash[:]
+}
+
+// DefaultHasher is the Hasher used by HashRequest, HashAAT and GenerateProofBytes and by relayers without WithHasher
+var DefaultHasher Hasher = SHA3Hasher{}
+
+// WithHasher sets the hash algorithm of the relay proofs, defaults to DefaultHasher
+// It must be the one of the network version the relays are sent to
+func WithHasher(hasher Hasher) RelayerOption {
+	return func(r *Relayer) {
+		r.hasher = hasher
+	}
+}
@@ -0,0 +1,179 @@
+package relayer
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sha256Hasher struct{}
+
+func (sha256Hasher) Name() string {
+	return "sha256"
+}
+
+func (sha256Hasher) Hash(data []byte) []byte {
+	hash := sha256.Sum256(data)
+
+	return hash[:]
+}
+
+func newHasherVectors() (*RequestHash, *provider.ViperAAT) {
+	request := &RequestHash{
+		Payload: &provider.RelayPayload{Data: `{"method":"eth_blockNumber","id":1,"jsonrpc":"2.0"}`, Method: "POST"},
+		Meta:    &provider.RelayMeta{BlockHeight: 21},
+	}
+
+	aat := &provider.ViperAAT{
+		Version:      "0.0.1",
+		AppPubKey:    "ABCD",
+		ClientPubKey: "EFGH",
+		Signature:    "IJKL",
+	}
+
+	return request, aat
+}
+
+func TestHashRequest_Headers(t *testing.T) {
+	c := require.New(t)
+
+	request, _ := newHasherVectors()
+	headers := [][2]string{
+		{"Content-Type", "application/json"},
+		{"Authorization", "Bearer 21"},
+		{"x-trace", "<abc>"},
+		{"X-Viper-Region", "us-east"},
+	}
+
+	for i := 0; i < 100; i++ {
+		request.Payload.Headers = provider.RelayHeaders{}
+
+		for j := range headers {
+			header := headers[(i+j)%len(headers)]
+			request.Payload.Headers[header[0]] = header[1]
+		}
+
+		requestHash, err := HashRequest(request)
+		c.NoError(err)
+		c.Equal("826236959234180a78830989a0e8818b149b07cc224b4a39130ab78057a6dcbd", requestHash)
+	}
+
+	request.Payload.Headers = provider.RelayHeaders{}
+
+	emptyHash, err := HashRequest(request)
+	c.NoError(err)
+
+	request.Payload.Headers = nil
+
+	nilHash, err := HashRequest(request)
+	c.NoError(err)
+	c.NotEqual(emptyHash, nilHash)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	input := newBatchInputs(1)[0]
+	input.Headers = provider.RelayHeaders{"Content-Type": "application/json"}
+
+	relay, _, err := NewRelayer(signer, &providerMock{}).BuildRelay(input)
+	c.NoError(err)
+
+	input.Headers["Authorization"] = "Bearer 21"
+
+	requestHash, err := HashRequest(&RequestHash{Payload: relay.Payload, Meta: relay.Meta})
+	c.NoError(err)
+	c.Equal(relay.Proof.RequestHash, requestHash)
+	c.Len(relay.Payload.Headers, 1)
+}
+
+func TestDefaultHasher(t *testing.T) {
+	c := require.New(t)
+
+	request, aat := newHasherVectors()
+
+	requestHash, err := HashRequest(request)
+	c.NoError(err)
+	c.Equal("43f32acb6e19c5d039f6144cbb45f89727d7915ce96deb1d6e0fc0569b1adccf", requestHash)
+
+	aatHash, err := HashAAT(aat)
+	c.NoError(err)
+	c.Equal("b9568ed1ad610e6eb418b0ec8c7e6ba57022bd22ec35fb6664cb0c1f279e9f3d", aatHash)
+
+	proofBytes, err := GenerateProofBytes(&provider.RelayProof{
+		RequestHash:        requestHash,
+		Entropy:            21,
+		SessionBlockHeight: 21,
+		ServicerPubKey:     "AOG",
+		Blockchain:         "0021",
+		AAT:                aat,
+	})
+	c.NoError(err)
+	c.Equal("ae198cb16e91ecb5ef3b69d4da073b8d27f677b4c52edb1612b3d06448ae3c21", hex.EncodeToString(proofBytes))
+
+	c.Equal(HashAlgorithmSHA3256, DefaultHasher.Name())
+}
+
+func TestRelayer_WithHasher(t *testing.T) {
+	c := require.New(t)
+
+	request, aat := newHasherVectors()
+
+	requestHash, err := HashRequestWithHasher(sha256Hasher{}, request)
+	c.NoError(err)
+	c.Equal("5530a271dbd49c321d30ffa8b9ccfbbc7c48f34994ff28c2d9114c30db91036a", requestHash)
+
+	sameHash, err := HashRequestWithHasher(sha256Hasher{}, request)
+	c.NoError(err)
+	c.Equal(requestHash, sameHash)
+
+	defaultHash, err := HashRequest(request)
+	c.NoError(err)
+	c.NotEqual(defaultHash, requestHash)
+
+	aatHash, err := HashAATWithHasher(sha256Hasher{}, aat)
+	c.NoError(err)
+
+	defaultAATHash, err := HashAAT(aat)
+	c.NoError(err)
+	c.NotEqual(defaultAATHash, aatHash)
+
+	clientSigner, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	input := newBatchInputs(1)[0]
+	input.ViperAAT = &provider.ViperAAT{Version: "0.0.1", AppPubKey: "ABCD", ClientPubKey: clientSigner.GetPublicKey()}
+
+	output, err := NewRelayer(clientSigner, &providerMock{}).Relay(input, nil)
+	c.NoError(err)
+	c.Equal(HashAlgorithmSHA3256, output.HashAlgorithm)
+
+	defaultRequestHash := output.Proof.RequestHash
+
+	output, err = NewRelayer(clientSigner, &providerMock{}, WithHasher(sha256Hasher{})).Relay(input, nil)
+	c.NoError(err)
+	c.Equal("sha256", output.HashAlgorithm)
+	c.NotEqual(defaultRequestHash, output.Proof.RequestHash)
+
+	proofBytes, err := GenerateProofBytesWithHasher(sha256Hasher{}, output.Proof)
+	c.NoError(err)
+
+	signature, err := hex.DecodeString(output.Proof.Signature)
+	c.NoError(err)
+
+	publicKey, err := hex.DecodeString(clientSigner.GetPublicKey())
+	c.NoError(err)
+	c.True(ed25519.Verify(publicKey, proofBytes, signature))
+
+	c.NoError(verifyProofSignature(sha256Hasher{}, output.Proof))
+	c.Equal(ErrInvalidProofSignature, verifyProofSignature(DefaultHasher, output.Proof))
+	c.Equal(ErrHashAlgorithmMismatch, VerifyStoredOutput(output))
+
+	_, err = NewRelayer(clientSigner, &providerMock{}, WithHasher(nil)).Relay(input, nil)
+	c.Equal(ErrNoHasher, err)
+}
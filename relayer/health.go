@@ -0,0 +1,93 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// ErrHealthCheckNotSupported error when the relayer provider can not probe nodes
+var ErrHealthCheckNotSupported = errors.New("provider does not support health checks")
+
+// HealthChecker interface representing providers able to probe a node without relaying, like provider.Provider
+type HealthChecker interface {
+	CheckHealth(rpcURL string) error
+}
+
+// HealthReport represents the result of a HealthCheck
+// Nodes keep the session order and Errors holds the error of every unhealthy node by its public key
+type HealthReport struct {
+	Healthy   []*provider.Node
+	Unhealthy []*provider.Node
+	Errors    map[string]error
+}
+
+type nodeHealth struct {
+	index int
+	err   error
+}
+
+// HealthCheck probes every node of the session concurrently
+// Nodes not answering before ctx is done are unhealthy with the ctx error
+// All nodes are unhealthy with ErrHealthCheckNotSupported when the provider is not a HealthChecker
+func (r *Relayer) HealthCheck(ctx context.Context, session *provider.Session) *HealthReport {
+	report := &HealthReport{Errors: map[string]error{}}
+
+	if session == nil {
+		return report
+	}
+
+	errs := make([]error, len(session.Nodes))
+
+	checker, ok := r.provider.(HealthChecker)
+	if ok {
+		checkNodesHealth(ctx, checker, session.Nodes, errs)
+	} else {
+		for i := range errs {
+			errs[i] = ErrHealthCheckNotSupported
+		}
+	}
+
+	for i, node := range session.Nodes {
+		if errs[i] != nil {
+			report.Unhealthy = append(report.Unhealthy, node)
+			report.Errors[node.PublicKey] = errs[i]
+
+			continue
+		}
+
+		report.Healthy = append(report.Healthy, node)
+	}
+
+	return report
+}
+
+// checkNodesHealth sets the probe error of every node in errs, probes still running when ctx is done are discarded
+func checkNodesHealth(ctx context.Context, checker HealthChecker, nodes []*provider.Node, errs []error) {
+	results := make(chan nodeHealth, len(nodes))
+
+	for i, node := range nodes {
+		go func(index int, node *provider.Node) {
+			results <- nodeHealth{index: index, err: checker.CheckHealth(node.ServiceURL)}
+		}(i, node)
+	}
+
+	checked := make([]bool, len(nodes))
+
+	for range nodes {
+		select {
+		case result := <-results:
+			errs[result.index] = result.err
+			checked[result.index] = true
+		case <-ctx.Done():
+			for i := range nodes {
+				if !checked[i] {
+					errs[i] = ctx.Err()
+				}
+			}
+
+			return
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/utils-go/mock-client"
+)
+
+type slowHealthCheckerMock struct {
+	providerMock
+}
+
+func (p *slowHealthCheckerMock) CheckHealth(rpcURL string) error {
+	if rpcURL == "https://ohana.com" {
+		time.Sleep(time.Second)
+	}
+
+	return nil
+}
+
+func TestRelayer_HealthCheck(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://aog.com", provider.QueryHeightRoute),
+		http.StatusOK, `{"height": 21}`)
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://pjog.com", provider.QueryHeightRoute),
+		http.StatusInternalServerError, `{"error": "internal"}`)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	healthyNode := &provider.Node{PublicKey: "AOG", ServiceURL: "https://aog.com"}
+	unhealthyNode := &provider.Node{PublicKey: "PJOG", ServiceURL: "https://pjog.com"}
+	session := &provider.Session{Nodes: []*provider.Node{healthyNode, unhealthyNode}}
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+
+	report := relayer.HealthCheck(context.Background(), session)
+	c.Equal([]*provider.Node{healthyNode}, report.Healthy)
+	c.Equal([]*provider.Node{unhealthyNode}, report.Unhealthy)
+	c.Len(report.Errors, 1)
+	c.Equal(provider.Err5xxOnConnection, report.Errors["PJOG"])
+
+	report = NewRelayer(signer, &providerMock{}).HealthCheck(context.Background(), session)
+	c.Empty(report.Healthy)
+	c.Len(report.Unhealthy, 2)
+	c.Equal(ErrHealthCheckNotSupported, report.Errors["AOG"])
+
+	slowNode := &provider.Node{PublicKey: "OHANA", ServiceURL: "https://ohana.com"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	report = NewRelayer(signer, &slowHealthCheckerMock{}).
+		HealthCheck(ctx, &provider.Session{Nodes: []*provider.Node{healthyNode, slowNode}})
+	c.Equal([]*provider.Node{healthyNode}, report.Healthy)
+	c.Equal([]*provider.Node{slowNode}, report.Unhealthy)
+	c.Equal(context.DeadlineExceeded, report.Errors["OHANA"])
+}
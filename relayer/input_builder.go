@@ -0,0 +1,91 @@
+package relayer
+
+import "github.com/vishruthsk/viper-go/provider"
+
+// InputBuilder assembles a relay Input and validates it before it is relayed
+type InputBuilder struct {
+	input Input
+}
+
+// NewInputBuilder returns an empty instance of InputBuilder
+func NewInputBuilder() *InputBuilder {
+	return &InputBuilder{}
+}
+
+// WithSession sets the session the relay is done in
+func (b *InputBuilder) WithSession(session *provider.Session) *InputBuilder {
+	b.input.Session = session
+
+	return b
+}
+
+// WithAAT sets the Viper AAT used to sign the relay proof
+func (b *InputBuilder) WithAAT(aat *provider.ViperAAT) *InputBuilder {
+	b.input.ViperAAT = aat
+
+	return b
+}
+
+// WithData sets the data of the relay request
+func (b *InputBuilder) WithData(data string) *InputBuilder {
+	b.input.Data = data
+
+	return b
+}
+
+// WithBlockchain sets the blockchain ID the relay is done to
+func (b *InputBuilder) WithBlockchain(blockchain string) *InputBuilder {
+	b.input.Blockchain = blockchain
+
+	return b
+}
+
+// WithNode sets the session node the relay is sent to, without node the relayer chooses one
+func (b *InputBuilder) WithNode(node *provider.Node) *InputBuilder {
+	b.input.Node = node
+
+	return b
+}
+
+// WithNodeServiceURL sets the service URL of the session node the relay is sent to, WithNode takes precedence
+func (b *InputBuilder) WithNodeServiceURL(serviceURL string) *InputBuilder {
+	b.input.NodeServiceURL = serviceURL
+
+	return b
+}
+
+// WithHeaders sets the HTTP headers of the relay request
+func (b *InputBuilder) WithHeaders(headers provider.RelayHeaders) *InputBuilder {
+	b.input.Headers = headers
+
+	return b
+}
+
+// Build returns the built Input after doing the input validations of Relay
+// Besides them the AAT app public key must match the one of the session header and the node must be in the session,
+// as well as a node with the node service URL
+func (b *InputBuilder) Build() (*Input, error) {
+	input := b.input
+
+	err := validateInputSession(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	if isAATMismatch(&input) {
+		return nil, ErrAATMismatch
+	}
+
+	if input.Node != nil && !IsNodeInSession(input.Session, input.Node) {
+		return nil, ErrNodeNotInSession
+	}
+
+	if input.Node == nil && input.NodeServiceURL != "" {
+		_, err = GetSessionNodeByServiceURL(input.Session, input.NodeServiceURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &input, nil
+}
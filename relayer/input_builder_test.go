@@ -0,0 +1,136 @@
+package relayer
+
+import (
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newBuilderSession() *provider.Session {
+	session := newSelectorSession()
+	session.Header = &provider.SessionHeader{AppPublicKey: "ABCD", Chain: "0021", SessionHeight: 21}
+
+	return session
+}
+
+func TestInputBuilder_Build(t *testing.T) {
+	c := require.New(t)
+
+	aat := &provider.ViperAAT{AppPubKey: "ABCD"}
+	session := newBuilderSession()
+
+	input, err := NewInputBuilder().
+		WithSession(session).
+		WithAAT(aat).
+		WithData(`{"method":"eth_blockNumber"}`).
+		WithBlockchain("0021").
+		WithNode(session.Nodes[1]).
+		WithHeaders(provider.RelayHeaders{"X-Viper": "ohana"}).
+		Build()
+	c.NoError(err)
+	c.Equal(&Input{
+		Blockchain: "0021",
+		Data:       `{"method":"eth_blockNumber"}`,
+		Headers:    provider.RelayHeaders{"X-Viper": "ohana"},
+		Node:       session.Nodes[1],
+		ViperAAT:   aat,
+		Session:    session,
+	}, input)
+
+	noNodesSession := newBuilderSession()
+	noNodesSession.Nodes = nil
+
+	noHeaderSession := newBuilderSession()
+	noHeaderSession.Header = nil
+
+	tests := []struct {
+		name        string
+		builder     *InputBuilder
+		expectedErr error
+	}{
+		{
+			name:        "no session",
+			builder:     NewInputBuilder().WithAAT(aat).WithBlockchain("0021"),
+			expectedErr: ErrNoSession,
+		},
+		{
+			name:        "no AAT",
+			builder:     NewInputBuilder().WithSession(session).WithBlockchain("0021"),
+			expectedErr: ErrNoViperAAT,
+		},
+		{
+			name:        "session without nodes",
+			builder:     NewInputBuilder().WithSession(noNodesSession).WithAAT(aat).WithBlockchain("0021"),
+			expectedErr: ErrSessionHasNoNodes,
+		},
+		{
+			name:        "session without header",
+			builder:     NewInputBuilder().WithSession(noHeaderSession).WithAAT(aat).WithBlockchain("0021"),
+			expectedErr: ErrNoSessionHeader,
+		},
+		{
+			name:        "no blockchain",
+			builder:     NewInputBuilder().WithSession(session).WithAAT(aat),
+			expectedErr: ErrChainMismatch,
+		},
+		{
+			name:        "blockchain of other session",
+			builder:     NewInputBuilder().WithSession(session).WithAAT(aat).WithBlockchain("0001"),
+			expectedErr: ErrChainMismatch,
+		},
+		{
+			name:        "AAT of other app",
+			builder:     NewInputBuilder().WithSession(session).WithAAT(&provider.ViperAAT{AppPubKey: "EFGH"}).WithBlockchain("0021"),
+			expectedErr: ErrAATMismatch,
+		},
+		{
+			name: "node not in session",
+			builder: NewInputBuilder().WithSession(session).WithAAT(aat).WithBlockchain("0021").
+				WithNode(&provider.Node{PublicKey: "FIU"}),
+			expectedErr: ErrNodeNotInSession,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := tt.builder.Build()
+			require.Equal(t, tt.expectedErr, err)
+			require.Nil(t, input)
+		})
+	}
+}
+
+func TestRelayer_ChainMismatch(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, &providerMock{})
+
+	input := &Input{
+		Blockchain: "0001",
+		ViperAAT:   &provider.ViperAAT{AppPubKey: "ABCD"},
+		Session:    newBuilderSession(),
+	}
+
+	relay, err := relayer.Relay(input, nil)
+	c.Equal(ErrChainMismatch, err)
+	c.Empty(relay)
+
+	input.Blockchain = "0021"
+	input.ViperAAT = &provider.ViperAAT{AppPubKey: "EFGH"}
+
+	relay, err = relayer.Relay(input, nil)
+	c.Equal(ErrAATMismatch, err)
+	c.Empty(relay)
+
+	input.ViperAAT = &provider.ViperAAT{AppPubKey: "ABCD"}
+
+	relay, err = relayer.Relay(input, nil)
+	c.NoError(err)
+	c.NotEmpty(relay)
+}
@@ -0,0 +1,98 @@
+package relayer
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// JSONRPCVersion is the version of the JSON-RPC envelopes built by NewJSONRPCInput
+const JSONRPCVersion = "2.0"
+
+var (
+	// ErrJSONRPC error when the relayed chain answers with a JSON-RPC error object
+	ErrJSONRPC = provider.ErrJSONRPC
+	// ErrNoJSONRPCResult error when a JSON-RPC response has neither result nor error
+	ErrNoJSONRPCResult = provider.ErrNoJSONRPCResult
+)
+
+// JSONRPCRequest represents a JSON-RPC request envelope
+// ID is a number or a string, nil sends a null id
+type JSONRPCRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+	ID      any    `json:"id"`
+}
+
+// JSONRPCResponse represents a JSON-RPC response envelope
+type JSONRPCResponse = provider.JSONRPCResponse
+
+// JSONRPCError represents the error object of a JSON-RPC response
+type JSONRPCError = provider.JSONRPCError
+
+func newJSONRPCInput(blockchain string, envelope any) (*Input, error) {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Input{
+		Blockchain: blockchain,
+		Data:       string(data),
+		Method:     http.MethodPost,
+		Path:       "",
+	}, nil
+}
+
+// NewJSONRPCInput returns a relay Input with the JSON-RPC request of given method and params as data
+// The session and Viper AAT must still be set by the caller
+func NewJSONRPCInput(blockchain, method string, params any, id any) (*Input, error) {
+	return newJSONRPCInput(blockchain, &JSONRPCRequest{
+		JSONRPC: JSONRPCVersion,
+		Method:  method,
+		Params:  params,
+		ID:      id,
+	})
+}
+
+// NewJSONRPCBatchInput returns a relay Input with the given JSON-RPC requests as a batch
+// Requests without version use JSONRPCVersion, the given requests are not modified
+func NewJSONRPCBatchInput(blockchain string, requests []*JSONRPCRequest) (*Input, error) {
+	batch := make([]JSONRPCRequest, len(requests))
+
+	for i, request := range requests {
+		batch[i] = *request
+
+		if batch[i].JSONRPC == "" {
+			batch[i].JSONRPC = JSONRPCVersion
+		}
+	}
+
+	return newJSONRPCInput(blockchain, batch)
+}
+
+// ParseJSONRPCResponse unmarshals the JSON-RPC result of the relay output into result
+// A JSON-RPC error object is returned as *JSONRPCError
+func ParseJSONRPCResponse(output *Output, result any) error {
+	response, err := output.RelayOutput.AsJSONRPC()
+	if err != nil {
+		return err
+	}
+
+	return response.Decode(result)
+}
+
+// ParseJSONRPCBatchResponse returns the JSON-RPC responses of a batch relay output
+// Responses can come in any order, use their raw ID to match them with the requests
+func ParseJSONRPCBatchResponse(output *Output) ([]*JSONRPCResponse, error) {
+	responses := []*JSONRPCResponse{}
+
+	err := json.Unmarshal([]byte(output.RelayOutput.Response), &responses)
+	if err != nil {
+		return nil, err
+	}
+
+	return responses, nil
+}
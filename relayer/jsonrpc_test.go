@@ -0,0 +1,141 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJSONRPCInput(t *testing.T) {
+	c := require.New(t)
+
+	input, err := NewJSONRPCInput("0021", "eth_blockNumber", nil, 1)
+	c.NoError(err)
+	c.Equal("0021", input.Blockchain)
+	c.Equal(`{"jsonrpc":"2.0","method":"eth_blockNumber","id":1}`, input.Data)
+	c.Equal(http.MethodPost, input.Method)
+	c.Empty(input.Path)
+
+	input, err = NewJSONRPCInput("0021", "eth_getBalance", []string{"0xabc", "latest"}, 2)
+	c.NoError(err)
+	c.Equal(`{"jsonrpc":"2.0","method":"eth_getBalance","params":["0xabc","latest"],"id":2}`, input.Data)
+
+	input, err = NewJSONRPCInput("0021", "eth_call", make(chan int), 3)
+	c.Error(err)
+	c.Empty(input)
+
+	requests := []*JSONRPCRequest{
+		{Method: "eth_blockNumber", ID: 1},
+		{Method: "eth_chainId", ID: "chain"},
+		{Method: "eth_gasPrice"},
+	}
+
+	input, err = NewJSONRPCBatchInput("0021", requests)
+	c.NoError(err)
+	c.Equal(`[{"jsonrpc":"2.0","method":"eth_blockNumber","id":1},{"jsonrpc":"2.0","method":"eth_chainId","id":"chain"},`+
+		`{"jsonrpc":"2.0","method":"eth_gasPrice","id":null}]`, input.Data)
+	c.Empty(requests[0].JSONRPC)
+}
+
+func TestParseJSONRPCResponse(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+
+	input, err := NewJSONRPCInput("0021", "eth_blockNumber", nil, 1)
+	c.NoError(err)
+
+	input.ViperAAT = &provider.ViperAAT{}
+	input.Session = newSelectorSession()
+
+	addMockedNodeRelay("https://dummy.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	output, err := relayer.Relay(input, nil)
+	c.NoError(err)
+
+	var blockNumber string
+
+	c.NoError(ParseJSONRPCResponse(output, &blockNumber))
+	c.Equal("0xdd03e4", blockNumber)
+
+	addMockedNodeRelay("https://dummy.com", `{"id":1,"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found"}}`)
+
+	output, err = relayer.Relay(input, nil)
+	c.NoError(err)
+
+	err = ParseJSONRPCResponse(output, &blockNumber)
+	c.True(errors.Is(err, ErrJSONRPC))
+
+	var jsonRPCErr *JSONRPCError
+
+	c.ErrorAs(err, &jsonRPCErr)
+	c.Equal(-32601, jsonRPCErr.Code)
+	c.Equal("method not found", jsonRPCErr.Message)
+
+	addMockedNodeRelay("https://dummy.com", `{"id":1,"jsonrpc":"2.0"}`)
+
+	output, err = relayer.Relay(input, nil)
+	c.NoError(err)
+	c.Equal(ErrNoJSONRPCResult, ParseJSONRPCResponse(output, &blockNumber))
+
+	addMockedNodeRelay("https://dummy.com", `{"id":"abc","jsonrpc":"2.0","result":"0x21"}`)
+
+	output, err = relayer.Relay(input, nil)
+	c.NoError(err)
+	c.NoError(ParseJSONRPCResponse(output, &blockNumber))
+	c.Equal("0x21", blockNumber)
+
+	addMockedNodeRelay("https://dummy.com", `{"id":null,"jsonrpc":"2.0","error":{"code":-32700,"message":"parse error"}}`)
+
+	output, err = relayer.Relay(input, nil)
+	c.NoError(err)
+	c.True(errors.Is(ParseJSONRPCResponse(output, &blockNumber), ErrJSONRPC))
+
+	input, err = NewJSONRPCBatchInput("0021", []*JSONRPCRequest{
+		{Method: "eth_blockNumber", ID: 1},
+		{Method: "eth_chainId", ID: "chain"},
+	})
+	c.NoError(err)
+
+	input.ViperAAT = &provider.ViperAAT{}
+	input.Session = newSelectorSession()
+
+	addMockedNodeRelay("https://dummy.com", fmt.Sprintf("[%s,%s]",
+		`{"id":"chain","jsonrpc":"2.0","result":"0x1"}`,
+		`{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`))
+
+	output, err = relayer.Relay(input, nil)
+	c.NoError(err)
+
+	responses, err := ParseJSONRPCBatchResponse(output)
+	c.NoError(err)
+	c.Len(responses, 2)
+
+	results := map[string]string{}
+
+	for _, response := range responses {
+		var result string
+
+		c.NoError(response.Decode(&result))
+		results[string(response.ID)] = result
+	}
+
+	c.Equal(map[string]string{"1": "0xdd03e4", `"chain"`: "0x1"}, results)
+
+	responses, err = ParseJSONRPCBatchResponse(&Output{RelayOutput: &provider.RelayOutput{Response: `{"id":1}`}})
+	c.Error(err)
+	c.Empty(responses)
+}
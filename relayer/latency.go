@@ -0,0 +1,183 @@
+package relayer
+
+import (
+	"container/list"
+	"crypto/rand"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+const (
+	// DefaultLatencyAlpha is the default weight of the newest sample in the latency EWMA
+	DefaultLatencyAlpha = 0.2
+	// DefaultLatencyMaxNodes is the default amount of nodes tracked by a LatencyTracker
+	DefaultLatencyMaxNodes = 1000
+	// DefaultLatencyEpsilon is the default probability of probing a random node in LatencySelector
+	DefaultLatencyEpsilon = 0.1
+
+	randomFloatPrecision = 1 << 53
+)
+
+// NodeLatency represents the latency statistics of a node
+type NodeLatency struct {
+	PublicKey string
+	EWMA      time.Duration
+	Samples   int64
+}
+
+// LatencyTracker records the relay durations of nodes as an exponentially weighted moving average
+// Only the most recently updated nodes are kept, so memory stays bounded across many sessions
+type LatencyTracker struct {
+	mutex    sync.Mutex
+	alpha    float64
+	maxNodes int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+// NewLatencyTracker returns instance of LatencyTracker
+// alpha is the weight of the newest sample in (0, 1] and maxNodes the amount of nodes tracked, invalid values use the defaults
+func NewLatencyTracker(alpha float64, maxNodes int) *LatencyTracker {
+	if alpha <= 0 || alpha > 1 {
+		alpha = DefaultLatencyAlpha
+	}
+
+	if maxNodes <= 0 {
+		maxNodes = DefaultLatencyMaxNodes
+	}
+
+	return &LatencyTracker{
+		alpha:    alpha,
+		maxNodes: maxNodes,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// Record adds a relay duration sample for the node with given public key
+func (t *LatencyTracker) Record(publicKey string, duration time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	element, ok := t.entries[publicKey]
+	if !ok {
+		t.entries[publicKey] = t.order.PushFront(&NodeLatency{PublicKey: publicKey, EWMA: duration, Samples: 1})
+		t.evict()
+
+		return
+	}
+
+	latency := element.Value.(*NodeLatency)
+	latency.EWMA = time.Duration(t.alpha*float64(duration) + (1-t.alpha)*float64(latency.EWMA))
+	latency.Samples++
+
+	t.order.MoveToFront(element)
+}
+
+func (t *LatencyTracker) evict() {
+	for t.order.Len() > t.maxNodes {
+		oldest := t.order.Back()
+		t.order.Remove(oldest)
+		delete(t.entries, oldest.Value.(*NodeLatency).PublicKey)
+	}
+}
+
+// Latency returns the latency statistics of the node with given public key and if it has any sample
+func (t *LatencyTracker) Latency(publicKey string) (NodeLatency, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	element, ok := t.entries[publicKey]
+	if !ok {
+		return NodeLatency{}, false
+	}
+
+	return *element.Value.(*NodeLatency), true
+}
+
+// Stats returns a snapshot of the latency statistics of all tracked nodes keyed by public key
+func (t *LatencyTracker) Stats() map[string]NodeLatency {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := make(map[string]NodeLatency, len(t.entries))
+
+	for publicKey, element := range t.entries {
+		stats[publicKey] = *element.Value.(*NodeLatency)
+	}
+
+	return stats
+}
+
+// WithLatencyTracker records the duration of every successful relay in the given tracker
+func WithLatencyTracker(tracker *LatencyTracker) RelayerOption {
+	return func(r *Relayer) {
+		r.latencyTracker = tracker
+	}
+}
+
+// LatencySelector chooses the fastest session node known by its tracker (epsilon-greedy)
+// Nodes without samples are chosen first and with probability epsilon a random node is probed
+type LatencySelector struct {
+	tracker *LatencyTracker
+	epsilon float64
+	random  func() (float64, error)
+}
+
+// NewLatencySelector returns instance of LatencySelector, epsilon is the probability in [0, 1] of probing a random node
+func NewLatencySelector(tracker *LatencyTracker, epsilon float64) *LatencySelector {
+	if epsilon < 0 || epsilon > 1 {
+		epsilon = DefaultLatencyEpsilon
+	}
+
+	return &LatencySelector{
+		tracker: tracker,
+		epsilon: epsilon,
+		random:  randomFloat,
+	}
+}
+
+func randomFloat() (float64, error) {
+	value, err := rand.Int(rand.Reader, big.NewInt(randomFloatPrecision))
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(value.Int64()) / randomFloatPrecision, nil
+}
+
+// Select returns the fastest node of given session or a random one with probability epsilon
+func (s *LatencySelector) Select(session *provider.Session, input *Input) (*provider.Node, error) {
+	if len(session.Nodes) == 0 {
+		return nil, ErrSessionHasNoNodes
+	}
+
+	probe, err := s.random()
+	if err != nil {
+		return nil, err
+	}
+
+	if probe < s.epsilon {
+		return GetRandomSessionNode(session)
+	}
+
+	var fastest *provider.Node
+	var fastestLatency time.Duration
+
+	for _, node := range session.Nodes {
+		latency, ok := s.tracker.Latency(node.PublicKey)
+		if !ok {
+			return node, nil
+		}
+
+		if fastest == nil || latency.EWMA < fastestLatency {
+			fastest = node
+			fastestLatency = latency.EWMA
+		}
+	}
+
+	return fastest, nil
+}
@@ -0,0 +1,99 @@
+package relayer
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyTracker_Record(t *testing.T) {
+	c := require.New(t)
+
+	tracker := NewLatencyTracker(0.5, 2)
+
+	tracker.Record("AOG", 10*time.Millisecond)
+	tracker.Record("AOG", 20*time.Millisecond)
+
+	latency, ok := tracker.Latency("AOG")
+	c.True(ok)
+	c.Equal(15*time.Millisecond, latency.EWMA)
+	c.Equal(int64(2), latency.Samples)
+
+	tracker.Record("PJOG", time.Millisecond)
+	tracker.Record("OHANA", time.Millisecond)
+
+	_, ok = tracker.Latency("AOG")
+	c.False(ok)
+
+	stats := tracker.Stats()
+	c.Len(stats, 2)
+	c.Contains(stats, "PJOG")
+	c.Contains(stats, "OHANA")
+}
+
+func TestLatencySelector_Select(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	tracker := NewLatencyTracker(DefaultLatencyAlpha, DefaultLatencyMaxNodes)
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+		WithLatencyTracker(tracker), WithNodeSelector(NewLatencySelector(tracker, DefaultLatencyEpsilon)))
+
+	input := &Input{
+		ViperAAT: &provider.ViperAAT{},
+		Session: &provider.Session{
+			Header: &provider.SessionHeader{},
+			Nodes: []*provider.Node{
+				{PublicKey: "SLOW", ServiceURL: "https://slow.com"},
+				{PublicKey: "AOG", ServiceURL: "https://aog.com"},
+				{PublicKey: "PJOG", ServiceURL: "https://pjog.com"},
+			},
+		},
+	}
+
+	for _, node := range input.Session.Nodes {
+		delay := time.Duration(0)
+		if node.PublicKey == "SLOW" {
+			delay = 20 * time.Millisecond
+		}
+
+		httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s%s", node.ServiceURL, provider.ClientRelayRoute),
+			func(req *http.Request) (*http.Response, error) {
+				time.Sleep(delay)
+
+				return httpmock.NewStringResponse(http.StatusOK, `{"response": "{}", "signature": "abf"}`), nil
+			})
+	}
+
+	selections := map[string]int{}
+
+	for i := 0; i < 100; i++ {
+		relay, err := relayer.Relay(input, nil)
+		c.NoError(err)
+
+		selections[relay.Node.PublicKey]++
+	}
+
+	c.Less(selections["SLOW"], 25)
+	c.Len(tracker.Stats(), 3)
+
+	slowLatency, ok := tracker.Latency("SLOW")
+	c.True(ok)
+	c.GreaterOrEqual(slowLatency.EWMA, 20*time.Millisecond)
+
+	node, err := NewLatencySelector(tracker, 0).Select(&provider.Session{}, nil)
+	c.Equal(ErrSessionHasNoNodes, err)
+	c.Empty(node)
+}
@@ -0,0 +1,102 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	mathrand "math/rand"
+	"sync"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// ErrInvalidFailureRate error when a chaos failure rate is not in [0, 1]
+var ErrInvalidFailureRate = errors.New("failure rate must be between 0 and 1")
+
+// RelayHandler represents the relay of an input to a chosen node, signing included
+type RelayHandler func(ctx context.Context, input *Input, node *provider.Node, options *provider.RelayRequestOptions) (*Output, error)
+
+// RelayMiddleware wraps a RelayHandler, it can act before and after the relay or skip it
+type RelayMiddleware func(next RelayHandler) RelayHandler
+
+// WithRelayMiddleware registers a middleware wrapping every relay to a node, it can be used many times
+// The first registered middleware is the outermost one, stats, observers and retries see its result
+func WithRelayMiddleware(middleware RelayMiddleware) RelayerOption {
+	return func(r *Relayer) {
+		r.middlewares = append(r.middlewares, middleware)
+	}
+}
+
+// getRelayHandler returns the relay to a node wrapped by the registered middlewares
+// relay is sent instead of building one when it is for the node and session the middlewares relay to
+func (r *Relayer) getRelayHandler(relay *provider.RelayInput) RelayHandler {
+	handler := RelayHandler(func(ctx context.Context, input *Input, node *provider.Node,
+		options *provider.RelayRequestOptions) (*Output, error) {
+		return r.doRelayToNode(ctx, input, node, relay, options)
+	})
+
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// NewChaosMiddleware returns a middleware failing a random failureRate fraction of relays with errToInject
+// without calling the next handler, it is meant to test retries and failover
+// failureRate must be in [0, 1] or ErrInvalidFailureRate is returned
+func NewChaosMiddleware(failureRate float64, errToInject error) (RelayMiddleware, error) {
+	if failureRate < 0 || failureRate > 1 {
+		return nil, ErrInvalidFailureRate
+	}
+
+	var mutex sync.Mutex
+
+	random := mathrand.New(mathrand.NewSource(time.Now().UnixNano())) // #nosec G404
+
+	return func(next RelayHandler) RelayHandler {
+		return func(ctx context.Context, input *Input, node *provider.Node, options *provider.RelayRequestOptions) (*Output, error) {
+			mutex.Lock()
+			fail := random.Float64() < failureRate
+			mutex.Unlock()
+
+			if fail {
+				return nil, errToInject
+			}
+
+			return next(ctx, input, node, options)
+		}
+	}, nil
+}
+
+// NewLatencyMiddleware returns a middleware waiting fixed plus a random jitter in [0, jitter) before the next handler,
+// it is meant to test timeouts and latency based behaviors
+// The ctx error is returned at once if ctx is done during the wait
+func NewLatencyMiddleware(fixed time.Duration, jitter time.Duration) RelayMiddleware {
+	var mutex sync.Mutex
+
+	random := mathrand.New(mathrand.NewSource(time.Now().UnixNano())) // #nosec G404
+
+	return func(next RelayHandler) RelayHandler {
+		return func(ctx context.Context, input *Input, node *provider.Node, options *provider.RelayRequestOptions) (*Output, error) {
+			delay := fixed
+
+			if jitter > 0 {
+				mutex.Lock()
+				delay += time.Duration(random.Int63n(int64(jitter)))
+				mutex.Unlock()
+			}
+
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+
+			return next(ctx, input, node, options)
+		}
+	}
+}
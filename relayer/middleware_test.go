@@ -0,0 +1,130 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayer_WithRelayMiddleware(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	var calls []string
+
+	newMiddleware := func(name string) RelayMiddleware {
+		return func(next RelayHandler) RelayHandler {
+			return func(ctx context.Context, input *Input, node *provider.Node, options *provider.RelayRequestOptions) (*Output, error) {
+				calls = append(calls, name)
+
+				return next(ctx, input, node, options)
+			}
+		}
+	}
+
+	relayer := NewRelayer(signer, &providerMock{}, WithRelayMiddleware(newMiddleware("outer")),
+		WithRelayMiddleware(newMiddleware("inner")))
+
+	output, err := relayer.Relay(newBatchInputs(1)[0], nil)
+	c.NoError(err)
+	c.Equal(`{"id":0}`, output.RelayOutput.Response)
+	c.Equal([]string{"outer", "inner"}, calls)
+}
+
+func TestNewChaosMiddleware(t *testing.T) {
+	c := require.New(t)
+
+	_, err := NewChaosMiddleware(-0.1, nil)
+	c.Equal(ErrInvalidFailureRate, err)
+
+	_, err = NewChaosMiddleware(1.1, nil)
+	c.Equal(ErrInvalidFailureRate, err)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	errChaos := errors.New("chaos")
+
+	for _, failureRate := range []float64{0, 0.3, 1} {
+		chaos, err := NewChaosMiddleware(failureRate, errChaos)
+		c.NoError(err)
+
+		relayer := NewRelayer(signer, &providerMock{}, WithRelayMiddleware(chaos))
+		input := newBatchInputs(1)[0]
+		relays := 5000
+		failures := 0
+
+		for i := 0; i < relays; i++ {
+			_, err := relayer.Relay(input, nil)
+			if err != nil {
+				c.Equal(errChaos, err)
+				failures++
+			}
+		}
+
+		c.InDelta(failureRate, float64(failures)/float64(relays), 0.03)
+		c.Equal(int64(failures), relayer.Stats().TotalFailures)
+	}
+}
+
+func TestNewLatencyMiddleware(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	var startTime time.Time
+	var delays []time.Duration
+
+	start := func(next RelayHandler) RelayHandler {
+		return func(ctx context.Context, input *Input, node *provider.Node, options *provider.RelayRequestOptions) (*Output, error) {
+			startTime = time.Now()
+
+			return next(ctx, input, node, options)
+		}
+	}
+
+	end := func(next RelayHandler) RelayHandler {
+		return func(ctx context.Context, input *Input, node *provider.Node, options *provider.RelayRequestOptions) (*Output, error) {
+			delays = append(delays, time.Since(startTime))
+
+			return next(ctx, input, node, options)
+		}
+	}
+
+	relayer := NewRelayer(signer, &providerMock{}, WithRelayMiddleware(start),
+		WithRelayMiddleware(NewLatencyMiddleware(20*time.Millisecond, 10*time.Millisecond)), WithRelayMiddleware(end))
+	input := newBatchInputs(1)[0]
+
+	for i := 0; i < 10; i++ {
+		_, err = relayer.Relay(input, nil)
+		c.NoError(err)
+	}
+
+	c.Len(delays, 10)
+
+	for _, delay := range delays {
+		c.GreaterOrEqual(delay, 20*time.Millisecond)
+		c.LessOrEqual(delay, 30*time.Millisecond+10*time.Millisecond)
+	}
+
+	relayer = NewRelayer(signer, &providerMock{}, WithRelayMiddleware(NewLatencyMiddleware(time.Minute, 0)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	startTime = time.Now()
+
+	_, err = relayer.RelayWithContext(ctx, input, nil)
+	c.Equal(context.DeadlineExceeded, err)
+	c.Less(time.Since(startTime), time.Second)
+	c.Equal(int64(1), relayer.Stats().TotalFailures)
+}
@@ -1,6 +1,8 @@
 package relayer
 
 import (
+	"time"
+
 	"github.com/vishruthsk/viper-go/provider"
 )
 
@@ -11,9 +13,22 @@ type Input struct {
 	Headers    provider.RelayHeaders
 	Method     string
 	Node       *provider.Node
-	Path       string
-	ViperAAT   *provider.ViperAAT
-	Session    *provider.Session
+	// NodeServiceURL sends the relay to the session node with this service URL when Node is not set,
+	// the relay fails with ErrServiceURLNotInSession if no session node has it
+	NodeServiceURL string
+	Path           string
+	ViperAAT       *provider.ViperAAT
+	Session        *provider.Session
+	// CurrentHeight is the current block height, when set the relay fails with ErrSessionExpired
+	// if the session is no longer valid
+	CurrentHeight int
+	// Priority orders the relays queued in a PriorityRelayer, higher is dispatched first and 0 is the default
+	Priority int
+	// Timeout bounds the relay, including node selection, signing and the node call, 0 disables it
+	// A relay not finished in time fails with a TimeoutError telling how far it got
+	Timeout time.Duration
+	// DisableAltruist keeps RelayWithRetries from falling back to the altruist, for callers requiring proofs
+	DisableAltruist bool
 }
 
 // RequestHash struct holding data needed to create a request hash
@@ -23,19 +38,34 @@ type RequestHash struct {
 }
 
 // Output struct for data needed as output for relay request
+// Duration is the time taken by the relay network call, signing is not included
+// RefreshedSession is only set when the relay was retried with a session from the SessionProvider
+// Trace is only set when the relay was done with RelayRequestOptions.Trace
+// HashAlgorithm is the name of the Hasher the request hash and proof were hashed with
+// ServedByAltruist is set when every session node failed and the altruist answered, the output has no proof
+// Attempts is the amount of relays sent to session nodes, TriedNodes their public keys in order
+// and TotalDuration the time taken by the whole Relay, RelayWithRetries or RelayWithConsensus call
 type Output struct {
-	RelayOutput *provider.RelayOutput
-	Proof       *provider.RelayProof
-	Node        *provider.Node
+	RelayOutput      *provider.RelayOutput
+	Proof            *provider.RelayProof
+	Node             *provider.Node
+	Duration         time.Duration
+	RefreshedSession *provider.Session
+	Trace            *RelayTrace
+	HashAlgorithm    string
+	ServedByAltruist bool
+	Attempts         int
+	TriedNodes       []string
+	TotalDuration    time.Duration
 }
 
-// Order of fields matters for signature
-type relayProofForSignature struct {
-	Entropy            int64  `json:"entropy"`
-	SessionBlockHeight int    `json:"session_block_height"`
-	ServicerPubKey     string `json:"servicer_pub_key"`
-	Blockchain         string `json:"blockchain"`
-	Signature          string `json:"signature"`
-	Token              string `json:"token"`
-	RequestHash        string `json:"request_hash"`
+// RelayTrace struct that holds the time taken by every step of a relay
+// Validation and node selection are only traced by Relay, not by RelayWithConsensus or RelayWithRetries
+type RelayTrace struct {
+	ValidationDuration    time.Duration
+	NodeSelectionDuration time.Duration
+	HashingDuration       time.Duration
+	SigningDuration       time.Duration
+	NetworkDuration       time.Duration
+	TotalDuration         time.Duration
 }
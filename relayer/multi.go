@@ -0,0 +1,154 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// ErrUnknownChain error when a MultiRelayer has no profile for the input blockchain and no default profile
+var ErrUnknownChain = errors.New("unknown chain")
+
+// ChainProfile represents the relay settings of a blockchain in a MultiRelayer
+// Headers are the default headers of the chain, see WithDefaultHeaders
+// AltruistURL is the chain altruist, see WithAltruist, empty means no altruist
+// MaxPayloadBytes = 0 keeps the MultiRelayer limit, < 0 disables the limit, see WithMaxPayloadBytes
+// RetryOptions are used for every relay of the chain, see RelayWithRetries
+// NodeSelector = nil keeps the MultiRelayer selector, see WithNodeSelector
+type ChainProfile struct {
+	Headers         provider.RelayHeaders
+	AltruistURL     string
+	MaxPayloadBytes int
+	RetryOptions    *RetryOptions
+	NodeSelector    NodeSelector
+}
+
+// chainRelayer is the relayer built from a chain profile
+type chainRelayer struct {
+	relayer      *Relayer
+	retryOptions *RetryOptions
+	fromDefault  bool
+}
+
+// MultiRelayer relays to several blockchains from one process, each one with its own ChainProfile
+// Every chain has its own Relayer built with the MultiRelayer options followed by the profile settings,
+// so stats, trackers and caches are not shared between chains
+type MultiRelayer struct {
+	signer   Signer
+	provider Provider
+	options  []RelayerOption
+
+	chains         map[string]*chainRelayer
+	defaultProfile *ChainProfile
+	chainsMutex    sync.RWMutex
+}
+
+// NewMultiRelayer returns instance of MultiRelayer with no chains, the options apply to the relayer of every chain
+func NewMultiRelayer(signer Signer, provider Provider, options ...RelayerOption) *MultiRelayer {
+	return &MultiRelayer{
+		signer:   signer,
+		provider: provider,
+		options:  options,
+		chains:   map[string]*chainRelayer{},
+	}
+}
+
+// RegisterChain sets the profile of the blockchain, replacing the previous one
+func (m *MultiRelayer) RegisterChain(blockchain string, profile ChainProfile) {
+	chain := m.newChainRelayer(blockchain, profile)
+
+	m.chainsMutex.Lock()
+	defer m.chainsMutex.Unlock()
+
+	m.chains[blockchain] = chain
+}
+
+// SetDefaultProfile sets the profile of the blockchains with no registered profile, nil removes it
+// so those relays fail with ErrUnknownChain
+func (m *MultiRelayer) SetDefaultProfile(profile *ChainProfile) {
+	m.chainsMutex.Lock()
+	defer m.chainsMutex.Unlock()
+
+	m.defaultProfile = profile
+
+	for blockchain, chain := range m.chains {
+		if chain.fromDefault {
+			delete(m.chains, blockchain)
+		}
+	}
+}
+
+// Relay does relay request with the settings of the input blockchain profile
+func (m *MultiRelayer) Relay(input *Input, options *provider.RelayRequestOptions) (*Output, error) {
+	return m.RelayWithContext(context.Background(), input, options)
+}
+
+// RelayWithContext does Relay bounded by ctx, see RelayWithRetriesContext
+func (m *MultiRelayer) RelayWithContext(ctx context.Context, input *Input, options *provider.RelayRequestOptions) (*Output, error) {
+	chain, err := m.getChainRelayer(input.Blockchain)
+	if err != nil {
+		return nil, err
+	}
+
+	output, _, err := chain.relayer.RelayWithRetriesContext(ctx, input, options, chain.retryOptions)
+
+	return output, err
+}
+
+// getChainRelayer returns the relayer of the blockchain, the relayer of a chain using the default profile
+// is built on its first relay
+func (m *MultiRelayer) getChainRelayer(blockchain string) (*chainRelayer, error) {
+	m.chainsMutex.RLock()
+	chain, ok := m.chains[blockchain]
+	m.chainsMutex.RUnlock()
+
+	if ok {
+		return chain, nil
+	}
+
+	m.chainsMutex.Lock()
+	defer m.chainsMutex.Unlock()
+
+	chain, ok = m.chains[blockchain]
+	if ok {
+		return chain, nil
+	}
+
+	if m.defaultProfile == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownChain, blockchain)
+	}
+
+	chain = m.newChainRelayer(blockchain, *m.defaultProfile)
+	chain.fromDefault = true
+	m.chains[blockchain] = chain
+
+	return chain, nil
+}
+
+func (m *MultiRelayer) newChainRelayer(blockchain string, profile ChainProfile) *chainRelayer {
+	options := append([]RelayerOption{}, m.options...)
+
+	if profile.Headers != nil {
+		options = append(options, WithDefaultHeaders(profile.Headers))
+	}
+
+	if profile.AltruistURL != "" {
+		options = append(options, WithAltruist(blockchain, profile.AltruistURL))
+	}
+
+	if profile.MaxPayloadBytes != 0 {
+		options = append(options, WithMaxPayloadBytes(profile.MaxPayloadBytes))
+	}
+
+	if profile.NodeSelector != nil {
+		options = append(options, WithNodeSelector(profile.NodeSelector))
+	}
+
+	return &chainRelayer{
+		relayer:      NewRelayer(m.signer, m.provider, options...),
+		retryOptions: profile.RetryOptions,
+	}
+}
@@ -0,0 +1,86 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/utils-go/mock-client"
+)
+
+func TestMultiRelayer_Relay(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	multiRelayer := NewMultiRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+	multiRelayer.RegisterChain("0021", ChainProfile{
+		RetryOptions: &RetryOptions{MaxAttempts: 2, Backoff: time.Millisecond},
+	})
+	multiRelayer.RegisterChain("0040", ChainProfile{
+		RetryOptions: &RetryOptions{MaxAttempts: 4, Backoff: time.Millisecond, SwitchNodes: true},
+	})
+
+	for _, node := range newConsensusInput().Session.Nodes {
+		mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", node.ServiceURL, provider.ClientRelayRoute),
+			http.StatusInternalServerError, "{}")
+	}
+
+	var attemptsErr *RelayAttemptsError
+
+	input := newConsensusInput()
+	input.Node = input.Session.Nodes[0]
+
+	output, err := multiRelayer.Relay(input, nil)
+	c.Empty(output)
+	c.ErrorAs(err, &attemptsErr)
+	c.Equal(2, attemptsErr.Attempts)
+	c.Equal([]string{"AOG", "AOG"}, attemptsErr.TriedNodes)
+
+	input = newConsensusInput()
+	input.Blockchain = "0040"
+
+	output, err = multiRelayer.Relay(input, nil)
+	c.Empty(output)
+	c.ErrorAs(err, &attemptsErr)
+	c.Equal(4, attemptsErr.Attempts)
+	c.Subset(attemptsErr.TriedNodes, []string{"AOG", "PJOG", "OHANA"})
+
+	input.Blockchain = "0001"
+
+	output, err = multiRelayer.Relay(input, nil)
+	c.Empty(output)
+	c.True(errors.Is(err, ErrUnknownChain))
+
+	multiRelayer.SetDefaultProfile(&ChainProfile{MaxPayloadBytes: 10})
+
+	output, err = multiRelayer.Relay(input, nil)
+	c.Empty(output)
+	c.True(errors.Is(err, ErrPayloadTooLarge))
+
+	multiRelayer.SetDefaultProfile(nil)
+
+	output, err = multiRelayer.Relay(input, nil)
+	c.Empty(output)
+	c.True(errors.Is(err, ErrUnknownChain))
+
+	multiRelayer.RegisterChain("0001", ChainProfile{})
+	addMockedNodeRelay("https://aog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	input.Node = input.Session.Nodes[0]
+
+	output, err = multiRelayer.Relay(input, nil)
+	c.NoError(err)
+	c.Equal(1, output.Attempts)
+}
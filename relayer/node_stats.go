@@ -0,0 +1,234 @@
+package relayer
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+const (
+	// DefaultNodeStatsMaxNodes is the default amount of nodes tracked by WithNodeStats
+	DefaultNodeStatsMaxNodes = 1000
+	// nodeLatencySamples is the amount of latest successful relay durations kept per node for the quantiles
+	nodeLatencySamples = 128
+)
+
+// ErrorClass represents the kind of a failed relay to a node
+type ErrorClass string
+
+const (
+	// ErrorClassTimeout is a relay that did not finish in time
+	ErrorClassTimeout ErrorClass = "timeout"
+	// ErrorClassCanceled is a relay whose context was canceled
+	ErrorClassCanceled ErrorClass = "canceled"
+	// ErrorClassRelay is a relay error returned by the servicer, see provider.RelayError
+	ErrorClassRelay ErrorClass = "relay"
+	// ErrorClassServer is a 5xx response of the servicer
+	ErrorClassServer ErrorClass = "server"
+	// ErrorClassClient is a 4xx response of the servicer
+	ErrorClassClient ErrorClass = "client"
+	// ErrorClassInvalidResponse is a response that is not a valid signed relay response
+	ErrorClassInvalidResponse ErrorClass = "invalid_response"
+	// ErrorClassOther is any other error, like network errors
+	ErrorClassOther ErrorClass = "other"
+)
+
+var errorClasses = []struct {
+	err   error
+	class ErrorClass
+}{
+	{err: context.Canceled, class: ErrorClassCanceled},
+	{err: context.DeadlineExceeded, class: ErrorClassTimeout},
+	{err: ErrAttemptTimeout, class: ErrorClassTimeout},
+	{err: ErrTimeout, class: ErrorClassTimeout},
+	{err: provider.ErrRequestTimeout, class: ErrorClassTimeout},
+	{err: provider.Err5xxOnConnection, class: ErrorClassServer},
+	{err: provider.Err4xxOnConnection, class: ErrorClassClient},
+	{err: ErrInvalidResponseSignature, class: ErrorClassInvalidResponse},
+	{err: provider.ErrInvalidRelayResponse, class: ErrorClassInvalidResponse},
+	{err: provider.ErrNonJSONResponse, class: ErrorClassInvalidResponse},
+}
+
+// GetErrorClass returns the kind of given relay error
+func GetErrorClass(err error) ErrorClass {
+	var relayErr *provider.RelayError
+	if errors.As(err, &relayErr) {
+		return ErrorClassRelay
+	}
+
+	for _, errorClass := range errorClasses {
+		if errors.Is(err, errorClass.err) {
+			return errorClass.class
+		}
+	}
+
+	return ErrorClassOther
+}
+
+// NodeRelayStats represents the relay statistics of a node collected by WithNodeStats
+// Relays is Successes plus the sum of Failures, the latency quantiles are approximated over the latest
+// successful relays of the node
+type NodeRelayStats struct {
+	Relays     int64
+	Successes  int64
+	Failures   map[ErrorClass]int64
+	P50Latency time.Duration
+	P95Latency time.Duration
+}
+
+type nodeStatsEntry struct {
+	publicKey string
+	stats     NodeRelayStats
+	latencies []time.Duration
+	next      int
+}
+
+func (e *nodeStatsEntry) record(err error, duration time.Duration) {
+	e.stats.Relays++
+
+	if err != nil {
+		e.stats.Failures[GetErrorClass(err)]++
+
+		return
+	}
+
+	e.stats.Successes++
+
+	if len(e.latencies) < nodeLatencySamples {
+		e.latencies = append(e.latencies, duration)
+
+		return
+	}
+
+	e.latencies[e.next] = duration
+	e.next = (e.next + 1) % nodeLatencySamples
+}
+
+func (e *nodeStatsEntry) snapshot() NodeRelayStats {
+	stats := e.stats
+	stats.Failures = make(map[ErrorClass]int64, len(e.stats.Failures))
+
+	for class, failures := range e.stats.Failures {
+		stats.Failures[class] = failures
+	}
+
+	latencies := append([]time.Duration(nil), e.latencies...)
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	stats.P50Latency = getQuantile(latencies, 0.5)
+	stats.P95Latency = getQuantile(latencies, 0.95)
+
+	return stats
+}
+
+// getQuantile returns the nearest rank quantile of given sorted durations, 0 when there is none
+func getQuantile(sorted []time.Duration, quantile float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(quantile*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	return sorted[rank]
+}
+
+// nodeStatsCollector keeps the relay statistics of the most recently relayed nodes
+type nodeStatsCollector struct {
+	mutex    sync.Mutex
+	maxNodes int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+func newNodeStatsCollector(maxNodes int) *nodeStatsCollector {
+	if maxNodes <= 0 {
+		maxNodes = DefaultNodeStatsMaxNodes
+	}
+
+	return &nodeStatsCollector{
+		maxNodes: maxNodes,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (c *nodeStatsCollector) record(publicKey string, err error, duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[publicKey]
+	if ok {
+		c.order.MoveToFront(element)
+	} else {
+		element = c.order.PushFront(&nodeStatsEntry{
+			publicKey: publicKey,
+			stats:     NodeRelayStats{Failures: map[ErrorClass]int64{}},
+		})
+		c.entries[publicKey] = element
+	}
+
+	element.Value.(*nodeStatsEntry).record(err, duration)
+
+	for c.order.Len() > c.maxNodes {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*nodeStatsEntry).publicKey)
+	}
+}
+
+func (c *nodeStatsCollector) stats() map[string]NodeRelayStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := make(map[string]NodeRelayStats, len(c.entries))
+
+	for publicKey, element := range c.entries {
+		stats[publicKey] = element.Value.(*nodeStatsEntry).snapshot()
+	}
+
+	return stats
+}
+
+func (c *nodeStatsCollector) reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = map[string]*list.Element{}
+	c.order.Init()
+}
+
+// WithNodeStats enables collecting the relay statistics of every node relayed to, see NodeStats
+// Only the maxNodes most recently relayed nodes are kept so memory stays bounded, maxNodes <= 0 uses
+// DefaultNodeStatsMaxNodes
+func WithNodeStats(maxNodes int) RelayerOption {
+	return func(r *Relayer) {
+		r.nodeStats = newNodeStatsCollector(maxNodes)
+	}
+}
+
+// NodeStats returns a snapshot of the relay statistics of the tracked nodes keyed by public key
+// It is empty when the relayer was not created with WithNodeStats
+func (r *Relayer) NodeStats() map[string]NodeRelayStats {
+	if r.nodeStats == nil {
+		return map[string]NodeRelayStats{}
+	}
+
+	return r.nodeStats.stats()
+}
+
+// ResetNodeStats drops the relay statistics of every node, it can be called while relays are in flight
+func (r *Relayer) ResetNodeStats() {
+	if r.nodeStats == nil {
+		return
+	}
+
+	r.nodeStats.reset()
+}
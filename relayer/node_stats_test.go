@@ -0,0 +1,127 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayer_NodeStats(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	c.Empty(NewRelayer(signer, &providerMock{}).NodeStats())
+
+	relayer := NewRelayer(signer, &providerMock{}, WithNodeStats(0))
+	inputs := newBatchInputs(4)
+	inputs[3].Data = "fail"
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 48; j++ {
+				_, _ = relayer.Relay(inputs[j%len(inputs)], nil)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	stats := relayer.NodeStats()
+	c.NotEmpty(stats)
+
+	var relays, successes, failures int64
+
+	for _, nodeStats := range stats {
+		nodeFailures := int64(0)
+		for _, classFailures := range nodeStats.Failures {
+			nodeFailures += classFailures
+		}
+
+		c.Equal(nodeStats.Relays, nodeStats.Successes+nodeFailures)
+		c.LessOrEqual(nodeStats.P50Latency, nodeStats.P95Latency)
+
+		relays += nodeStats.Relays
+		successes += nodeStats.Successes
+		failures += nodeStats.Failures[ErrorClassServer]
+	}
+
+	c.Equal(int64(384), relays)
+	c.Equal(int64(288), successes)
+	c.Equal(int64(96), failures)
+	c.Equal(relayer.Stats().TotalAttempts, relays)
+
+	relayer.ResetNodeStats()
+	c.Empty(relayer.NodeStats())
+}
+
+func TestRelayer_WithNodeStatsBounded(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, &providerMock{}, WithNodeStats(2))
+	input := newBatchInputs(1)[0]
+
+	for _, node := range input.Session.Nodes {
+		input.Node = node
+
+		_, err = relayer.Relay(input, nil)
+		c.NoError(err)
+	}
+
+	stats := relayer.NodeStats()
+	c.Len(stats, 2)
+	c.NotContains(stats, "AOG")
+	c.Equal(int64(1), stats["OHANA"].Relays)
+}
+
+func TestNodeStatsCollector_Quantiles(t *testing.T) {
+	c := require.New(t)
+
+	collector := newNodeStatsCollector(1)
+
+	for i := 100; i > 0; i-- {
+		collector.record("AOG", nil, time.Duration(i)*time.Millisecond)
+	}
+
+	stats := collector.stats()["AOG"]
+	c.Equal(50*time.Millisecond, stats.P50Latency)
+	c.Equal(95*time.Millisecond, stats.P95Latency)
+
+	for i := 0; i < nodeLatencySamples; i++ {
+		collector.record("AOG", nil, time.Second)
+	}
+
+	stats = collector.stats()["AOG"]
+	c.Equal(time.Second, stats.P50Latency)
+	c.Equal(int64(100+nodeLatencySamples), stats.Successes)
+}
+
+func TestGetErrorClass(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal(ErrorClassRelay, GetErrorClass(&NodeRelayError{Err: &provider.RelayError{Code: provider.OverServiceError}}))
+	c.Equal(ErrorClassServer, GetErrorClass(&NodeRelayError{Err: provider.Err5xxOnConnection}))
+	c.Equal(ErrorClassClient, GetErrorClass(provider.Err4xxOnConnection))
+	c.Equal(ErrorClassTimeout, GetErrorClass(ErrAttemptTimeout))
+	c.Equal(ErrorClassTimeout, GetErrorClass(&TimeoutError{}))
+	c.Equal(ErrorClassCanceled, GetErrorClass(context.Canceled))
+	c.Equal(ErrorClassInvalidResponse, GetErrorClass(provider.ErrNonJSONResponse))
+	c.Equal(ErrorClassOther, GetErrorClass(errors.New("connection reset")))
+}
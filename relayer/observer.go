@@ -0,0 +1,64 @@
+package relayer
+
+import (
+	"context"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// RelayObserver interface representing hooks called along the lifecycle of every relay to a node
+// attempt is 0 for single relays, the attempt number starting at 0 for RelayWithRetries
+// and the node index for RelayWithConsensus
+// Hooks can be called concurrently and must not block
+type RelayObserver interface {
+	OnRelayStart(input *Input, node *provider.Node, attempt int)
+	OnRelaySuccess(output *Output, duration time.Duration, attempt int)
+	OnRelayError(input *Input, node *provider.Node, err error, duration time.Duration, attempt int)
+}
+
+// WithRelayObserver registers an observer of every relay done by the relayer, it can be used many times
+func WithRelayObserver(observer RelayObserver) RelayerOption {
+	return func(r *Relayer) {
+		r.observers = append(r.observers, observer)
+	}
+}
+
+// relayToNode does the relay to given node through the middlewares recording its stats, notifying its errors
+// and calling the observers hooks
+// relay is the relay already built and signed for the node, like a batch signed one, nil builds and signs it
+// the duration given to the hooks includes the proof signing
+func (r *Relayer) relayToNode(ctx context.Context, input *Input, node *provider.Node, relay *provider.RelayInput,
+	options *provider.RelayRequestOptions, attempt int) (*Output, error) {
+	defer r.trackRelay()()
+
+	for _, observer := range r.observers {
+		observer.OnRelayStart(input, node, attempt)
+	}
+
+	startTime := time.Now()
+
+	output, err := r.getRelayHandler(relay)(ctx, input, node, options)
+
+	duration := time.Since(startTime)
+
+	r.stats.record(node, err, duration)
+
+	if r.nodeStats != nil {
+		r.nodeStats.record(node.PublicKey, err, duration)
+	}
+
+	if err != nil {
+		r.notifyRelayError(input, node, err)
+	}
+
+	for _, observer := range r.observers {
+		if err != nil {
+			observer.OnRelayError(input, node, err, duration, attempt)
+		} else {
+			observer.OnRelaySuccess(output, duration, attempt)
+		}
+	}
+
+	return output, err
+}
@@ -0,0 +1,129 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/utils-go/mock-client"
+)
+
+type countingObserver struct {
+	mutex     sync.Mutex
+	starts    map[int]int
+	successes map[int]int
+	errors    map[int]int
+}
+
+func newCountingObserver() *countingObserver {
+	return &countingObserver{
+		starts:    map[int]int{},
+		successes: map[int]int{},
+		errors:    map[int]int{},
+	}
+}
+
+func (o *countingObserver) OnRelayStart(input *Input, node *provider.Node, attempt int) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.starts[attempt]++
+}
+
+func (o *countingObserver) OnRelaySuccess(output *Output, duration time.Duration, attempt int) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.successes[attempt]++
+}
+
+func (o *countingObserver) OnRelayError(input *Input, node *provider.Node, err error, duration time.Duration, attempt int) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.errors[attempt]++
+}
+
+func TestRelayer_WithRelayObserver(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	observer := newCountingObserver()
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+		WithRelayObserver(observer))
+	input := newConsensusInput()
+	input.Node = input.Session.Nodes[0]
+
+	_, err = relayer.Relay(&Input{}, nil)
+	c.Equal(ErrNoSession, err)
+	c.Empty(observer.starts)
+
+	addMockedNodeRelay("https://aog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	_, err = relayer.Relay(input, nil)
+	c.NoError(err)
+	c.Equal(map[int]int{0: 1}, observer.starts)
+	c.Equal(map[int]int{0: 1}, observer.successes)
+	c.Empty(observer.errors)
+
+	observer = newCountingObserver()
+	relayer.observers = []RelayObserver{observer}
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://aog.com", provider.ClientRelayRoute),
+		http.StatusInternalServerError, "{}")
+
+	_, err = relayer.Relay(input, nil)
+	c.True(errors.Is(err, provider.Err5xxOnConnection))
+	c.Equal(map[int]int{0: 1}, observer.starts)
+	c.Empty(observer.successes)
+	c.Equal(map[int]int{0: 1}, observer.errors)
+
+	observer = newCountingObserver()
+	relayer.observers = []RelayObserver{observer}
+
+	mock.AddMultipleMockedPlainResponses(http.MethodPost, fmt.Sprintf("%s%s", "https://aog.com", provider.ClientRelayRoute),
+		[]int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+		[]string{"{}", "{}", `{"response": "{}", "signature": "abf"}`})
+
+	_, attempts, err := relayer.RelayWithRetries(input, nil, &RetryOptions{Backoff: time.Millisecond})
+	c.NoError(err)
+	c.Len(attempts, 3)
+	c.Equal(map[int]int{0: 1, 1: 1, 2: 1}, observer.starts)
+	c.Equal(map[int]int{2: 1}, observer.successes)
+	c.Equal(map[int]int{0: 1, 1: 1}, observer.errors)
+
+	observer = newCountingObserver()
+	relayer.observers = []RelayObserver{observer}
+
+	addMockedNodeRelay("https://aog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+	addMockedNodeRelay("https://pjog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://ohana.com", provider.ClientRelayRoute),
+		http.StatusInternalServerError, "{}")
+
+	consensusOutput, err := relayer.RelayWithConsensus(input, nil)
+	c.NoError(err)
+	c.Equal(map[int]int{0: 1, 1: 1, 2: 1}, observer.starts)
+
+	for i, nodeErr := range consensusOutput.Errors {
+		if nodeErr != nil {
+			c.Equal(1, observer.errors[i])
+			c.Zero(observer.successes[i])
+		} else {
+			c.Equal(1, observer.successes[i])
+			c.Zero(observer.errors[i])
+		}
+	}
+}
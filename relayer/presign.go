@@ -0,0 +1,183 @@
+package relayer
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+var (
+	// ErrNoProof error when a pre-signed relay has no proof
+	ErrNoProof = errors.New("no proof provided")
+	// ErrMissingProofSignature error when a pre-signed relay proof has no signature
+	ErrMissingProofSignature = errors.New("missing proof signature")
+	// ErrProofRequestHashMismatch error when a pre-signed proof is not for the request of the input
+	ErrProofRequestHashMismatch = errors.New("proof request hash does not match input")
+	// ErrProofBlockchainMismatch error when a pre-signed proof is not for the blockchain of the input
+	ErrProofBlockchainMismatch = errors.New("proof blockchain does not match input")
+	// ErrProofAATMismatch error when a pre-signed proof does not carry the AAT of the input
+	ErrProofAATMismatch = errors.New("proof AAT does not match input")
+	// ErrProofSessionMismatch error when a pre-signed proof is not for the session of the input
+	ErrProofSessionMismatch = errors.New("proof session height does not match input")
+)
+
+// BuildUnsignedProof returns the proof of the relay of given input to given node with given entropy
+// and the hex encoded bytes to sign for it, so proofs can be signed apart from the process relaying them
+// The request hash only covers the input headers, relayer default headers are not added to pre-signed relays
+// It hashes with DefaultHasher, use BuildUnsignedProofWithHasher for relayers with another hasher
+func BuildUnsignedProof(input *Input, node *provider.Node, entropy int64) (*provider.RelayProof, string, error) {
+	return BuildUnsignedProofWithHasher(DefaultHasher, input, node, entropy)
+}
+
+// BuildUnsignedProofWithHasher does BuildUnsignedProof hashing with given hasher
+func BuildUnsignedProofWithHasher(hasher Hasher, input *Input, node *provider.Node,
+	entropy int64) (*provider.RelayProof, string, error) {
+	err := validateInputSession(input)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if node == nil || !IsNodeInSession(input.Session, node) {
+		return nil, "", ErrNodeNotInSession
+	}
+
+	relay, err := newUnsignedRelay(hasher, input, input.Headers)
+	if err != nil {
+		return nil, "", err
+	}
+
+	proof := newUnsignedProof(input, node, relay.Proof.RequestHash, entropy)
+
+	proofBytes, err := GenerateProofBytesWithHasher(hasher, proof)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return proof, hex.EncodeToString(proofBytes), nil
+}
+
+// AttachSignature sets the hex encoded signature of the bytes returned by BuildUnsignedProof on the proof
+// When the AAT client public key of the proof is a valid key the signature is verified with it
+// and ErrInvalidProofSignature is returned if it does not match
+func AttachSignature(proof *provider.RelayProof, signature string) error {
+	return AttachSignatureWithHasher(DefaultHasher, proof, signature)
+}
+
+// AttachSignatureWithHasher does AttachSignature for proofs of BuildUnsignedProofWithHasher with given hasher
+func AttachSignatureWithHasher(hasher Hasher, proof *provider.RelayProof, signature string) error {
+	if signature == "" {
+		return ErrMissingProofSignature
+	}
+
+	signedProof := *proof
+	signedProof.Signature = signature
+
+	if signedProof.AAT != nil && isVerifyingKey(signedProof.AAT.ClientPubKey) {
+		err := verifyProofSignature(hasher, &signedProof)
+		if err != nil {
+			return err
+		}
+	}
+
+	proof.Signature = signature
+
+	return nil
+}
+
+func isVerifyingKey(publicKey string) bool {
+	decodedKey, err := hex.DecodeString(publicKey)
+
+	return err == nil && len(decodedKey) == ed25519.PublicKeySize
+}
+
+// RelayPreSigned does the relay of given input with a proof signed elsewhere, no signer is needed
+// The proof must come from BuildUnsignedProof for the same input and the relayer hasher,
+// its servicer is the node relayed to
+// A proof for another blockchain, AAT, session or request than the input is rejected before sending the relay
+func (r *Relayer) RelayPreSigned(input *Input, proof *provider.RelayProof) (*Output, error) {
+	if r.provider == nil {
+		return nil, ErrNoProvider
+	}
+
+	if r.hasher == nil {
+		return nil, ErrNoHasher
+	}
+
+	if proof == nil {
+		return nil, ErrNoProof
+	}
+
+	if proof.Signature == "" {
+		return nil, ErrMissingProofSignature
+	}
+
+	err := r.validateRelayInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	node := getSessionNode(input.Session, proof.ServicerPubKey)
+	if node == nil {
+		return nil, ErrNodeNotInSession
+	}
+
+	relay, err := r.buildPreSignedRelay(input, proof)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.relayToNode(context.Background(), input, node, relay, nil, 0)
+}
+
+// validatePreSignedProof checks the proof was built for the blockchain, AAT and session of the input
+func validatePreSignedProof(input *Input, proof *provider.RelayProof) error {
+	if proof.Blockchain != input.Blockchain {
+		return fmt.Errorf("%w: expected %s, got %s", ErrProofBlockchainMismatch, input.Blockchain, proof.Blockchain)
+	}
+
+	if proof.AAT == nil || input.ViperAAT == nil || *proof.AAT != *input.ViperAAT {
+		return ErrProofAATMismatch
+	}
+
+	if proof.SessionBlockHeight != input.Session.Header.SessionHeight {
+		return fmt.Errorf("%w: expected %d, got %d", ErrProofSessionMismatch, input.Session.Header.SessionHeight,
+			proof.SessionBlockHeight)
+	}
+
+	return nil
+}
+
+// buildPreSignedRelay returns the relay of input with the pre-signed proof
+func (r *Relayer) buildPreSignedRelay(input *Input, proof *provider.RelayProof) (*provider.RelayInput, error) {
+	err := validatePreSignedProof(input, proof)
+	if err != nil {
+		return nil, err
+	}
+
+	relay, err := newUnsignedRelay(r.hasher, input, input.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if relay.Proof.RequestHash != proof.RequestHash {
+		return nil, fmt.Errorf("%w: expected %s, got %s", ErrProofRequestHashMismatch, relay.Proof.RequestHash, proof.RequestHash)
+	}
+
+	relay.Proof = proof
+
+	return relay, nil
+}
+
+func getSessionNode(session *provider.Session, publicKey string) *provider.Node {
+	for _, node := range session.Nodes {
+		if node.PublicKey == publicKey {
+			return node
+		}
+	}
+
+	return nil
+}
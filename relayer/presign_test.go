@@ -0,0 +1,114 @@
+package relayer
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayer_RelayPreSigned(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	clientSigner, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	otherSigner, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	input := newConsensusInput()
+	input.ViperAAT = &provider.ViperAAT{Version: "0.0.1", AppPubKey: "ABCD", ClientPubKey: clientSigner.GetPublicKey()}
+
+	// signing process, holds the client key and no provider
+	proof, hash, err := BuildUnsignedProof(input, input.Session.Nodes[1], 21)
+	c.NoError(err)
+	c.Equal("PJOG", proof.ServicerPubKey)
+	c.Equal(int64(21), proof.Entropy)
+	c.Empty(proof.Signature)
+
+	hashBytes, err := hex.DecodeString(hash)
+	c.NoError(err)
+
+	otherSignature, err := otherSigner.Sign(hashBytes)
+	c.NoError(err)
+	c.Equal(ErrInvalidProofSignature, AttachSignature(proof, otherSignature))
+	c.Empty(proof.Signature)
+	c.Equal(ErrMissingProofSignature, AttachSignature(proof, ""))
+
+	signature, err := clientSigner.Sign(hashBytes)
+	c.NoError(err)
+	c.NoError(AttachSignature(proof, signature))
+	c.Equal(signature, proof.Signature)
+
+	// relay process, holds no key
+	relayer := NewRelayer(nil, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+
+	addMockedNodeRelay("https://pjog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	output, err := relayer.RelayPreSigned(input, proof)
+	c.NoError(err)
+	c.Equal(`{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`, output.RelayOutput.Response)
+	c.Equal("PJOG", output.Node.PublicKey)
+	c.Equal(proof, output.Proof)
+	c.NoError(verifyProofSignature(DefaultHasher, output.Proof))
+	c.Equal(int64(1), relayer.Stats().TotalSuccesses)
+
+	_, err = relayer.Relay(input, nil)
+	c.Equal(ErrNoSigner, err)
+
+	_, err = relayer.RelayPreSigned(input, nil)
+	c.Equal(ErrNoProof, err)
+
+	_, err = relayer.RelayPreSigned(input, &provider.RelayProof{})
+	c.Equal(ErrMissingProofSignature, err)
+
+	otherInput := *input
+	otherInput.Data = `{"method":"eth_chainId","id":1,"jsonrpc":"2.0"}`
+
+	_, err = relayer.RelayPreSigned(&otherInput, proof)
+	c.True(errors.Is(err, ErrProofRequestHashMismatch))
+
+	otherBlockchain := *proof
+	otherBlockchain.Blockchain = "0001"
+
+	_, err = relayer.RelayPreSigned(input, &otherBlockchain)
+	c.True(errors.Is(err, ErrProofBlockchainMismatch))
+
+	otherAAT := *proof
+	otherAAT.AAT = &provider.ViperAAT{Version: "0.0.1", AppPubKey: "DCBA", ClientPubKey: clientSigner.GetPublicKey()}
+
+	_, err = relayer.RelayPreSigned(input, &otherAAT)
+	c.Equal(ErrProofAATMismatch, err)
+
+	otherAAT.AAT = nil
+
+	_, err = relayer.RelayPreSigned(input, &otherAAT)
+	c.Equal(ErrProofAATMismatch, err)
+
+	otherSession := *proof
+	otherSession.SessionBlockHeight = 17
+
+	_, err = relayer.RelayPreSigned(input, &otherSession)
+	c.True(errors.Is(err, ErrProofSessionMismatch))
+	c.Equal(int64(1), relayer.Stats().TotalSuccesses+relayer.Stats().TotalFailures)
+
+	otherNode := *proof
+	otherNode.ServicerPubKey = "FIU"
+
+	_, err = relayer.RelayPreSigned(input, &otherNode)
+	c.Equal(ErrNodeNotInSession, err)
+
+	_, _, err = BuildUnsignedProof(input, &provider.Node{PublicKey: "FIU"}, 21)
+	c.Equal(ErrNodeNotInSession, err)
+
+	_, _, err = BuildUnsignedProof(&Input{}, input.Session.Nodes[0], 21)
+	c.Equal(ErrNoSession, err)
+}
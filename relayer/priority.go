@@ -0,0 +1,197 @@
+package relayer
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// ErrPriorityRelayerClosed error when a relay is submitted to or still queued in a closed PriorityRelayer
+var ErrPriorityRelayerClosed = errors.New("priority relayer closed")
+
+// RelayResult represents the result of a relay submitted to a PriorityRelayer
+type RelayResult struct {
+	Output *Output
+	Err    error
+}
+
+type priorityItem struct {
+	ctx      context.Context
+	input    *Input
+	options  *provider.RelayRequestOptions
+	results  chan RelayResult
+	sequence uint64
+}
+
+// priorityQueue is a max heap on Input.Priority, items with the same priority are kept in submission order
+type priorityQueue []*priorityItem
+
+func (q priorityQueue) Len() int { return len(q) }
+
+func (q priorityQueue) Less(i, j int) bool {
+	if q[i].input.Priority != q[j].input.Priority {
+		return q[i].input.Priority > q[j].input.Priority
+	}
+
+	return q[i].sequence < q[j].sequence
+}
+
+func (q priorityQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *priorityQueue) Push(item any) { *q = append(*q, item.(*priorityItem)) }
+
+func (q *priorityQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+
+	return item
+}
+
+// PriorityRelayer queues submitted relays and dispatches them with the wrapped Relayer in Input.Priority order
+// At most concurrency relays are in flight, when a slot frees up the queued relay with the highest priority is done
+type PriorityRelayer struct {
+	relayer *Relayer
+
+	mutex    sync.Mutex
+	queue    priorityQueue
+	sequence uint64
+	closed   bool
+
+	notify chan struct{}
+	slots  chan struct{}
+	done   chan struct{}
+}
+
+// NewPriorityRelayer returns instance of PriorityRelayer and starts its dispatcher, concurrency <= 0 is used as 1
+// Close must be called to stop the dispatcher
+func NewPriorityRelayer(relayer *Relayer, concurrency int) *PriorityRelayer {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	priorityRelayer := &PriorityRelayer{
+		relayer: relayer,
+		notify:  make(chan struct{}, 1),
+		slots:   make(chan struct{}, concurrency),
+		done:    make(chan struct{}),
+	}
+
+	go priorityRelayer.dispatch()
+
+	return priorityRelayer
+}
+
+// Submit queues the relay of given input, its result is sent on the returned channel
+// ctx bounds the relay and a relay whose ctx is done before being dispatched fails with the ctx error
+func (p *PriorityRelayer) Submit(ctx context.Context, input *Input, options *provider.RelayRequestOptions) <-chan RelayResult {
+	results := make(chan RelayResult, 1)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		results <- RelayResult{Err: ErrPriorityRelayerClosed}
+
+		return results
+	}
+
+	heap.Push(&p.queue, &priorityItem{
+		ctx:      ctx,
+		input:    input,
+		options:  options,
+		results:  results,
+		sequence: p.sequence,
+	})
+	p.sequence++
+
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+
+	return results
+}
+
+// Close stops the dispatcher, queued relays fail with ErrPriorityRelayerClosed and in flight ones finish
+func (p *PriorityRelayer) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	p.closed = true
+	close(p.done)
+
+	for _, item := range p.queue {
+		item.results <- RelayResult{Err: ErrPriorityRelayerClosed}
+	}
+
+	p.queue = nil
+}
+
+func (p *PriorityRelayer) dispatch() {
+	for {
+		select {
+		case p.slots <- struct{}{}:
+		case <-p.done:
+			return
+		}
+
+		item := p.next()
+		if item == nil {
+			return
+		}
+
+		go p.relay(item)
+	}
+}
+
+// next waits for a queued relay and pops the one with the highest priority, returns nil when closed
+func (p *PriorityRelayer) next() *priorityItem {
+	for {
+		p.mutex.Lock()
+
+		if p.closed {
+			p.mutex.Unlock()
+
+			return nil
+		}
+
+		if p.queue.Len() > 0 {
+			item := heap.Pop(&p.queue).(*priorityItem)
+			p.mutex.Unlock()
+
+			return item
+		}
+
+		p.mutex.Unlock()
+
+		select {
+		case <-p.notify:
+		case <-p.done:
+			return nil
+		}
+	}
+}
+
+func (p *PriorityRelayer) relay(item *priorityItem) {
+	defer func() { <-p.slots }()
+
+	err := item.ctx.Err()
+	if err != nil {
+		item.results <- RelayResult{Err: err}
+
+		return
+	}
+
+	output, err := p.relayer.RelayWithContext(item.ctx, item.input, item.options)
+
+	item.results <- RelayResult{Output: output, Err: err}
+}
@@ -0,0 +1,79 @@
+package relayer
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/stretchr/testify/require"
+)
+
+type orderedProviderMock struct {
+	mutex   sync.Mutex
+	order   []string
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *orderedProviderMock) Relay(rpcURL string, input *provider.RelayInput, options *provider.RelayRequestOptions) (*provider.RelayOutput, error) {
+	p.mutex.Lock()
+	p.order = append(p.order, input.Payload.Data)
+	p.mutex.Unlock()
+
+	if input.Payload.Data == "block" {
+		close(p.started)
+		<-p.release
+	}
+
+	return &provider.RelayOutput{Response: input.Payload.Data}, nil
+}
+
+func TestPriorityRelayer_Submit(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	mockProvider := &orderedProviderMock{started: make(chan struct{}), release: make(chan struct{})}
+
+	priorityRelayer := NewPriorityRelayer(NewRelayer(signer, mockProvider), 1)
+	defer priorityRelayer.Close()
+
+	inputs := newBatchInputs(4)
+	inputs[0].Data = "block"
+	inputs[1].Data = "low"
+	inputs[1].Priority = -1
+	inputs[2].Data = "high"
+	inputs[2].Priority = 10
+	inputs[3].Data = "default"
+
+	blockResults := priorityRelayer.Submit(context.Background(), inputs[0], nil)
+	<-mockProvider.started
+
+	lowResults := priorityRelayer.Submit(context.Background(), inputs[1], nil)
+	highResults := priorityRelayer.Submit(context.Background(), inputs[2], nil)
+	defaultResults := priorityRelayer.Submit(context.Background(), inputs[3], nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	canceledResults := priorityRelayer.Submit(ctx, newBatchInputs(1)[0], nil)
+
+	close(mockProvider.release)
+
+	for _, results := range []<-chan RelayResult{blockResults, lowResults, highResults, defaultResults} {
+		result := <-results
+		c.NoError(result.Err)
+		c.NotNil(result.Output)
+	}
+
+	c.Equal(context.Canceled, (<-canceledResults).Err)
+	c.Equal([]string{"block", "high", "default", "low"}, mockProvider.order)
+
+	priorityRelayer.Close()
+
+	c.Equal(ErrPriorityRelayerClosed, (<-priorityRelayer.Submit(context.Background(), inputs[1], nil)).Err)
+}
@@ -0,0 +1,165 @@
+package relayer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// RecordedRelay represents a relay captured by a RequestRecorder
+// Input, Node and Output are copies taken when the relay was done, later changes to the originals do not affect them
+type RecordedRelay struct {
+	Input     *Input
+	Node      *provider.Node
+	Output    *Output
+	Err       error
+	Timestamp time.Time
+}
+
+// RequestRecorder holds the relays captured by the middleware returned by NewRequestRecorder
+type RequestRecorder struct {
+	entries []RecordedRelay
+	mutex   sync.Mutex
+}
+
+// NewRequestRecorder returns a RequestRecorder and the middleware recording every relay that goes through it
+// It is meant to replay the exact sequence of relays when debugging, entries are kept until Reset is called
+func NewRequestRecorder() (*RequestRecorder, RelayMiddleware) {
+	recorder := &RequestRecorder{}
+
+	return recorder, func(next RelayHandler) RelayHandler {
+		return func(ctx context.Context, input *Input, node *provider.Node, options *provider.RelayRequestOptions) (*Output, error) {
+			entry := RecordedRelay{
+				Input:     copyInput(input),
+				Node:      copyNode(node),
+				Timestamp: time.Now(),
+			}
+
+			output, err := next(ctx, input, node, options)
+
+			entry.Output = copyOutput(output)
+			entry.Err = err
+
+			recorder.mutex.Lock()
+			recorder.entries = append(recorder.entries, entry)
+			recorder.mutex.Unlock()
+
+			return output, err
+		}
+	}
+}
+
+// Entries returns the recorded relays in the order they were done
+func (r *RequestRecorder) Entries() []RecordedRelay {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entries := make([]RecordedRelay, len(r.entries))
+	copy(entries, r.entries)
+
+	return entries
+}
+
+// Reset drops every recorded relay
+func (r *RequestRecorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.entries = nil
+}
+
+func copyInput(input *Input) *Input {
+	if input == nil {
+		return nil
+	}
+
+	inputCopy := *input
+	inputCopy.Node = copyNode(input.Node)
+	inputCopy.ViperAAT = copyAAT(input.ViperAAT)
+	inputCopy.Session = copySession(input.Session)
+
+	if input.Headers != nil {
+		inputCopy.Headers = make(provider.RelayHeaders, len(input.Headers))
+
+		for key, value := range input.Headers {
+			inputCopy.Headers[key] = value
+		}
+	}
+
+	return &inputCopy
+}
+
+func copyOutput(output *Output) *Output {
+	if output == nil {
+		return nil
+	}
+
+	outputCopy := *output
+	outputCopy.Node = copyNode(output.Node)
+	outputCopy.RefreshedSession = copySession(output.RefreshedSession)
+	outputCopy.TriedNodes = append([]string(nil), output.TriedNodes...)
+
+	if output.RelayOutput != nil {
+		relayOutput := *output.RelayOutput
+		outputCopy.RelayOutput = &relayOutput
+	}
+
+	if output.Proof != nil {
+		proof := *output.Proof
+		proof.AAT = copyAAT(output.Proof.AAT)
+		outputCopy.Proof = &proof
+	}
+
+	if output.Trace != nil {
+		trace := *output.Trace
+		outputCopy.Trace = &trace
+	}
+
+	return &outputCopy
+}
+
+func copyNode(node *provider.Node) *provider.Node {
+	if node == nil {
+		return nil
+	}
+
+	nodeCopy := *node
+	nodeCopy.Chains = append([]string(nil), node.Chains...)
+
+	return &nodeCopy
+}
+
+func copyAAT(aat *provider.ViperAAT) *provider.ViperAAT {
+	if aat == nil {
+		return nil
+	}
+
+	aatCopy := *aat
+
+	return &aatCopy
+}
+
+func copySession(session *provider.Session) *provider.Session {
+	if session == nil {
+		return nil
+	}
+
+	sessionCopy := *session
+
+	if session.Header != nil {
+		header := *session.Header
+		sessionCopy.Header = &header
+	}
+
+	if session.Nodes != nil {
+		sessionCopy.Nodes = make([]*provider.Node, len(session.Nodes))
+
+		for i, node := range session.Nodes {
+			sessionCopy.Nodes[i] = copyNode(node)
+		}
+	}
+
+	return &sessionCopy
+}
@@ -0,0 +1,56 @@
+package relayer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRequestRecorder(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	recorder, middleware := NewRequestRecorder()
+	relayer := NewRelayer(signer, &providerMock{}, WithRelayMiddleware(middleware))
+
+	inputs := newBatchInputs(3)
+	inputs[2].Data = "fail"
+	inputs[0].Headers = provider.RelayHeaders{"Content-Type": "application/json"}
+
+	for _, input := range inputs {
+		_, _ = relayer.Relay(input, nil)
+	}
+
+	entries := recorder.Entries()
+	c.Len(entries, 3)
+
+	c.Equal(`{"id":0}`, entries[0].Input.Data)
+	c.Equal(`{"id":0}`, entries[0].Output.RelayOutput.Response)
+	c.NoError(entries[0].Err)
+	c.NotEmpty(entries[0].Output.Proof.Signature)
+	c.Equal(entries[0].Node.PublicKey, entries[0].Output.Proof.ServicerPubKey)
+	c.False(entries[0].Timestamp.IsZero())
+
+	c.Equal("fail", entries[2].Input.Data)
+	c.True(errors.Is(entries[2].Err, provider.Err5xxOnConnection))
+	c.Nil(entries[2].Output)
+
+	inputs[0].Data = "mutated"
+	inputs[0].Headers["Content-Type"] = "text/plain"
+	inputs[0].Session.Nodes[0].PublicKey = "FIU"
+	inputs[0].Session.Header.SessionHeight = 42
+
+	c.Equal(`{"id":0}`, entries[0].Input.Data)
+	c.Equal("application/json", entries[0].Input.Headers["Content-Type"])
+	c.Equal("AOG", entries[0].Input.Session.Nodes[0].PublicKey)
+	c.Equal(21, entries[0].Input.Session.Header.SessionHeight)
+
+	recorder.Reset()
+	c.Empty(recorder.Entries())
+}
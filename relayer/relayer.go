@@ -3,16 +3,22 @@
 package relayer
 
 import (
+	"context"
+	"crypto/ed25519"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"math"
 	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/vishruthsk/viper-go/provider"
-
-	"golang.org/x/crypto/sha3"
 )
 
 var (
@@ -30,30 +36,253 @@ var (
 	ErrSessionHasNoNodes = errors.New("session has no nodes")
 	// ErrNodeNotInSession error when given node is not in session
 	ErrNodeNotInSession = errors.New("node not in session")
+	// ErrServiceURLNotInSession error when no session node has the input node service URL
+	ErrServiceURLNotInSession = errors.New("no session node with service URL")
+	// ErrNoRequestHash error when no request hash structure is provided
+	ErrNoRequestHash = errors.New("no request hash provided")
+	// ErrInvalidEntropyMax error when entropy upper bound is not positive
+	ErrInvalidEntropyMax = errors.New("entropy max must be positive")
+	// ErrNoEntropyReader error when no entropy reader is provided
+	ErrNoEntropyReader = errors.New("no entropy reader provided")
+	// ErrAATSessionMismatch error when the Viper AAT app public key is not the one of the session
+	ErrAATSessionMismatch = errors.New("AAT app public key does not match session")
+	// ErrInvalidResponseSignature error when the relay response is not signed by the servicer node
+	ErrInvalidResponseSignature = errors.New("invalid response signature")
+	// ErrSessionExpired error when the current height is outside of the session window
+	ErrSessionExpired = errors.New("session expired")
+	// ErrChainMismatch error when the input blockchain is not the chain of the session
+	ErrChainMismatch = errors.New("blockchain does not match session chain")
+	// ErrAATMismatch error when the Viper AAT app public key is not the one of the session, same as ErrAATSessionMismatch
+	ErrAATMismatch = ErrAATSessionMismatch
+	// ErrPayloadTooLarge error when the relay data, path and headers are bigger than the max payload size
+	ErrPayloadTooLarge = errors.New("relay payload too large")
 )
 
+// PayloadTooLargeError represents the thrown error when the relay payload exceeds the max payload size
+type PayloadTooLargeError struct {
+	Size  int
+	Limit int
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("%s: %d bytes, limit is %d bytes", ErrPayloadTooLarge, e.Size, e.Limit)
+}
+
+// Unwrap returns ErrPayloadTooLarge so the error can be checked with errors.Is
+func (e *PayloadTooLargeError) Unwrap() error {
+	return ErrPayloadTooLarge
+}
+
+// DefaultBlocksPerSession is the default number of blocks a session lasts
+const DefaultBlocksPerSession = 4
+
+// SessionExpiredError represents the thrown error when the current height is outside of the session window
+type SessionExpiredError struct {
+	SessionHeight    int
+	CurrentHeight    int
+	BlocksPerSession int
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *SessionExpiredError) Error() string {
+	return fmt.Sprintf("%s: height %d is outside of session [%d, %d)", ErrSessionExpired, e.CurrentHeight,
+		e.SessionHeight, e.SessionHeight+e.BlocksPerSession)
+}
+
+// Unwrap returns ErrSessionExpired so the error can be checked with errors.Is
+func (e *SessionExpiredError) Unwrap() error {
+	return ErrSessionExpired
+}
+
+// InvalidResponseSignatureError represents the thrown error when a relay response signature does not verify
+// It carries the relay output so the caller can still decide to use it
+type InvalidResponseSignatureError struct {
+	Output *Output
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *InvalidResponseSignatureError) Error() string {
+	return fmt.Sprintf("%s: node %s", ErrInvalidResponseSignature, e.Output.Node.PublicKey)
+}
+
+// Unwrap returns ErrInvalidResponseSignature so the error can be checked with errors.Is
+func (e *InvalidResponseSignatureError) Unwrap() error {
+	return ErrInvalidResponseSignature
+}
+
 // Provider interface representing provider functions necessary for Relayer Package
 type Provider interface {
 	Relay(rpcURL string, input *provider.RelayInput, options *provider.RelayRequestOptions) (*provider.RelayOutput, error)
 }
 
 // Signer interface representing signer functions necessary for Relayer Package
+// It can be implemented outside of this module, e.g. signer.RemoteSigner wraps an HSM or KMS signing callback
 type Signer interface {
 	Sign(payload []byte) (string, error)
 }
 
+// NodeRelayError represents the thrown error when the relay to a node fails, it carries the node that produced it
+type NodeRelayError struct {
+	Node *provider.Node
+	Err  error
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *NodeRelayError) Error() string {
+	return fmt.Sprintf("relay to node with ServicerPubKey: %s failed: %s", e.Node.PublicKey, e.Err)
+}
+
+// Unwrap returns the original relay error so it can be checked with errors.Is and errors.As
+func (e *NodeRelayError) Unwrap() error {
+	return e.Err
+}
+
 // Relayer implementation of relayer interface
 type Relayer struct {
-	signer   Signer
-	provider Provider
+	signer       Signer
+	provider     Provider
+	nodeSelector NodeSelector
+	entropyMax   int64
+
+	entropyReader   io.Reader
+	entropySequence EntropySequence
+	entropyTracker  *EntropyTracker
+	entropyMutex    sync.Mutex
+	hasher          Hasher
+
+	defaultHeaders  provider.RelayHeaders
+	maxPayloadBytes int
+
+	blocksPerSession         int
+	retryBaseDelay           time.Duration
+	retryMaxDelay            time.Duration
+	skipAATSessionValidation bool
+	validateResponse         bool
+	skipAATHashCache         bool
+	aatHashes                sync.Map
+
+	latencyTracker  *LatencyTracker
+	observers       []RelayObserver
+	middlewares     []RelayMiddleware
+	errorWatchers   []*relayErrorWatcher
+	watchersMutex   sync.RWMutex
+	sessionProvider SessionProvider
+	stats           *relayStats
+	nodeStats       *nodeStatsCollector
+
+	perNodeConcurrencyLimit int64
+	nodeSemaphores          sync.Map
+
+	altruists               map[string]string
+	altruistClient          *http.Client
+	altruistMaxResponseSize int64
+
+	debugSessionDrift bool
+	heightSource      HeightSource
+
+	subscriptionMaxResigns int
+
+	inFlight      int
+	inFlightIdle  chan struct{}
+	inFlightMutex sync.Mutex
+}
+
+// RelayerOption represents an optional setting of a Relayer
+type RelayerOption func(*Relayer)
+
+// WithNodeSelector sets the strategy used to choose the session node when the input has no node
+func WithNodeSelector(selector NodeSelector) RelayerOption {
+	return func(r *Relayer) {
+		r.nodeSelector = selector
+	}
+}
+
+// WithEntropyMax sets the exclusive upper bound of the proof entropy, defaults to math.MaxInt64
+// Entropy is uniformly distributed in [0, entropyMax)
+func WithEntropyMax(entropyMax int64) RelayerOption {
+	return func(r *Relayer) {
+		r.entropyMax = entropyMax
+	}
+}
+
+// WithEntropyReader sets the random source of the proof entropy, defaults to crypto/rand.Reader
+// The reader is only used by one relay at a time, a deterministic reader gives deterministic proofs
+func WithEntropyReader(reader io.Reader) RelayerOption {
+	return func(r *Relayer) {
+		r.entropyReader = reader
+	}
+}
+
+// WithDefaultHeaders sets relay headers sent with every relay, the input headers take precedence on conflict
+// The merged headers are part of the relay request hash
+func WithDefaultHeaders(headers map[string]string) RelayerOption {
+	return func(r *Relayer) {
+		r.defaultHeaders = headers
+	}
+}
+
+// WithMaxPayloadBytes fails relays whose data, path and headers are bigger than maxBytes with a PayloadTooLargeError
+// before hashing and signing them, maxBytes <= 0 disables the limit and it is disabled by default
+func WithMaxPayloadBytes(maxBytes int) RelayerOption {
+	return func(r *Relayer) {
+		r.maxPayloadBytes = maxBytes
+	}
+}
+
+// WithAATSessionValidation enables or disables checking that the Viper AAT app public key is the one of the session
+// It is enabled by default and only applies to sessions whose header has an app public key
+func WithAATSessionValidation(enabled bool) RelayerOption {
+	return func(r *Relayer) {
+		r.skipAATSessionValidation = !enabled
+	}
+}
+
+// WithResponseValidation enables or disables verifying the servicer signature of every relay response
+// It is disabled by default, invalid signatures return an InvalidResponseSignatureError
+func WithResponseValidation(enabled bool) RelayerOption {
+	return func(r *Relayer) {
+		r.validateResponse = enabled
+	}
+}
+
+// WithBlocksPerSession sets the number of blocks a session lasts, defaults to DefaultBlocksPerSession
+// It is used to check the session is still valid when the input has a current height
+func WithBlocksPerSession(blocksPerSession int) RelayerOption {
+	return func(r *Relayer) {
+		r.blocksPerSession = blocksPerSession
+	}
 }
 
 // NewRelayer returns instance of Relayer with given input
-func NewRelayer(signer Signer, provider Provider) *Relayer {
-	return &Relayer{
-		signer:   signer,
-		provider: provider,
+func NewRelayer(signer Signer, provider Provider, options ...RelayerOption) *Relayer {
+	relayer := &Relayer{
+		signer:       signer,
+		provider:     provider,
+		nodeSelector: &RandomSelector{},
+		entropyMax:   math.MaxInt64,
+
+		entropyReader: rand.Reader,
+		hasher:        DefaultHasher,
+		stats:         newRelayStats(),
+
+		blocksPerSession: DefaultBlocksPerSession,
+
+		subscriptionMaxResigns: DefaultSubscriptionMaxResigns,
+
+		altruistClient:          defaultAltruistClient,
+		altruistMaxResponseSize: DefaultAltruistMaxResponseSize,
+	}
+
+	for _, option := range options {
+		option(relayer)
 	}
+
+	return relayer
 }
 
 func (r *Relayer) validateRelayRequest(input *Input) error {
@@ -65,6 +294,69 @@ func (r *Relayer) validateRelayRequest(input *Input) error {
 		return ErrNoProvider
 	}
 
+	return r.validateBuildRequest(input)
+}
+
+// validateBuildRequest validates what is needed to build a relay, a provider is only needed to send it
+func (r *Relayer) validateBuildRequest(input *Input) error {
+	if r.signer == nil {
+		return ErrNoSigner
+	}
+
+	if r.entropyMax <= 0 {
+		return ErrInvalidEntropyMax
+	}
+
+	if r.entropyReader == nil {
+		return ErrNoEntropyReader
+	}
+
+	if r.hasher == nil {
+		return ErrNoHasher
+	}
+
+	return r.validateRelayInput(input)
+}
+
+func (r *Relayer) validateRelayInput(input *Input) error {
+	err := validateInputSession(input)
+	if err != nil {
+		return err
+	}
+
+	if !r.skipAATSessionValidation && isAATMismatch(input) {
+		return ErrAATMismatch
+	}
+
+	err = r.validateSessionWindow(input)
+	if err != nil {
+		return err
+	}
+
+	return r.validatePayloadSize(input)
+}
+
+// validatePayloadSize checks the data, path and merged headers of the input are not bigger than the max payload size
+func (r *Relayer) validatePayloadSize(input *Input) error {
+	if r.maxPayloadBytes <= 0 {
+		return nil
+	}
+
+	size := len(input.Data) + len(input.Path)
+
+	for key, value := range r.getRelayHeaders(input) {
+		size += len(key) + len(value)
+	}
+
+	if size > r.maxPayloadBytes {
+		return &PayloadTooLargeError{Size: size, Limit: r.maxPayloadBytes}
+	}
+
+	return nil
+}
+
+// validateInputSession checks the input has a session with nodes and header, an AAT and the session blockchain
+func validateInputSession(input *Input) error {
 	if input.Session == nil {
 		return ErrNoSession
 	}
@@ -81,54 +373,251 @@ func (r *Relayer) validateRelayRequest(input *Input) error {
 		return ErrNoSessionHeader
 	}
 
+	if input.Session.Header.Chain != "" && input.Session.Header.Chain != input.Blockchain {
+		return ErrChainMismatch
+	}
+
+	return nil
+}
+
+// isAATMismatch returns if the AAT app public key is not the one of the session header, a header without one matches any AAT
+func isAATMismatch(input *Input) bool {
+	return input.Session.Header.AppPublicKey != "" && input.Session.Header.AppPublicKey != input.ViperAAT.AppPubKey
+}
+
+// validateSessionWindow checks the current height is in [sessionHeight, sessionHeight + blocksPerSession)
+// the check is skipped when the input has no current height
+func (r *Relayer) validateSessionWindow(input *Input) error {
+	if input.CurrentHeight == 0 || r.blocksPerSession <= 0 {
+		return nil
+	}
+
+	sessionHeight := input.Session.Header.SessionHeight
+
+	if input.CurrentHeight < sessionHeight || input.CurrentHeight >= sessionHeight+r.blocksPerSession {
+		return &SessionExpiredError{
+			SessionHeight:    sessionHeight,
+			CurrentHeight:    input.CurrentHeight,
+			BlocksPerSession: r.blocksPerSession,
+		}
+	}
+
 	return nil
 }
 
-func getNode(input *Input) (*provider.Node, error) {
-	if input.Node == nil {
-		return GetRandomSessionNode(input.Session)
+func (r *Relayer) getNode(input *Input) (*provider.Node, error) {
+	if input.Node == nil && input.NodeServiceURL != "" {
+		return GetSessionNodeByServiceURL(input.Session, input.NodeServiceURL)
 	}
 
-	if !IsNodeInSession(input.Session, input.Node) {
+	node := input.Node
+
+	if node == nil {
+		selector := r.nodeSelector
+		if selector == nil {
+			selector = &RandomSelector{}
+		}
+
+		selectedNode, err := selector.Select(input.Session, input)
+		if err != nil {
+			return nil, err
+		}
+
+		node = selectedNode
+	}
+
+	if node == nil || !IsNodeInSession(input.Session, node) {
 		return nil, ErrNodeNotInSession
 	}
 
-	return input.Node, nil
+	return node, nil
 }
 
-func (r *Relayer) getSignedProofBytes(proof *provider.RelayProof) (string, error) {
-	proofBytes, err := GenerateProofBytes(proof)
+func (r *Relayer) getProofBytes(proof *provider.RelayProof) ([]byte, error) {
+	token, err := r.hashAAT(proof.AAT)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	return generateProofBytes(r.hasher, proof, token)
+}
+
+// getRelayHeaders returns a copy of the input headers merged with the default headers, the input headers win
+// on conflict
+// The copy keeps the hashed headers and the sent ones the same when the caller changes the input headers
+func (r *Relayer) getRelayHeaders(input *Input) provider.RelayHeaders {
+	if len(r.defaultHeaders) == 0 && input.Headers == nil {
+		return nil
+	}
+
+	headers := make(provider.RelayHeaders, len(r.defaultHeaders)+len(input.Headers))
+
+	for key, value := range r.defaultHeaders {
+		headers[key] = value
 	}
 
-	return r.signer.Sign(proofBytes)
+	for key, value := range input.Headers {
+		headers[key] = value
+	}
+
+	return headers
 }
 
 // Relay does relay request with given input
 func (r *Relayer) Relay(input *Input, options *provider.RelayRequestOptions) (*Output, error) {
-	err := r.validateRelayRequest(input)
+	return r.RelayWithContext(context.Background(), input, options)
+}
+
+// RelayWithContext does relay request with given input
+// ctx bounds the wait for a free slot when a per node concurrency limit is set
+// and the whole relay when the input has a timeout
+func (r *Relayer) RelayWithContext(ctx context.Context, input *Input, options *provider.RelayRequestOptions) (*Output, error) {
+	return r.relayWithContext(ctx, input, nil, options)
+}
+
+// relayWithContext does RelayWithContext sending relay instead of building one when it is not nil
+func (r *Relayer) relayWithContext(ctx context.Context, input *Input, relay *provider.RelayInput,
+	options *provider.RelayRequestOptions) (*Output, error) {
+	defer r.trackRelay()()
+
+	startTime := time.Now()
+
+	output, err := r.relayWithInputTimeout(ctx, input, relay, options)
+	if err != nil {
+		return nil, r.annotateSessionDrift(input, err)
+	}
+
+	output.TotalDuration = time.Since(startTime)
+
+	return output, nil
+}
+
+// BuildRelay returns the relay that Relay would send for given input and the node it would be sent to
+// It validates the input, chooses the node and signs the proof but does not call the node, so no provider is needed
+func (r *Relayer) BuildRelay(input *Input) (*provider.RelayInput, *provider.Node, error) {
+	err := r.validateBuildRequest(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	node, err := r.getNode(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	relay, err := r.buildRelay(input, node, &RelayTrace{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return relay, node, nil
+}
+
+// SignProofOnly returns the signed proof of the relay of given input and the node it is for without sending the relay,
+// so the proof can be persisted and submitted later, no provider is needed
+func (r *Relayer) SignProofOnly(input *Input) (*provider.RelayProof, *provider.Node, error) {
+	relay, node, err := r.BuildRelay(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return relay.Proof, node, nil
+}
+
+func (r *Relayer) doRelayToNode(ctx context.Context, input *Input, node *provider.Node, relay *provider.RelayInput,
+	options *provider.RelayRequestOptions) (*Output, error) {
+	startTime := time.Now()
+	trace := &RelayTrace{}
+
+	if !isRelayFor(relay, input, node) {
+		var err error
+
+		relay, err = r.buildRelay(input, node, trace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	output, err := r.sendRelay(ctx, node, relay, options)
 	if err != nil {
 		return nil, err
 	}
 
-	node, err := getNode(input)
+	if options != nil && options.Trace {
+		trace.NetworkDuration = output.Duration
+		trace.TotalDuration = time.Since(startTime)
+		output.Trace = trace
+	}
+
+	return output, nil
+}
+
+// buildRelay returns the relay of given input to given node with its signed proof
+// the time taken to hash the request and to sign the proof is set in trace
+func (r *Relayer) buildRelay(input *Input, node *provider.Node, trace *RelayTrace) (*provider.RelayInput, error) {
+	relay, proofBytes, err := r.buildUnsignedRelay(input, node, trace)
 	if err != nil {
 		return nil, err
 	}
 
+	startTime := time.Now()
+
+	relay.Proof.Signature, err = r.signer.Sign(proofBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	trace.SigningDuration += time.Since(startTime)
+
+	return relay, nil
+}
+
+// buildUnsignedRelay returns the relay of given input to given node with the proof bytes to sign
+// the time taken to hash the request and to build the proof is set in trace
+func (r *Relayer) buildUnsignedRelay(input *Input, node *provider.Node,
+	trace *RelayTrace) (*provider.RelayInput, []byte, error) {
+	startTime := time.Now()
+
+	relay, err := newUnsignedRelay(r.hasher, input, r.getRelayHeaders(input))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	trace.HashingDuration = time.Since(startTime)
+	startTime = time.Now()
+
+	entropy, err := r.generateUniqueEntropy(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	relay.Proof = newUnsignedProof(input, node, relay.Proof.RequestHash, entropy)
+
+	proofBytes, err := r.getProofBytes(relay.Proof)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	trace.SigningDuration = time.Since(startTime)
+
+	return relay, proofBytes, nil
+}
+
+// newUnsignedRelay returns the relay payload and meta of given input with given headers
+// its proof only has the request hash
+func newUnsignedRelay(hasher Hasher, input *Input, headers provider.RelayHeaders) (*provider.RelayInput, error) {
 	relayPayload := &provider.RelayPayload{
 		Data:    input.Data,
 		Method:  input.Method,
 		Path:    input.Path,
-		Headers: input.Headers,
+		Headers: headers,
 	}
 
 	relayMeta := &provider.RelayMeta{
 		BlockHeight: input.Session.Header.SessionHeight,
 	}
 
-	hashedReq, err := HashRequest(&RequestHash{
+	hashedReq, err := HashRequestWithHasher(hasher, &RequestHash{
 		Payload: relayPayload,
 		Meta:    relayMeta,
 	})
@@ -136,49 +625,113 @@ func (r *Relayer) Relay(input *Input, options *provider.RelayRequestOptions) (*O
 		return nil, err
 	}
 
-	entropy, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
-	if err != nil {
-		return nil, err
-	}
+	return &provider.RelayInput{
+		Payload: relayPayload,
+		Meta:    relayMeta,
+		Proof:   &provider.RelayProof{RequestHash: hashedReq},
+	}, nil
+}
 
-	signedProofBytes, err := r.getSignedProofBytes(&provider.RelayProof{
-		RequestHash:        hashedReq,
-		Entropy:            entropy.Int64(),
+func newUnsignedProof(input *Input, node *provider.Node, requestHash string, entropy int64) *provider.RelayProof {
+	return &provider.RelayProof{
+		RequestHash:        requestHash,
+		Entropy:            entropy,
 		SessionBlockHeight: input.Session.Header.SessionHeight,
 		ServicerPubKey:     node.PublicKey,
 		Blockchain:         input.Blockchain,
 		AAT:                input.ViperAAT,
-	})
+	}
+}
+
+// sendRelay sends the built relay to given node
+func (r *Relayer) sendRelay(ctx context.Context, node *provider.Node, relay *provider.RelayInput,
+	options *provider.RelayRequestOptions) (*Output, error) {
+	setRelayStage(ctx, RelayStageSigned)
+
+	release, err := r.acquireNodeSlot(ctx, node)
 	if err != nil {
 		return nil, err
 	}
 
-	relayProof := &provider.RelayProof{
-		RequestHash:        hashedReq,
-		Entropy:            entropy.Int64(),
-		SessionBlockHeight: input.Session.Header.SessionHeight,
-		ServicerPubKey:     node.PublicKey,
-		Blockchain:         input.Blockchain,
-		AAT:                input.ViperAAT,
-		Signature:          signedProofBytes,
+	setRelayStage(ctx, RelayStageSent)
+
+	startTime := time.Now()
+
+	relayOutput, err := r.provider.Relay(node.ServiceURL, relay, options)
+
+	duration := time.Since(startTime)
+
+	release()
+
+	if err != nil {
+		return nil, &NodeRelayError{Node: node, Err: err}
 	}
 
-	relay := &provider.RelayInput{
-		Payload: relayPayload,
-		Meta:    relayMeta,
-		Proof:   relayProof,
+	if r.latencyTracker != nil {
+		r.latencyTracker.Record(node.PublicKey, duration)
 	}
 
-	relayOutput, err := r.provider.Relay(node.ServiceURL, relay, options)
+	output := &Output{
+		RelayOutput:   relayOutput,
+		Proof:         relay.Proof,
+		Node:          node,
+		Duration:      duration,
+		HashAlgorithm: r.hasher.Name(),
+	}
+
+	if r.validateResponse && !IsValidResponseSignature(output) {
+		return nil, &InvalidResponseSignatureError{Output: output}
+	}
+
+	return output, nil
+}
+
+// generateEntropy returns a random proof entropy in [0, entropyMax) read from the entropy reader,
+// or the next one of the entropy sequence when there is one
+func (r *Relayer) generateEntropy() (int64, error) {
+	r.entropyMutex.Lock()
+	defer r.entropyMutex.Unlock()
+
+	if r.entropySequence != nil {
+		entropy, err := r.entropySequence.Next()
+		if err != nil {
+			return 0, err
+		}
+
+		entropy %= r.entropyMax
+		if entropy < 0 {
+			entropy += r.entropyMax
+		}
+
+		return entropy, nil
+	}
+
+	entropy, err := rand.Int(r.entropyReader, big.NewInt(r.entropyMax))
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	return &Output{
-		RelayOutput: relayOutput,
-		Proof:       relayProof,
-		Node:        node,
-	}, nil
+	return entropy.Int64(), nil
+}
+
+// IsValidResponseSignature verifies the relay response is signed by the node the relay was sent to
+func IsValidResponseSignature(output *Output) bool {
+	publicKey, err := hex.DecodeString(output.Node.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+
+	signature, err := hex.DecodeString(output.RelayOutput.Signature)
+	if err != nil {
+		return false
+	}
+
+	responseBytes, err := GenerateResponseBytes(output.RelayOutput.Response, output.Proof.RequestHash)
+	if err != nil {
+		return false
+	}
+
+	return ed25519.Verify(publicKey, responseBytes, signature)
 }
 
 // GetRandomSessionNode returns a random node from given session
@@ -202,71 +755,93 @@ func IsNodeInSession(session *provider.Session, node *provider.Node) bool {
 	return false
 }
 
-// GenerateProofBytes returns relay proof as encoded bytes
-func GenerateProofBytes(proof *provider.RelayProof) ([]byte, error) {
-	token, err := HashAAT(proof.AAT)
-	if err != nil {
-		return nil, err
+// GetSessionNodeByServiceURL returns the session node with given service URL, a trailing slash is ignored
+// Returns ErrServiceURLNotInSession when no session node has it
+func GetSessionNodeByServiceURL(session *provider.Session, serviceURL string) (*provider.Node, error) {
+	serviceURL = strings.TrimSuffix(serviceURL, "/")
+
+	for _, node := range session.Nodes {
+		if strings.TrimSuffix(node.ServiceURL, "/") == serviceURL {
+			return node, nil
+		}
 	}
 
-	proofMap := &relayProofForSignature{
-		RequestHash:        proof.RequestHash,
-		Entropy:            proof.Entropy,
-		SessionBlockHeight: proof.SessionBlockHeight,
-		ServicerPubKey:     proof.ServicerPubKey,
-		Blockchain:         proof.Blockchain,
-		Token:              token,
-		Signature:          "",
+	return nil, fmt.Errorf("%w: %s", ErrServiceURLNotInSession, serviceURL)
+}
+
+// GenerateProofBytes returns relay proof as encoded bytes hashed with DefaultHasher
+func GenerateProofBytes(proof *provider.RelayProof) ([]byte, error) {
+	return GenerateProofBytesWithHasher(DefaultHasher, proof)
+}
+
+// GenerateProofBytesWithHasher returns relay proof as encoded bytes hashed with given hasher
+// The hashed proof is its canonical JSON, see CanonicalProofJSON
+// Returns ErrNoProof for a nil proof and ErrNoViperAAT for a proof without AAT
+func GenerateProofBytesWithHasher(hasher Hasher, proof *provider.RelayProof) ([]byte, error) {
+	if proof == nil {
+		return nil, ErrNoProof
 	}
 
-	marshaledProof, err := json.Marshal(proofMap)
+	token, err := HashAATWithHasher(hasher, proof.AAT)
 	if err != nil {
 		return nil, err
 	}
 
-	hasher := sha3.New256()
+	return generateProofBytes(hasher, proof, token)
+}
 
-	_, err = hasher.Write(marshaledProof)
+// generateProofBytes returns relay proof as encoded bytes to sign using the already hashed AAT token
+func generateProofBytes(hasher Hasher, proof *provider.RelayProof, token string) ([]byte, error) {
+	marshaledProof, err := canonicalProofJSON(proof, token)
 	if err != nil {
 		return nil, err
 	}
 
-	return hasher.Sum(nil), nil
+	return hasher.Hash(marshaledProof), nil
+}
+
+// GenerateResponseBytes returns the bytes signed by the servicer node for a relay response
+func GenerateResponseBytes(response, requestHash string) ([]byte, error) {
+	return provider.HashRelayResponse(response, requestHash)
 }
 
-// HashAAT returns Viper AAT as hashed string
+// HashAAT returns Viper AAT as hashed string with DefaultHasher
 func HashAAT(aat *provider.ViperAAT) (string, error) {
-	tokenToSend := *aat
-	tokenToSend.Signature = ""
+	return HashAATWithHasher(DefaultHasher, aat)
+}
 
-	marshaledAAT, err := json.Marshal(tokenToSend)
-	if err != nil {
-		return "", err
+// HashAATWithHasher returns Viper AAT as hashed string with given hasher, returns ErrNoViperAAT for a nil AAT
+func HashAATWithHasher(hasher Hasher, aat *provider.ViperAAT) (string, error) {
+	if aat == nil {
+		return "", ErrNoViperAAT
 	}
 
-	hasher := sha3.New256()
-
-	_, err = hasher.Write(marshaledAAT)
+	marshaledAAT, err := canonicalAATJSON(aat)
 	if err != nil {
 		return "", err
 	}
 
-	return hex.EncodeToString(hasher.Sum(nil)), nil
+	return hex.EncodeToString(hasher.Hash(marshaledAAT)), nil
 }
 
-// HashRequest creates the request hash from its structure
+// HashRequest creates the request hash from its structure with DefaultHasher
+// The structure is hashed as its JSON encoding, the payload headers are encoded with their keys sorted
+// so the hash does not depend on the header map ordering, nil and empty headers hash differently
 func HashRequest(reqHash *RequestHash) (string, error) {
-	marshaledReqHash, err := json.Marshal(reqHash)
-	if err != nil {
-		return "", err
-	}
+	return HashRequestWithHasher(DefaultHasher, reqHash)
+}
 
-	hasher := sha3.New256()
+// HashRequestWithHasher creates the request hash from its structure with given hasher
+// Returns ErrNoRequestHash for a nil structure
+func HashRequestWithHasher(hasher Hasher, reqHash *RequestHash) (string, error) {
+	if reqHash == nil {
+		return "", ErrNoRequestHash
+	}
 
-	_, err = hasher.Write(marshaledReqHash)
+	marshaledReqHash, err := json.Marshal(reqHash)
 	if err != nil {
 		return "", err
 	}
 
-	return hex.EncodeToString(hasher.Sum(nil)), nil
+	return hex.EncodeToString(hasher.Hash(marshaledReqHash)), nil
 }
@@ -0,0 +1,92 @@
+package relayer
+
+import (
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+func FuzzHashAAT(f *testing.F) {
+	_, aat := newHasherVectors()
+
+	f.Add(aat.Version, aat.AppPubKey, aat.ClientPubKey, aat.Signature)
+	f.Add("", "", "", "")
+	f.Add("0.0.1", "\xff\xfe", "<>& ", "\x00")
+
+	hash, err := HashAAT(nil)
+	if err != ErrNoViperAAT || hash != "" {
+		f.Fatalf("nil AAT returned hash %q with error: %v", hash, err)
+	}
+
+	f.Fuzz(func(t *testing.T, version, appPubKey, clientPubKey, signature string) {
+		hash, err := HashAAT(&provider.ViperAAT{
+			Version:      version,
+			AppPubKey:    appPubKey,
+			ClientPubKey: clientPubKey,
+			Signature:    signature,
+		})
+		if err != nil || len(hash) != 64 {
+			t.Fatalf("unexpected hash %q with error: %v", hash, err)
+		}
+	})
+}
+
+func FuzzHashRequest(f *testing.F) {
+	request, _ := newHasherVectors()
+	input := newConsensusInput()
+
+	f.Add(request.Payload.Data, request.Payload.Method, request.Payload.Path, "", "", request.Meta.BlockHeight)
+	f.Add(input.Data, "POST", "/v1/query", "Content-Type", "application/json", input.Session.Header.SessionHeight)
+	f.Add("\xff", "", "?a=b#c", "\x00", "\xfe", -1)
+
+	hash, err := HashRequest(nil)
+	if err != ErrNoRequestHash || hash != "" {
+		f.Fatalf("nil request returned hash %q with error: %v", hash, err)
+	}
+
+	f.Fuzz(func(t *testing.T, data, method, path, headerKey, headerValue string, blockHeight int) {
+		payload := &provider.RelayPayload{Data: data, Method: method, Path: path}
+		if headerKey != "" {
+			payload.Headers = provider.RelayHeaders{headerKey: headerValue}
+		}
+
+		hash, err := HashRequest(&RequestHash{Payload: payload, Meta: &provider.RelayMeta{BlockHeight: blockHeight}})
+		if err != nil || len(hash) != 64 {
+			t.Fatalf("unexpected hash %q with error: %v", hash, err)
+		}
+	})
+}
+
+func FuzzGenerateProofBytes(f *testing.F) {
+	_, aat := newHasherVectors()
+
+	f.Add("43f32acb6e19c5d039f6144cbb45f89727d7915ce96deb1d6e0fc0569b1adccf", int64(21), 21, "AOG", "0021",
+		aat.AppPubKey, aat.ClientPubKey)
+	f.Add("", int64(-1), 0, "", "", "", "")
+	f.Add("\xff", int64(1)<<62, -21, "\x00", "<>&", "\xfe", " ")
+
+	proofBytes, err := GenerateProofBytes(nil)
+	if err != ErrNoProof || proofBytes != nil {
+		f.Fatalf("nil proof returned bytes %x with error: %v", proofBytes, err)
+	}
+
+	proofBytes, err = GenerateProofBytes(&provider.RelayProof{})
+	if err != ErrNoViperAAT || proofBytes != nil {
+		f.Fatalf("proof without AAT returned bytes %x with error: %v", proofBytes, err)
+	}
+
+	f.Fuzz(func(t *testing.T, requestHash string, entropy int64, sessionHeight int, servicerPubKey, blockchain,
+		appPubKey, clientPubKey string) {
+		proofBytes, err := GenerateProofBytes(&provider.RelayProof{
+			RequestHash:        requestHash,
+			Entropy:            entropy,
+			SessionBlockHeight: sessionHeight,
+			ServicerPubKey:     servicerPubKey,
+			Blockchain:         blockchain,
+			AAT:                &provider.ViperAAT{AppPubKey: appPubKey, ClientPubKey: clientPubKey},
+		})
+		if err != nil || len(proofBytes) != 32 {
+			t.Fatalf("unexpected proof bytes %x with error: %v", proofBytes, err)
+		}
+	})
+}
@@ -1,9 +1,15 @@
 package relayer
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/vishruthsk/viper-go/signer"
 
@@ -14,6 +20,11 @@ import (
 	"github.com/vishruthsk/utils-go/mock-client"
 )
 
+var (
+	_ Signer = &signer.Signer{}
+	_ Signer = &signer.RemoteSigner{}
+)
+
 func TestRelayer_Relay(t *testing.T) {
 	c := require.New(t)
 
@@ -37,6 +48,13 @@ func TestRelayer_Relay(t *testing.T) {
 	c.Empty(relay)
 
 	relayer.provider = provider.NewProvider("https://dummy.com", []string{"https://dummy.com"})
+	relayer.entropyMax = 0
+
+	relay, err = relayer.Relay(input, nil)
+	c.Equal(ErrInvalidEntropyMax, err)
+	c.Empty(relay)
+
+	relayer.entropyMax = math.MaxInt64
 
 	relay, err = relayer.Relay(input, nil)
 	c.Equal(ErrNoSession, err)
@@ -60,7 +78,13 @@ func TestRelayer_Relay(t *testing.T) {
 	c.Equal(ErrNoSessionHeader, err)
 	c.Empty(relay)
 
-	input.Session.Header = &provider.SessionHeader{}
+	input.Session.Header = &provider.SessionHeader{AppPublicKey: "ABCD"}
+
+	relay, err = relayer.Relay(input, nil)
+	c.Equal(ErrAATSessionMismatch, err)
+	c.Empty(relay)
+
+	input.ViperAAT.AppPubKey = "ABCD"
 	input.Node = &provider.Node{PublicKey: "PJOG"}
 
 	relay, err = relayer.Relay(input, nil)
@@ -73,7 +97,12 @@ func TestRelayer_Relay(t *testing.T) {
 		http.StatusInternalServerError, "../provider/samples/client_relay.json")
 
 	relay, err = relayer.Relay(input, nil)
-	c.Equal(provider.Err5xxOnConnection, err)
+	c.True(errors.Is(err, provider.Err5xxOnConnection))
+
+	var nodeRelayErr *NodeRelayError
+
+	c.ErrorAs(err, &nodeRelayErr)
+	c.Equal(input.Session.Nodes[0], nodeRelayErr.Node)
 	c.Empty(relay)
 
 	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", provider.ClientRelayRoute),
@@ -98,3 +127,533 @@ func TestRelayer_Relay(t *testing.T) {
 	c.NoError(err)
 	c.NotEmpty(relay)
 }
+
+func TestRelayer_WithEntropyMax(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}), WithEntropyMax(2))
+	input := &Input{
+		ViperAAT: &provider.ViperAAT{},
+		Session: &provider.Session{
+			Header: &provider.SessionHeader{},
+			Nodes:  []*provider.Node{{PublicKey: "AOG", ServiceURL: "https://dummy.com"}},
+		},
+	}
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", provider.ClientRelayRoute),
+		http.StatusOK, "../provider/samples/client_relay.json")
+
+	for i := 0; i < 10; i++ {
+		relay, err := relayer.Relay(input, nil)
+		c.NoError(err)
+		c.GreaterOrEqual(relay.Proof.Entropy, int64(0))
+		c.Less(relay.Proof.Entropy, int64(2))
+	}
+
+	relayer = NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}), WithEntropyMax(-1))
+
+	relay, err := relayer.Relay(input, nil)
+	c.Equal(ErrInvalidEntropyMax, err)
+	c.Empty(relay)
+}
+
+func TestRelayer_WithAATSessionValidation(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	input := &Input{
+		ViperAAT: &provider.ViperAAT{AppPubKey: "ABCD"},
+		Session: &provider.Session{
+			Header: &provider.SessionHeader{AppPublicKey: "EFGH"},
+			Nodes:  []*provider.Node{{PublicKey: "AOG"}},
+		},
+		Node: &provider.Node{PublicKey: "PJOG"},
+	}
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+
+	relay, err := relayer.Relay(input, nil)
+	c.Equal(ErrAATSessionMismatch, err)
+	c.Empty(relay)
+
+	relayer = NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+		WithAATSessionValidation(false))
+
+	relay, err = relayer.Relay(input, nil)
+	c.Equal(ErrNodeNotInSession, err)
+	c.Empty(relay)
+
+	input.Session.Header.AppPublicKey = ""
+	relayer = NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+
+	relay, err = relayer.Relay(input, nil)
+	c.Equal(ErrNodeNotInSession, err)
+	c.Empty(relay)
+}
+
+func TestRelayer_WithResponseValidation(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	clientSigner, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	nodeSigner, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	input := &Input{
+		Data:     `{"method":"eth_blockNumber","id":1,"jsonrpc":"2.0"}`,
+		ViperAAT: &provider.ViperAAT{},
+		Session: &provider.Session{
+			Header: &provider.SessionHeader{SessionHeight: 21},
+			Nodes:  []*provider.Node{{PublicKey: nodeSigner.GetPublicKey(), ServiceURL: "https://dummy.com"}},
+		},
+	}
+
+	requestHash, err := HashRequest(&RequestHash{
+		Payload: &provider.RelayPayload{Data: input.Data},
+		Meta:    &provider.RelayMeta{BlockHeight: 21},
+	})
+	c.NoError(err)
+
+	response := `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`
+
+	responseBytes, err := GenerateResponseBytes(response, requestHash)
+	c.NoError(err)
+
+	signature, err := nodeSigner.Sign(responseBytes)
+	c.NoError(err)
+
+	relayer := NewRelayer(clientSigner, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+		WithResponseValidation(true))
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", provider.ClientRelayRoute), http.StatusOK,
+		fmt.Sprintf(`{"response": %q, "signature": %q}`, response, signature))
+
+	relay, err := relayer.Relay(input, nil)
+	c.NoError(err)
+	c.Equal(response, relay.RelayOutput.Response)
+	c.True(IsValidResponseSignature(relay))
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", provider.ClientRelayRoute), http.StatusOK,
+		fmt.Sprintf(`{"response": %q, "signature": %q}`, `{"id":1,"jsonrpc":"2.0","result":"0xdd03e5"}`, signature))
+
+	relay, err = relayer.Relay(input, nil)
+	c.True(errors.Is(err, ErrInvalidResponseSignature))
+	c.Empty(relay)
+
+	var signatureErr *InvalidResponseSignatureError
+
+	c.ErrorAs(err, &signatureErr)
+	c.Equal(`{"id":1,"jsonrpc":"2.0","result":"0xdd03e5"}`, signatureErr.Output.RelayOutput.Response)
+	c.Equal(nodeSigner.GetPublicKey(), signatureErr.Output.Node.PublicKey)
+
+	relayer = NewRelayer(clientSigner, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+
+	relay, err = relayer.Relay(input, &provider.RelayRequestOptions{VerifyResponse: true})
+	c.True(errors.Is(err, provider.ErrResponseSignatureMismatch))
+	c.Empty(relay)
+
+	relay, err = relayer.Relay(input, nil)
+	c.NoError(err)
+	c.False(IsValidResponseSignature(relay))
+}
+
+func TestRelayer_SessionWindow(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+	input := &Input{
+		ViperAAT: &provider.ViperAAT{},
+		Session: &provider.Session{
+			Header: &provider.SessionHeader{SessionHeight: 21},
+			Nodes:  []*provider.Node{{PublicKey: "AOG"}},
+		},
+		Node: &provider.Node{PublicKey: "PJOG"},
+	}
+
+	tests := []struct {
+		currentHeight int
+		expectedErr   error
+	}{
+		{currentHeight: 0, expectedErr: ErrNodeNotInSession},
+		{currentHeight: 20, expectedErr: ErrSessionExpired},
+		{currentHeight: 21, expectedErr: ErrNodeNotInSession},
+		{currentHeight: 24, expectedErr: ErrNodeNotInSession},
+		{currentHeight: 25, expectedErr: ErrSessionExpired},
+	}
+
+	for _, tt := range tests {
+		input.CurrentHeight = tt.currentHeight
+
+		relay, err := relayer.Relay(input, nil)
+		c.True(errors.Is(err, tt.expectedErr), "height %d", tt.currentHeight)
+		c.Empty(relay)
+	}
+
+	var expiredErr *SessionExpiredError
+
+	input.CurrentHeight = 25
+
+	_, err = relayer.Relay(input, nil)
+	c.ErrorAs(err, &expiredErr)
+	c.Equal(&SessionExpiredError{SessionHeight: 21, CurrentHeight: 25, BlocksPerSession: DefaultBlocksPerSession}, expiredErr)
+
+	relayer = NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}), WithBlocksPerSession(5))
+
+	_, err = relayer.Relay(input, nil)
+	c.Equal(ErrNodeNotInSession, err)
+
+	input.CurrentHeight = 26
+
+	_, err = relayer.Relay(input, nil)
+	c.True(errors.Is(err, ErrSessionExpired))
+}
+
+func TestRelayer_RelayDuration(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, &providerMock{delay: 10 * time.Millisecond})
+
+	relay, err := relayer.Relay(newBatchInputs(1)[0], nil)
+	c.NoError(err)
+	c.GreaterOrEqual(relay.Duration, 10*time.Millisecond)
+}
+
+func TestRelayer_WithEntropyReader(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	input := newConsensusInput()
+	input.Node = input.Session.Nodes[0]
+
+	addMockedNodeRelay("https://aog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	var proofs []*provider.RelayProof
+
+	for i := 0; i < 2; i++ {
+		relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+			WithEntropyReader(bytes.NewReader(bytes.Repeat([]byte{0x21}, 64))))
+
+		relay, err := relayer.Relay(input, nil)
+		c.NoError(err)
+		c.GreaterOrEqual(relay.Proof.Entropy, int64(0))
+		c.Less(relay.Proof.Entropy, int64(math.MaxInt64))
+
+		proofs = append(proofs, relay.Proof)
+	}
+
+	c.Equal(proofs[0], proofs[1])
+	c.Equal(int64(0x2121212121212121), proofs[0].Entropy)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+		WithEntropyReader(bytes.NewReader(bytes.Repeat([]byte{0x21}, 64))), WithEntropyMax(1000))
+
+	relay, err := relayer.Relay(input, nil)
+	c.NoError(err)
+	c.Less(relay.Proof.Entropy, int64(1000))
+
+	relayer = NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+		WithEntropyReader(bytes.NewReader(nil)))
+
+	relay, err = relayer.Relay(input, nil)
+	c.Equal(io.EOF, err)
+	c.Empty(relay)
+
+	relayer = NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+		WithEntropyReader(nil))
+
+	relay, err = relayer.Relay(input, nil)
+	c.Equal(ErrNoEntropyReader, err)
+	c.Empty(relay)
+}
+
+func TestRelayer_WithDefaultHeaders(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	input := newBatchInputs(1)[0]
+	input.Headers = provider.RelayHeaders{"Authorization": "Bearer input", "X-Input": "ohana"}
+
+	relayer := NewRelayer(signer, &providerMock{},
+		WithDefaultHeaders(map[string]string{"Authorization": "Bearer default", "X-Default": "aog"}))
+
+	c.Equal(provider.RelayHeaders{
+		"Authorization": "Bearer input",
+		"X-Input":       "ohana",
+		"X-Default":     "aog",
+	}, relayer.getRelayHeaders(input))
+	c.Equal(provider.RelayHeaders{"Authorization": "Bearer input", "X-Input": "ohana"}, input.Headers)
+
+	expectedHash, err := HashRequest(&RequestHash{
+		Payload: &provider.RelayPayload{Data: input.Data, Headers: relayer.getRelayHeaders(input)},
+		Meta:    &provider.RelayMeta{BlockHeight: 21},
+	})
+	c.NoError(err)
+
+	relay, err := relayer.Relay(input, nil)
+	c.NoError(err)
+	c.Equal(expectedHash, relay.Proof.RequestHash)
+
+	otherRelayer := NewRelayer(signer, &providerMock{},
+		WithDefaultHeaders(map[string]string{"Authorization": "Bearer default", "X-Default": "pjog"}))
+
+	otherRelay, err := otherRelayer.Relay(input, nil)
+	c.NoError(err)
+	c.NotEqual(relay.Proof.RequestHash, otherRelay.Proof.RequestHash)
+
+	relay, err = NewRelayer(signer, &providerMock{}).Relay(input, nil)
+	c.NoError(err)
+	c.NotEqual(expectedHash, relay.Proof.RequestHash)
+}
+
+func TestRelayer_BuildRelay(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	input := newBatchInputs(1)[0]
+
+	relay, node, err := NewRelayer(nil, nil).BuildRelay(input)
+	c.Equal(ErrNoSigner, err)
+	c.Empty(relay)
+	c.Empty(node)
+
+	relay, node, err = NewRelayer(signer, nil).BuildRelay(&Input{})
+	c.Equal(ErrNoSession, err)
+	c.Empty(relay)
+	c.Empty(node)
+
+	mockProvider := &providerMock{}
+	relayer := NewRelayer(signer, mockProvider)
+
+	relay, node, err = relayer.BuildRelay(input)
+	c.NoError(err)
+	c.True(IsNodeInSession(input.Session, node))
+	c.Equal(int64(0), mockProvider.maxInFlight)
+
+	c.Equal(input.Data, relay.Payload.Data)
+	c.Equal(21, relay.Meta.BlockHeight)
+	c.Equal(node.PublicKey, relay.Proof.ServicerPubKey)
+
+	expectedHash, err := HashRequest(&RequestHash{Payload: relay.Payload, Meta: relay.Meta})
+	c.NoError(err)
+	c.Equal(expectedHash, relay.Proof.RequestHash)
+
+	proofBytes, err := GenerateProofBytes(relay.Proof)
+	c.NoError(err)
+
+	signature, err := signer.Sign(proofBytes)
+	c.NoError(err)
+	c.Equal(signature, relay.Proof.Signature)
+
+	input.Node = node
+
+	output, err := relayer.Relay(input, nil)
+	c.NoError(err)
+	c.Equal(node, output.Node)
+	c.Equal(input.Data, output.RelayOutput.Response)
+	c.Equal(relay.Proof.RequestHash, output.Proof.RequestHash)
+}
+
+func TestRelayer_SignProofOnly(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	input := newBatchInputs(1)[0]
+
+	proof, node, err := NewRelayer(nil, nil).SignProofOnly(input)
+	c.Equal(ErrNoSigner, err)
+	c.Empty(proof)
+	c.Empty(node)
+
+	proof, node, err = NewRelayer(signer, nil).SignProofOnly(input)
+	c.NoError(err)
+	c.True(IsNodeInSession(input.Session, node))
+	c.Equal(node.PublicKey, proof.ServicerPubKey)
+	c.Equal(21, proof.SessionBlockHeight)
+
+	proofBytes, err := GenerateProofBytes(proof)
+	c.NoError(err)
+
+	signature, err := signer.Sign(proofBytes)
+	c.NoError(err)
+	c.Equal(signature, proof.Signature)
+
+	mockProvider := &providerMock{}
+
+	output, err := NewRelayer(nil, mockProvider).RelayPreSigned(input, proof)
+	c.NoError(err)
+	c.Equal(node, output.Node)
+	c.Equal(proof, output.Proof)
+}
+
+func TestRelayer_Trace(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	input := newBatchInputs(1)[0]
+
+	relayer := NewRelayer(&slowSignerMock{signer: signer, delay: 5 * time.Millisecond},
+		&providerMock{delay: 10 * time.Millisecond})
+
+	relay, err := relayer.Relay(input, nil)
+	c.NoError(err)
+	c.Nil(relay.Trace)
+
+	relay, err = relayer.Relay(input, &provider.RelayRequestOptions{Trace: true})
+	c.NoError(err)
+
+	trace := relay.Trace
+	c.NotNil(trace)
+	c.GreaterOrEqual(trace.SigningDuration, 5*time.Millisecond)
+	c.GreaterOrEqual(trace.NetworkDuration, 10*time.Millisecond)
+	c.Equal(relay.Duration, trace.NetworkDuration)
+	c.GreaterOrEqual(trace.TotalDuration, trace.ValidationDuration+trace.NodeSelectionDuration+
+		trace.HashingDuration+trace.SigningDuration+trace.NetworkDuration)
+}
+
+func TestRelayer_ProviderRequestHeaders(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var receivedHeaders http.Header
+
+	httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", provider.ClientRelayRoute),
+		func(req *http.Request) (*http.Response, error) {
+			receivedHeaders = req.Header
+
+			return httpmock.NewStringResponse(http.StatusOK, `{"response": "{}", "signature": "abf"}`), nil
+		})
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"},
+		provider.WithUserAgent("indexer/1.0"),
+		provider.WithRequestID("X-Correlation-ID", func() string { return "aog" })))
+
+	relay, err := relayer.Relay(newBatchInputs(1)[0], nil)
+	c.NoError(err)
+	c.NotEmpty(relay)
+	c.Equal("indexer/1.0", receivedHeaders.Get("User-Agent"))
+	c.Equal("aog", receivedHeaders.Get("X-Correlation-ID"))
+}
+
+func TestRelayer_WithMaxPayloadBytes(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	input := newBatchInputs(1)[0]
+	input.Data = `{"id":21}`
+	input.Headers = provider.RelayHeaders{"X-Viper": "aog"}
+
+	relay, err := NewRelayer(signer, &providerMock{}).Relay(input, nil)
+	c.NoError(err)
+	c.NotEmpty(relay)
+
+	mockProvider := &providerMock{}
+	relayer := NewRelayer(signer, mockProvider, WithMaxPayloadBytes(19),
+		WithDefaultHeaders(map[string]string{"X-Default": "pjog"}))
+
+	relay, err = relayer.Relay(input, nil)
+	c.True(errors.Is(err, ErrPayloadTooLarge))
+	c.Equal(&PayloadTooLargeError{Size: 32, Limit: 19}, err)
+	c.Empty(relay)
+	c.Equal(int64(0), mockProvider.maxInFlight)
+	c.False(IsRetryableError(err))
+
+	relay, err = NewRelayer(signer, mockProvider, WithMaxPayloadBytes(19)).Relay(input, nil)
+	c.NoError(err)
+	c.NotEmpty(relay)
+
+	input.Path = "/"
+
+	relay, err = NewRelayer(signer, mockProvider, WithMaxPayloadBytes(19)).Relay(input, nil)
+	c.Equal(&PayloadTooLargeError{Size: 20, Limit: 19}, err)
+	c.Empty(relay)
+
+	relay, err = NewRelayer(signer, mockProvider, WithMaxPayloadBytes(0)).Relay(input, nil)
+	c.NoError(err)
+	c.NotEmpty(relay)
+
+	input.Path = ""
+	input.Headers = nil
+	input.Data = strings.Repeat("a", 8*1024*1024)
+
+	relay, err = NewRelayer(signer, mockProvider).Relay(input, nil)
+	c.NoError(err)
+	c.NotEmpty(relay)
+}
+
+func TestRelayer_RelayWithNodeServiceURL(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, &providerMock{})
+	input := newConsensusInput()
+	input.NodeServiceURL = "https://pjog.com/"
+
+	for i := 0; i < 5; i++ {
+		output, err := relayer.Relay(input, nil)
+		c.NoError(err)
+		c.Equal("PJOG", output.Node.PublicKey)
+		c.Equal("PJOG", output.Proof.ServicerPubKey)
+	}
+
+	input.Node = input.Session.Nodes[2]
+
+	output, err := relayer.Relay(input, nil)
+	c.NoError(err)
+	c.Equal("OHANA", output.Node.PublicKey)
+
+	input.Node = nil
+	input.NodeServiceURL = "https://fiu.com"
+
+	output, err = relayer.Relay(input, nil)
+	c.True(errors.Is(err, ErrServiceURLNotInSession))
+	c.False(IsRetryableError(err))
+	c.Empty(output)
+
+	_, err = NewInputBuilder().
+		WithSession(input.Session).
+		WithAAT(input.ViperAAT).
+		WithBlockchain("0021").
+		WithNodeServiceURL("https://fiu.com").
+		Build()
+	c.True(errors.Is(err, ErrServiceURLNotInSession))
+}
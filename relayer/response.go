@@ -0,0 +1,86 @@
+package relayer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// ErrChainError error when the relayed chain answers with an error envelope
+var ErrChainError = errors.New("chain error")
+
+// ChainError represents the error envelope returned by the relayed chain
+// Code is 0 for envelopes without code, like {"error": "message"}
+// RPCError is the JSON-RPC error object of JSON-RPC envelopes, the one ParseJSONRPCResponse returns for the same
+// response, so errors.As with *JSONRPCError works with both APIs
+type ChainError struct {
+	Code     int
+	Message  string
+	RPCError *JSONRPCError
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("%s: code %d, message: %s", ErrChainError, e.Code, e.Message)
+}
+
+// Is returns true for ErrChainError so the error can be checked with errors.Is
+func (e *ChainError) Is(target error) bool {
+	return target == ErrChainError
+}
+
+// Unwrap returns the JSON-RPC error object of the envelope or ErrChainError if it is not a JSON-RPC one
+func (e *ChainError) Unwrap() error {
+	if e.RPCError != nil {
+		return e.RPCError
+	}
+
+	return ErrChainError
+}
+
+type errorEnvelope struct {
+	Error json.RawMessage `json:"error"`
+}
+
+// getChainError returns the chain error of a JSON object response with an error field or nil if there is none
+func getChainError(response []byte) error {
+	envelope := errorEnvelope{}
+
+	err := json.Unmarshal(response, &envelope)
+	if err != nil || len(envelope.Error) == 0 || string(envelope.Error) == "null" {
+		return nil
+	}
+
+	var message string
+	if json.Unmarshal(envelope.Error, &message) == nil {
+		return &ChainError{Message: message}
+	}
+
+	rpcErr := &JSONRPCError{}
+	if json.Unmarshal(envelope.Error, rpcErr) == nil {
+		return &ChainError{Code: rpcErr.Code, Message: rpcErr.Message, RPCError: rpcErr}
+	}
+
+	return &ChainError{Message: string(envelope.Error)}
+}
+
+// DecodeResponse unmarshals the relay response into v
+// Chain error envelopes, a JSON-RPC error object or {"error": "message"}, are returned as *ChainError
+// and responses that are not JSON return provider.ErrNonJSONResponse
+func (o *Output) DecodeResponse(v any) error {
+	response := []byte(o.RelayOutput.Response)
+
+	if !json.Valid(response) {
+		return provider.ErrNonJSONResponse
+	}
+
+	err := getChainError(response)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(response, v)
+}
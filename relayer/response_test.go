@@ -0,0 +1,102 @@
+package relayer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutput_DecodeResponse(t *testing.T) {
+	c := require.New(t)
+
+	type blockNumberResponse struct {
+		ID     int    `json:"id"`
+		Result string `json:"result"`
+	}
+
+	methodNotFound := &JSONRPCError{Code: -32601, Message: "method not found"}
+
+	tests := []struct {
+		name             string
+		response         string
+		expectedResponse *blockNumberResponse
+		expectedErr      error
+	}{
+		{
+			name:             "success",
+			response:         `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`,
+			expectedResponse: &blockNumberResponse{ID: 1, Result: "0xdd03e4"},
+		},
+		{
+			name:             "null error",
+			response:         `{"id":1,"result":"0xdd03e4","error":null}`,
+			expectedResponse: &blockNumberResponse{ID: 1, Result: "0xdd03e4"},
+		},
+		{
+			name:        "JSON-RPC error",
+			response:    `{"id":1,"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found"}}`,
+			expectedErr: &ChainError{Code: -32601, Message: "method not found", RPCError: methodNotFound},
+		},
+		{
+			name:        "string error",
+			response:    `{"error":"rate limited"}`,
+			expectedErr: &ChainError{Message: "rate limited"},
+		},
+		{
+			name:        "unknown error",
+			response:    `{"error":["rate limited"]}`,
+			expectedErr: &ChainError{Message: `["rate limited"]`},
+		},
+		{
+			name:        "html",
+			response:    "<html>bad gateway</html>",
+			expectedErr: provider.ErrNonJSONResponse,
+		},
+		{
+			name:        "binary",
+			response:    "\x00\x01\x02",
+			expectedErr: provider.ErrNonJSONResponse,
+		},
+	}
+
+	for _, test := range tests {
+		output := &Output{RelayOutput: &provider.RelayOutput{Response: test.response}}
+		response := &blockNumberResponse{}
+
+		err := output.DecodeResponse(response)
+		c.Equal(test.expectedErr, err, test.name)
+
+		if test.expectedErr == nil {
+			c.Equal(test.expectedResponse, response, test.name)
+		}
+	}
+
+	jsonRPCResponse := `{"id":1,"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found"}}`
+	output := &Output{RelayOutput: &provider.RelayOutput{Response: jsonRPCResponse}}
+
+	for _, err := range []error{output.DecodeResponse(&blockNumberResponse{}), ParseJSONRPCResponse(output, nil)} {
+		var rpcErr *JSONRPCError
+
+		c.ErrorAs(err, &rpcErr)
+		c.Equal(-32601, rpcErr.Code)
+		c.True(errors.Is(err, ErrJSONRPC))
+	}
+
+	err := output.DecodeResponse(&blockNumberResponse{})
+	c.True(errors.Is(err, ErrChainError))
+
+	output = &Output{RelayOutput: &provider.RelayOutput{Response: `{"error":"rate limited"}`}}
+	err = output.DecodeResponse(&blockNumberResponse{})
+	c.True(errors.Is(err, ErrChainError))
+	c.False(errors.Is(err, ErrJSONRPC))
+
+	var responses []*blockNumberResponse
+
+	output = &Output{RelayOutput: &provider.RelayOutput{Response: `[{"id":1,"result":"0x1"},{"id":2,"result":"0x2"}]`}}
+
+	c.NoError(output.DecodeResponse(&responses))
+	c.Len(responses, 2)
+}
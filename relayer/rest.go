@@ -0,0 +1,59 @@
+package relayer
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+var (
+	// ErrInvalidHTTPMethod error when a REST relay has an unsupported HTTP method
+	ErrInvalidHTTPMethod = errors.New("invalid HTTP method")
+	// ErrInvalidRelayPath error when a REST relay path does not start with "/" or has a fragment
+	ErrInvalidRelayPath = errors.New("invalid relay path")
+)
+
+var restMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// NewRESTInput returns a relay Input for a REST chain with the query percent encoded in the path
+// The request hash of the relay is computed over the assembled path
+// The session and Viper AAT must still be set by the caller
+func NewRESTInput(blockchain, method, path string, query url.Values, body []byte,
+	headers map[string]string) (*Input, error) {
+	method = strings.ToUpper(method)
+	if !restMethods[method] {
+		return nil, ErrInvalidHTTPMethod
+	}
+
+	if !strings.HasPrefix(path, "/") || strings.Contains(path, "#") {
+		return nil, ErrInvalidRelayPath
+	}
+
+	if len(query) != 0 {
+		separator := "?"
+		if strings.Contains(path, "?") {
+			separator = "&"
+		}
+
+		path = path + separator + query.Encode()
+	}
+
+	return &Input{
+		Blockchain: blockchain,
+		Data:       string(body),
+		Headers:    provider.RelayHeaders(headers),
+		Method:     method,
+		Path:       path,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package relayer
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRESTInput(t *testing.T) {
+	c := require.New(t)
+
+	input, err := NewRESTInput("0021", "TRACE", "/blocks", nil, nil, nil)
+	c.Equal(ErrInvalidHTTPMethod, err)
+	c.Empty(input)
+
+	input, err = NewRESTInput("0021", http.MethodGet, "blocks", nil, nil, nil)
+	c.Equal(ErrInvalidRelayPath, err)
+	c.Empty(input)
+
+	input, err = NewRESTInput("0021", http.MethodGet, "/blocks#latest", nil, nil, nil)
+	c.Equal(ErrInvalidRelayPath, err)
+	c.Empty(input)
+
+	input, err = NewRESTInput("0021", "get", "/blocks/latest", nil, nil, nil)
+	c.NoError(err)
+	c.Equal(http.MethodGet, input.Method)
+	c.Equal("/blocks/latest", input.Path)
+	c.Empty(input.Data)
+
+	query := url.Values{}
+	query.Set("owner", "aog & pjog")
+	query.Set("filter", "a=b/c?")
+
+	input, err = NewRESTInput("0021", http.MethodPost, "/accounts", query, []byte(`{"limit":1}`),
+		map[string]string{"Content-Type": "application/json"})
+	c.NoError(err)
+	c.Equal("/accounts?filter=a%3Db%2Fc%3F&owner=aog+%26+pjog", input.Path)
+	c.Equal(`{"limit":1}`, input.Data)
+	c.Equal(provider.RelayHeaders{"Content-Type": "application/json"}, input.Headers)
+
+	input, err = NewRESTInput("0021", http.MethodGet, "/accounts?page=2", url.Values{"limit": []string{"1"}}, nil, nil)
+	c.NoError(err)
+	c.Equal("/accounts?page=2&limit=1", input.Path)
+}
+
+func TestRelayer_RelayREST(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+
+	input, err := NewRESTInput("0021", http.MethodGet, "/blocks", url.Values{"height": []string{"21"}}, nil, nil)
+	c.NoError(err)
+
+	input.ViperAAT = &provider.ViperAAT{}
+	input.Session = newSelectorSession()
+
+	addMockedNodeRelay("https://dummy.com", `{"height":21}`)
+
+	output, err := relayer.Relay(input, nil)
+	c.NoError(err)
+
+	requestHash, err := HashRequest(&RequestHash{
+		Payload: &provider.RelayPayload{Method: http.MethodGet, Path: "/blocks?height=21"},
+		Meta:    &provider.RelayMeta{},
+	})
+	c.NoError(err)
+	c.Equal(requestHash, output.Proof.RequestHash)
+}
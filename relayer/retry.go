@@ -0,0 +1,450 @@
+package relayer
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+const (
+	// DefaultRetryMaxAttempts is the default amount of attempts done by RelayWithRetries
+	DefaultRetryMaxAttempts = 3
+	// DefaultRetryBackoff is the default wait before the first retry of RelayWithRetries
+	DefaultRetryBackoff = 100 * time.Millisecond
+	// DefaultRetryMaxBackoff is the default upper bound of the wait between attempts of RelayWithRetries
+	DefaultRetryMaxBackoff = 2 * time.Second
+)
+
+// ErrAttemptTimeout error when a relay attempt does not finish before the attempt timeout
+var ErrAttemptTimeout = errors.New("relay attempt timed out")
+
+// RetryOptions represents optional arguments for RelayWithRetries
+// MaxAttempts <= 0 uses DefaultRetryMaxAttempts, AttemptTimeout = 0 disables the attempt timeout
+// The wait before retry n is Backoff * 2^(n-1) bounded by MaxBackoff plus a random jitter in [0, Jitter)
+// With FullJitter the wait is random in [0, Backoff * 2^(n-1)] bounded by MaxBackoff instead and Jitter is ignored
+// A Backoff set here takes precedence over the relayer retry delays of WithRetryBaseDelay and WithRetryMaxDelay,
+// when Backoff is 0 and the relayer has a retry base delay, these replace Backoff, MaxBackoff and FullJitter
+// SwitchNodes relays every retry to a session node not tried yet while there is one
+type RetryOptions struct {
+	MaxAttempts    int
+	AttemptTimeout time.Duration
+	Backoff        time.Duration
+	MaxBackoff     time.Duration
+	Jitter         time.Duration
+	FullJitter     bool
+	SwitchNodes    bool
+}
+
+// WithRetryBaseDelay sets the base delay of the full jitter backoff between the attempts of RelayWithRetries
+// The wait before retry n is random in [0, baseDelay * 2^(n-1)] bounded by the retry max delay
+// It only applies to calls whose RetryOptions have no Backoff, a per-call Backoff takes precedence over it
+// and over WithRetryMaxDelay, baseDelay <= 0 disables it and it is disabled by default
+func WithRetryBaseDelay(baseDelay time.Duration) RelayerOption {
+	return func(r *Relayer) {
+		r.retryBaseDelay = baseDelay
+	}
+}
+
+// WithRetryMaxDelay sets the upper bound of the full jitter backoff set by WithRetryBaseDelay,
+// defaults to DefaultRetryMaxBackoff
+func WithRetryMaxDelay(maxDelay time.Duration) RelayerOption {
+	return func(r *Relayer) {
+		r.retryMaxDelay = maxDelay
+	}
+}
+
+// RelayAttempt describes one try of RelayWithRetries, Node is nil when no node could be chosen
+type RelayAttempt struct {
+	Node     *provider.Node
+	Duration time.Duration
+	Err      error
+}
+
+// RetryError represents the thrown error when RelayWithRetries fails after trying some nodes
+// Its message lists the node and error of every attempt and it unwraps to the error of the last attempt
+type RetryError struct {
+	Attempts []*RelayAttempt
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *RetryError) Error() string {
+	messages := make([]string, len(e.Attempts))
+
+	for i, attempt := range e.Attempts {
+		servicerPubKey := ""
+		if attempt.Node != nil {
+			servicerPubKey = attempt.Node.PublicKey
+		}
+
+		messages[i] = fmt.Sprintf("attempt %d with ServicerPubKey: %s: %s", i+1, servicerPubKey, attempt.Err)
+	}
+
+	return fmt.Sprintf("relay failed after %d attempts: %s", len(e.Attempts), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the error of the last attempt so the error can be checked with errors.Is and errors.As
+func (e *RetryError) Unwrap() error {
+	return e.Attempts[len(e.Attempts)-1].Err
+}
+
+// RelayAttemptsError represents the thrown error when RelayWithRetries fails, with the same attempts metadata
+// as a successful Output
+// Err is the RetryError describing every attempt, it unwraps to the error of the last attempt
+type RelayAttemptsError struct {
+	Attempts      int
+	TriedNodes    []string
+	TotalDuration time.Duration
+	Err           error
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *RelayAttemptsError) Error() string {
+	return fmt.Sprintf("%s, total duration: %s", e.Err, e.TotalDuration)
+}
+
+// Unwrap returns the RetryError so the error can be checked with errors.Is and errors.As
+func (e *RelayAttemptsError) Unwrap() error {
+	return e.Err
+}
+
+// getTriedNodes returns the public keys of the nodes of given attempts in their order
+// attempts without node, failing at the node selection, are counted in Attempts but have no tried node
+func getTriedNodes(attempts []*RelayAttempt) []string {
+	triedNodes := []string{}
+
+	for _, attempt := range attempts {
+		if attempt.Node != nil {
+			triedNodes = append(triedNodes, attempt.Node.PublicKey)
+		}
+	}
+
+	return triedNodes
+}
+
+// setAttemptsMetadata sets the attempts metadata of the output of RelayWithRetries
+func setAttemptsMetadata(output *Output, attempts []*RelayAttempt, startTime time.Time) {
+	output.Attempts = len(attempts)
+	output.TriedNodes = getTriedNodes(attempts)
+	output.TotalDuration = time.Since(startTime)
+}
+
+var terminalErrors = []error{
+	ErrNoSigner,
+	ErrNoSession,
+	ErrNoSessionHeader,
+	ErrNoProvider,
+	ErrNoViperAAT,
+	ErrSessionHasNoNodes,
+	ErrNodeNotInSession,
+	ErrServiceURLNotInSession,
+	ErrInvalidEntropyMax,
+	ErrNoEntropyReader,
+	ErrAATSessionMismatch,
+	ErrChainMismatch,
+	ErrPayloadTooLarge,
+	ErrSessionExpired,
+	ErrNoHasher,
+	ErrNoProof,
+	ErrMissingProofSignature,
+	ErrProofRequestHashMismatch,
+	ErrProofBlockchainMismatch,
+	ErrProofAATMismatch,
+	ErrProofSessionMismatch,
+	ErrInvalidProofSignature,
+	ErrBatchSignatureCount,
+	provider.Err4xxOnConnection,
+	provider.ErrCertificatePinMismatch,
+	provider.ErrNilHTTPClient,
+	provider.ErrInvalidMaxAttempts,
+	context.Canceled,
+}
+
+var retryableRelayErrorCodes = map[provider.RelayErrorCode]bool{
+	provider.DuplicateProofError:     true,
+	provider.EvidencedSealedError:    true,
+	provider.HTTPExecutionError:      true,
+	provider.InvalidBlockHeightError: true,
+	provider.OutOfSyncRequestError:   true,
+	provider.OverServiceError:        true,
+}
+
+// IsRetryableError returns if a relay failing with given error can succeed when done again
+// Relay errors are retryable depending on their code, input and configuration errors are terminal
+// and any other error, like network errors or 5xx responses, is retryable
+func IsRetryableError(err error) bool {
+	var relayErr *provider.RelayError
+	if errors.As(err, &relayErr) {
+		return retryableRelayErrorCodes[relayErr.Code]
+	}
+
+	for _, terminalErr := range terminalErrors {
+		if errors.Is(err, terminalErr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func getRetryOptions(options *RetryOptions) RetryOptions {
+	retryOptions := RetryOptions{}
+	if options != nil {
+		retryOptions = *options
+	}
+
+	if retryOptions.MaxAttempts <= 0 {
+		retryOptions.MaxAttempts = DefaultRetryMaxAttempts
+	}
+
+	if retryOptions.Backoff == 0 {
+		retryOptions.Backoff = DefaultRetryBackoff
+	}
+
+	if retryOptions.MaxBackoff == 0 {
+		retryOptions.MaxBackoff = DefaultRetryMaxBackoff
+	}
+
+	return retryOptions
+}
+
+// getRetryDelays returns the retry options with the relayer full jitter backoff when the call sets no backoff
+func (r *Relayer) getRetryDelays(options *RetryOptions) RetryOptions {
+	retryOptions := getRetryOptions(options)
+
+	if r.retryBaseDelay <= 0 || (options != nil && options.Backoff != 0) {
+		return retryOptions
+	}
+
+	retryOptions.Backoff = r.retryBaseDelay
+	retryOptions.MaxBackoff = DefaultRetryMaxBackoff
+	retryOptions.FullJitter = true
+
+	if r.retryMaxDelay > 0 {
+		retryOptions.MaxBackoff = r.retryMaxDelay
+	}
+
+	return retryOptions
+}
+
+func (o RetryOptions) getBackoff(retry int) (time.Duration, error) {
+	backoff := o.Backoff
+
+	for i := 1; i < retry && backoff < o.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > o.MaxBackoff {
+		backoff = o.MaxBackoff
+	}
+
+	if o.FullJitter {
+		fullJitter, err := rand.Int(rand.Reader, big.NewInt(int64(backoff)+1))
+		if err != nil {
+			return 0, err
+		}
+
+		return time.Duration(fullJitter.Int64()), nil
+	}
+
+	if o.Jitter <= 0 {
+		return backoff, nil
+	}
+
+	jitter, err := rand.Int(rand.Reader, big.NewInt(int64(o.Jitter)))
+	if err != nil {
+		return 0, err
+	}
+
+	return backoff + time.Duration(jitter.Int64()), nil
+}
+
+// getUntriedNode returns a random session node not in tried, or a random session node if all were tried
+func getUntriedNode(session *provider.Session, tried map[string]bool) (*provider.Node, error) {
+	untried := []*provider.Node{}
+
+	for _, node := range session.Nodes {
+		if !tried[node.PublicKey] {
+			untried = append(untried, node)
+		}
+	}
+
+	if len(untried) == 0 {
+		return GetRandomSessionNode(session)
+	}
+
+	return GetRandomSessionNode(&provider.Session{Nodes: untried})
+}
+
+func (r *Relayer) getAttemptNode(input *Input, options RetryOptions, tried map[string]bool) (*provider.Node, error) {
+	if len(tried) == 0 || !options.SwitchNodes {
+		return r.getNode(input)
+	}
+
+	return getUntriedNode(input.Session, tried)
+}
+
+type relayResult struct {
+	output *Output
+	err    error
+}
+
+// relayWithTimeout does the relay to given node, returning ErrAttemptTimeout if it does not finish before timeout
+// or the ctx error if ctx is done first
+// the relay keeps running in background after a timeout and its result is discarded
+func (r *Relayer) relayWithTimeout(ctx context.Context, input *Input, node *provider.Node,
+	options *provider.RelayRequestOptions, timeout time.Duration, attempt int) (*Output, error) {
+	if timeout <= 0 {
+		return r.relayToNode(ctx, input, node, nil, options, attempt)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	results := make(chan relayResult, 1)
+
+	go func() {
+		output, err := r.relayToNode(ctx, input, node, nil, options, attempt)
+		results <- relayResult{output: output, err: err}
+	}()
+
+	select {
+	case result := <-results:
+		return result.output, result.err
+	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ctx.Err()
+		}
+
+		return nil, ErrAttemptTimeout
+	}
+}
+
+// RelayWithRetries does relay request with given input until it succeeds, fails with a terminal error
+// or the attempts are exhausted, see IsRetryableError
+// Every try is described in the returned attempts, also when the relay fails with a RelayAttemptsError
+// When every session node failed and the relayer has an altruist for the chain the raw payload is sent to it,
+// see WithAltruist
+func (r *Relayer) RelayWithRetries(input *Input, options *provider.RelayRequestOptions,
+	retryOptions *RetryOptions) (*Output, []*RelayAttempt, error) {
+	return r.RelayWithRetriesContext(context.Background(), input, options, retryOptions)
+}
+
+// RelayWithRetriesContext does RelayWithRetries bounded by ctx, a done ctx aborts the backoff wait
+// and the attempt in flight and its error is returned
+func (r *Relayer) RelayWithRetriesContext(ctx context.Context, input *Input, options *provider.RelayRequestOptions,
+	retryOptions *RetryOptions) (*Output, []*RelayAttempt, error) {
+	defer r.trackRelay()()
+
+	output, attempts, err := r.relayWithRetries(ctx, input, options, retryOptions)
+
+	return output, attempts, r.annotateSessionDrift(input, err)
+}
+
+func (r *Relayer) relayWithRetries(ctx context.Context, input *Input, options *provider.RelayRequestOptions,
+	retryOptions *RetryOptions) (*Output, []*RelayAttempt, error) {
+	startTime := time.Now()
+
+	err := r.validateRelayRequest(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	finalOptions := r.getRetryDelays(retryOptions)
+	attempts := []*RelayAttempt{}
+	tried := map[string]bool{}
+
+	for {
+		output, attempt, err := r.doRelayAttempt(ctx, input, options, finalOptions, tried, len(attempts))
+		attempts = append(attempts, attempt)
+
+		if err == nil {
+			setAttemptsMetadata(output, attempts, startTime)
+
+			return output, attempts, nil
+		}
+
+		if !IsRetryableError(err) || len(attempts) >= finalOptions.MaxAttempts {
+			output, err = r.relayAltruistFallback(ctx, input, tried, newRelayAttemptsError(attempts, startTime))
+			if err == nil {
+				setAttemptsMetadata(output, attempts, startTime)
+			}
+
+			return output, attempts, err
+		}
+
+		err = waitBackoff(ctx, finalOptions, len(attempts))
+		if err != nil {
+			return nil, attempts, err
+		}
+	}
+}
+
+func newRelayAttemptsError(attempts []*RelayAttempt, startTime time.Time) *RelayAttemptsError {
+	return &RelayAttemptsError{
+		Attempts:      len(attempts),
+		TriedNodes:    getTriedNodes(attempts),
+		TotalDuration: time.Since(startTime),
+		Err:           &RetryError{Attempts: attempts},
+	}
+}
+
+// relayAltruistFallback relays to the altruist when every session node failed, otherwise returns relayErr
+func (r *Relayer) relayAltruistFallback(ctx context.Context, input *Input, tried map[string]bool,
+	relayErr error) (*Output, error) {
+	altruistURL, ok := r.getAltruist(input, tried)
+	if !ok {
+		return nil, relayErr
+	}
+
+	output, err := r.relayToAltruist(ctx, altruistURL, input)
+	if err != nil {
+		return nil, &AltruistError{RelayErr: relayErr, Err: err}
+	}
+
+	return output, nil
+}
+
+// waitBackoff waits the backoff before given retry, returns the ctx error if ctx is done first
+func waitBackoff(ctx context.Context, options RetryOptions, retry int) error {
+	backoff, err := options.getBackoff(retry)
+	if err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Relayer) doRelayAttempt(ctx context.Context, input *Input, options *provider.RelayRequestOptions,
+	retryOptions RetryOptions, tried map[string]bool, attemptIndex int) (*Output, *RelayAttempt, error) {
+	node, err := r.getAttemptNode(input, retryOptions, tried)
+	if err != nil {
+		return nil, &RelayAttempt{Err: err}, err
+	}
+
+	tried[node.PublicKey] = true
+	startTime := time.Now()
+
+	output, err := r.relayWithTimeout(ctx, input, node, options, retryOptions.AttemptTimeout, attemptIndex)
+
+	return output, &RelayAttempt{
+		Node:     node,
+		Duration: time.Since(startTime),
+		Err:      err,
+	}, err
+}
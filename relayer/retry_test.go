@@ -0,0 +1,328 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/utils-go/mock-client"
+)
+
+func TestRelayer_RelayWithRetries(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+	input := newConsensusInput()
+	retryOptions := &RetryOptions{MaxAttempts: 3, Backoff: time.Millisecond, Jitter: time.Millisecond, SwitchNodes: true}
+
+	output, attempts, err := relayer.RelayWithRetries(&Input{}, nil, retryOptions)
+	c.Equal(ErrNoSession, err)
+	c.Empty(output)
+	c.Empty(attempts)
+
+	addMockedNodeRelay("https://aog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+	addMockedNodeRelay("https://pjog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+	addMockedNodeRelay("https://ohana.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	output, attempts, err = relayer.RelayWithRetries(input, nil, retryOptions)
+	c.NoError(err)
+	c.Equal(`{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`, output.RelayOutput.Response)
+	c.Len(attempts, 1)
+	c.Equal(output.Node, attempts[0].Node)
+	c.NoError(attempts[0].Err)
+
+	for _, node := range input.Session.Nodes {
+		mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", node.ServiceURL, provider.ClientRelayRoute),
+			http.StatusInternalServerError, "{}")
+	}
+
+	output, attempts, err = relayer.RelayWithRetries(input, nil, retryOptions)
+	c.True(errors.Is(err, provider.Err5xxOnConnection))
+
+	var retryErr *RetryError
+
+	c.ErrorAs(err, &retryErr)
+	c.Equal(attempts, retryErr.Attempts)
+	c.Contains(err.Error(), "attempt 3 with ServicerPubKey")
+	c.Empty(output)
+	c.Len(attempts, 3)
+
+	triedNodes := map[string]bool{}
+	for _, attempt := range attempts {
+		c.True(errors.Is(attempt.Err, provider.Err5xxOnConnection))
+		triedNodes[attempt.Node.PublicKey] = true
+	}
+	c.Len(triedNodes, 3)
+
+	input.Node = input.Session.Nodes[0]
+
+	mock.AddMultipleMockedPlainResponses(http.MethodPost, fmt.Sprintf("%s%s", "https://aog.com", provider.ClientRelayRoute),
+		[]int{http.StatusInternalServerError, http.StatusOK}, []string{"{}", `{"response": "{}", "signature": "abf"}`})
+
+	output, attempts, err = relayer.RelayWithRetries(input, nil, &RetryOptions{Backoff: time.Millisecond})
+	c.NoError(err)
+	c.Equal("{}", output.RelayOutput.Response)
+	c.Len(attempts, 2)
+	c.Equal("AOG", attempts[0].Node.PublicKey)
+	c.Equal("AOG", attempts[1].Node.PublicKey)
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://aog.com", provider.ClientRelayRoute),
+		http.StatusBadRequest, "../provider/samples/client_relay_error.json")
+
+	output, attempts, err = relayer.RelayWithRetries(input, nil, retryOptions)
+	c.True(provider.IsErrorCode(provider.EmptyPayloadDataError, err))
+	c.Empty(output)
+	c.Len(attempts, 1)
+}
+
+func TestRelayer_AttemptsMetadata(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+	input := newConsensusInput()
+	input.Node = input.Session.Nodes[0]
+
+	addMockedNodeRelay("https://aog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	output, err := relayer.Relay(input, nil)
+	c.NoError(err)
+	c.Equal(1, output.Attempts)
+	c.Equal([]string{"AOG"}, output.TriedNodes)
+	c.Positive(output.TotalDuration)
+
+	mock.AddMultipleMockedPlainResponses(http.MethodPost, fmt.Sprintf("%s%s", "https://aog.com", provider.ClientRelayRoute),
+		[]int{http.StatusInternalServerError, http.StatusOK}, []string{"{}", `{"response": "{}", "signature": "abf"}`})
+
+	output, attempts, err := relayer.RelayWithRetries(input, nil, &RetryOptions{Backoff: time.Millisecond})
+	c.NoError(err)
+	c.Len(attempts, 2)
+	c.Equal(2, output.Attempts)
+	c.Equal([]string{"AOG", "AOG"}, output.TriedNodes)
+	c.GreaterOrEqual(output.TotalDuration, attempts[0].Duration+attempts[1].Duration)
+
+	input.Node = nil
+
+	for _, node := range input.Session.Nodes {
+		mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", node.ServiceURL, provider.ClientRelayRoute),
+			http.StatusInternalServerError, "{}")
+	}
+
+	output, attempts, err = relayer.RelayWithRetries(input, nil,
+		&RetryOptions{MaxAttempts: 3, Backoff: time.Millisecond, SwitchNodes: true})
+	c.Empty(output)
+	c.True(errors.Is(err, provider.Err5xxOnConnection))
+
+	var attemptsErr *RelayAttemptsError
+
+	c.ErrorAs(err, &attemptsErr)
+	c.Equal(3, attemptsErr.Attempts)
+	c.Equal([]string{attempts[0].Node.PublicKey, attempts[1].Node.PublicKey, attempts[2].Node.PublicKey},
+		attemptsErr.TriedNodes)
+	c.ElementsMatch([]string{"AOG", "PJOG", "OHANA"}, attemptsErr.TriedNodes)
+	c.Positive(attemptsErr.TotalDuration)
+
+	var retryErr *RetryError
+
+	c.ErrorAs(err, &retryErr)
+	c.Equal(attempts, retryErr.Attempts)
+}
+
+func TestRelayer_RelayWithRetriesAttemptTimeout(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+	input := newConsensusInput()
+
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+
+	httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s%s", "https://aog.com", provider.ClientRelayRoute),
+		func(request *http.Request) (*http.Response, error) {
+			defer wg.Done()
+
+			<-release
+
+			return httpmock.NewStringResponse(http.StatusOK, `{"response": "{}", "signature": "abf"}`), nil
+		})
+
+	input.Node = input.Session.Nodes[0]
+
+	output, attempts, err := relayer.RelayWithRetries(input, nil, &RetryOptions{
+		MaxAttempts:    2,
+		AttemptTimeout: 5 * time.Millisecond,
+		Backoff:        time.Millisecond,
+	})
+	c.True(errors.Is(err, ErrAttemptTimeout))
+	c.Empty(output)
+	c.Len(attempts, 2)
+
+	close(release)
+	wg.Wait()
+}
+
+func TestIsRetryableError(t *testing.T) {
+	c := require.New(t)
+
+	c.True(IsRetryableError(provider.Err5xxOnConnection))
+	c.True(IsRetryableError(ErrAttemptTimeout))
+	c.True(IsRetryableError(&NodeBusyError{Node: &provider.Node{}, Err: ErrNodeBusy}))
+	c.True(IsRetryableError(&provider.RelayError{Code: provider.OverServiceError}))
+	c.False(IsRetryableError(&provider.RelayError{Code: provider.EmptyPayloadDataError}))
+	c.False(IsRetryableError(provider.Err4xxOnConnection))
+	c.False(IsRetryableError(&SessionExpiredError{}))
+	c.False(IsRetryableError(ErrNoSigner))
+
+	for _, err := range []error{ErrNoHasher, ErrNoProof, ErrMissingProofSignature, ErrProofRequestHashMismatch,
+		ErrInvalidProofSignature, ErrBatchSignatureCount, ErrProofBlockchainMismatch, ErrProofAATMismatch,
+		ErrProofSessionMismatch} {
+		c.False(IsRetryableError(fmt.Errorf("relay failed: %w", err)))
+	}
+}
+
+func TestRetryOptions_getBackoff(t *testing.T) {
+	c := require.New(t)
+
+	options := getRetryOptions(&RetryOptions{Backoff: time.Second, MaxBackoff: 5 * time.Second})
+
+	for retry, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		backoff, err := options.getBackoff(retry + 1)
+		c.NoError(err)
+		c.Equal(expected, backoff)
+	}
+
+	options.Jitter = time.Second
+
+	backoff, err := options.getBackoff(1)
+	c.NoError(err)
+	c.GreaterOrEqual(backoff, time.Second)
+	c.Less(backoff, 2*time.Second)
+
+	options.FullJitter = true
+
+	for retry := 1; retry < 100; retry++ {
+		backoff, err = options.getBackoff(retry)
+		c.NoError(err)
+		c.GreaterOrEqual(backoff, time.Duration(0))
+		c.LessOrEqual(backoff, 5*time.Second)
+	}
+}
+
+func TestRelayer_RelayWithRetriesResponseValidator(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+	input := newConsensusInput()
+	input.Node = input.Session.Nodes[0]
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://aog.com", provider.ClientRelayRoute), http.StatusOK,
+		`{"response": "{\"id\":1,\"jsonrpc\":\"2.0\",\"error\":{\"code\":-32000}}", "signature": "abf"}`)
+	addMockedNodeRelay("https://pjog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+	addMockedNodeRelay("https://ohana.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	options := &provider.RelayRequestOptions{
+		ResponseValidator: func(response []byte) error {
+			if !strings.Contains(string(response), `"result"`) {
+				return errors.New("no result")
+			}
+
+			return nil
+		},
+	}
+
+	output, attempts, err := relayer.RelayWithRetries(input, options, &RetryOptions{Backoff: time.Millisecond, SwitchNodes: true})
+	c.NoError(err)
+	c.Equal(`{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`, output.RelayOutput.Response)
+	c.Len(attempts, 2)
+	c.Equal("AOG", attempts[0].Node.PublicKey)
+	c.True(errors.Is(attempts[0].Err, provider.ErrInvalidRelayResponse))
+	c.True(IsRetryableError(attempts[0].Err))
+	c.NotEqual("AOG", attempts[1].Node.PublicKey)
+}
+
+func TestRelayer_RelayWithRetriesFullJitter(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, &providerMock{}, WithRetryBaseDelay(5*time.Millisecond),
+		WithRetryMaxDelay(10*time.Millisecond))
+
+	options := relayer.getRetryDelays(&RetryOptions{MaxAttempts: 5})
+	c.Equal(5*time.Millisecond, options.Backoff)
+	c.Equal(10*time.Millisecond, options.MaxBackoff)
+	c.True(options.FullJitter)
+
+	for retry := 1; retry < 100; retry++ {
+		backoff, err := options.getBackoff(retry)
+		c.NoError(err)
+		c.GreaterOrEqual(backoff, time.Duration(0))
+		c.LessOrEqual(backoff, 10*time.Millisecond)
+	}
+
+	options = relayer.getRetryDelays(&RetryOptions{Backoff: time.Millisecond})
+	c.Equal(time.Millisecond, options.Backoff)
+	c.Equal(DefaultRetryMaxBackoff, options.MaxBackoff)
+	c.False(options.FullJitter)
+
+	input := newBatchInputs(1)[0]
+	input.Data = "fail"
+
+	startTime := time.Now()
+
+	output, attempts, err := relayer.RelayWithRetries(input, nil, &RetryOptions{MaxAttempts: 5})
+	c.True(errors.Is(err, provider.Err5xxOnConnection))
+	c.Empty(output)
+	c.Len(attempts, 5)
+	c.Less(time.Since(startTime), 4*10*time.Millisecond+time.Second)
+
+	relayer = NewRelayer(signer, &providerMock{}, WithRetryBaseDelay(time.Minute))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	startTime = time.Now()
+
+	output, attempts, err = relayer.RelayWithRetriesContext(ctx, input, nil, &RetryOptions{MaxAttempts: 5})
+	c.Equal(context.DeadlineExceeded, err)
+	c.Empty(output)
+	c.NotEmpty(attempts)
+	c.Less(time.Since(startTime), time.Second)
+}
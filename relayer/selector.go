@@ -0,0 +1,312 @@
+package relayer
+
+import (
+	"container/list"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	mathrand "math/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// NodeSelector interface representing the strategy to choose the node to relay to from a session
+// The chosen node must be part of the session, the relayer rejects it otherwise
+type NodeSelector interface {
+	Select(session *provider.Session, input *Input) (*provider.Node, error)
+}
+
+// RandomSelector chooses a random session node, it is the default NodeSelector
+type RandomSelector struct{}
+
+// Select returns a random node from given session
+func (s *RandomSelector) Select(session *provider.Session, input *Input) (*provider.Node, error) {
+	return GetRandomSessionNode(session)
+}
+
+// RoundRobinSelector chooses the session nodes one after the other
+type RoundRobinSelector struct {
+	next uint64
+}
+
+// NewRoundRobinSelector returns instance of RoundRobinSelector
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{}
+}
+
+// Select returns the next node of given session
+func (s *RoundRobinSelector) Select(session *provider.Session, input *Input) (*provider.Node, error) {
+	if len(session.Nodes) == 0 {
+		return nil, ErrSessionHasNoNodes
+	}
+
+	index := (atomic.AddUint64(&s.next, 1) - 1) % uint64(len(session.Nodes))
+
+	return session.Nodes[index], nil
+}
+
+// DefaultSelectorMaxNodes is the amount of nodes remembered by LeastRecentlyUsedSelector and StickySessionSelector
+const DefaultSelectorMaxNodes = 1000
+
+type nodeValue struct {
+	publicKey string
+	value     int64
+}
+
+// nodeValues holds a value per node public key, only the most recently updated maxNodes nodes are kept
+// so memory stays bounded across many sessions, it is not safe for concurrent use
+type nodeValues struct {
+	maxNodes int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+func newNodeValues(maxNodes int) *nodeValues {
+	return &nodeValues{
+		maxNodes: maxNodes,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// get returns the value of the node, 0 for nodes not kept
+func (v *nodeValues) get(publicKey string) int64 {
+	element, ok := v.entries[publicKey]
+	if !ok {
+		return 0
+	}
+
+	return element.Value.(*nodeValue).value
+}
+
+func (v *nodeValues) set(publicKey string, value int64) {
+	element, ok := v.entries[publicKey]
+	if ok {
+		element.Value.(*nodeValue).value = value
+		v.order.MoveToFront(element)
+
+		return
+	}
+
+	v.entries[publicKey] = v.order.PushFront(&nodeValue{publicKey: publicKey, value: value})
+
+	for v.order.Len() > v.maxNodes {
+		oldest := v.order.Back()
+		v.order.Remove(oldest)
+		delete(v.entries, oldest.Value.(*nodeValue).publicKey)
+	}
+}
+
+func (v *nodeValues) delete(publicKey string) {
+	element, ok := v.entries[publicKey]
+	if !ok {
+		return
+	}
+
+	v.order.Remove(element)
+	delete(v.entries, publicKey)
+}
+
+// LeastRecentlyUsedSelector chooses the session node that was selected the longest time ago
+// Nodes never selected before are preferred in session order
+// Only the DefaultSelectorMaxNodes most recently selected nodes are remembered, older ones count as never selected
+type LeastRecentlyUsedSelector struct {
+	mutex    sync.Mutex
+	counter  int64
+	lastUsed *nodeValues
+}
+
+// NewLeastRecentlyUsedSelector returns instance of LeastRecentlyUsedSelector
+func NewLeastRecentlyUsedSelector() *LeastRecentlyUsedSelector {
+	return &LeastRecentlyUsedSelector{
+		lastUsed: newNodeValues(DefaultSelectorMaxNodes),
+	}
+}
+
+// Select returns the least recently used node of given session
+func (s *LeastRecentlyUsedSelector) Select(session *provider.Session, input *Input) (*provider.Node, error) {
+	if len(session.Nodes) == 0 {
+		return nil, ErrSessionHasNoNodes
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	selected := session.Nodes[0]
+
+	for _, node := range session.Nodes[1:] {
+		if s.lastUsed.get(node.PublicKey) < s.lastUsed.get(selected.PublicKey) {
+			selected = node
+		}
+	}
+
+	s.counter++
+	s.lastUsed.set(selected.PublicKey, s.counter)
+
+	return selected, nil
+}
+
+// DefaultStickyMaxFailures is the default number of failures before StickySessionSelector fails over a node
+const DefaultStickyMaxFailures = 3
+
+// StickySessionSelector always chooses the same node for the same session
+// The node is derived from the app public key, chain, session height and sticky key of the session,
+// the selector moves to the next session node only after the node is reported failing maxFailures times in a row
+// Only the failures of the DefaultSelectorMaxNodes most recently failing nodes are remembered
+type StickySessionSelector struct {
+	stickyKey   string
+	maxFailures int
+	mutex       sync.Mutex
+	failures    *nodeValues
+}
+
+// NewStickySessionSelector returns instance of StickySessionSelector
+// stickyKey is optional and allows different callers to stick to different nodes of the same session
+// maxFailures <= 0 uses DefaultStickyMaxFailures
+func NewStickySessionSelector(stickyKey string, maxFailures int) *StickySessionSelector {
+	if maxFailures <= 0 {
+		maxFailures = DefaultStickyMaxFailures
+	}
+
+	return &StickySessionSelector{
+		stickyKey:   stickyKey,
+		maxFailures: maxFailures,
+		failures:    newNodeValues(DefaultSelectorMaxNodes),
+	}
+}
+
+func (s *StickySessionSelector) getSessionIndex(session *provider.Session) uint64 {
+	header := session.Header
+	if header == nil {
+		header = &provider.SessionHeader{}
+	}
+
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%d/%s", header.AppPublicKey, header.Chain, header.SessionHeight, s.stickyKey)))
+
+	return binary.BigEndian.Uint64(hash[:8]) % uint64(len(session.Nodes))
+}
+
+// Select returns the sticky node of given session, or the next healthy node if it failed too many times
+// If every node failed too many times the sticky node is returned
+func (s *StickySessionSelector) Select(session *provider.Session, input *Input) (*provider.Node, error) {
+	if len(session.Nodes) == 0 {
+		return nil, ErrSessionHasNoNodes
+	}
+
+	index := s.getSessionIndex(session)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := uint64(0); i < uint64(len(session.Nodes)); i++ {
+		node := session.Nodes[(index+i)%uint64(len(session.Nodes))]
+
+		if s.failures.get(node.PublicKey) < int64(s.maxFailures) {
+			return node, nil
+		}
+	}
+
+	return session.Nodes[index], nil
+}
+
+// MarkFailure reports a failed relay to given node
+func (s *StickySessionSelector) MarkFailure(node *provider.Node) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.failures.set(node.PublicKey, s.failures.get(node.PublicKey)+1)
+}
+
+// MarkSuccess reports a successful relay to given node, clearing its failures
+func (s *StickySessionSelector) MarkSuccess(node *provider.Node) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.failures.delete(node.PublicKey)
+}
+
+// StakeWeightedSelector chooses a session node with probability proportional to its staked tokens
+// Node stake is read from the Tokens field filled by dispatch and GetNode, session nodes without
+// valid stake weight the mean stake of the others and nodes are chosen uniformly when none has stake
+type StakeWeightedSelector struct {
+	mutex  sync.Mutex
+	random io.Reader
+}
+
+// NewStakeWeightedSelector returns instance of StakeWeightedSelector using crypto/rand
+func NewStakeWeightedSelector() *StakeWeightedSelector {
+	return &StakeWeightedSelector{
+		random: rand.Reader,
+	}
+}
+
+// NewSeededStakeWeightedSelector returns instance of StakeWeightedSelector with a deterministic random source
+// Selectors with the same seed choose the same nodes in the same order, it is meant for tests
+func NewSeededStakeWeightedSelector(seed int64) *StakeWeightedSelector {
+	return &StakeWeightedSelector{
+		random: mathrand.New(mathrand.NewSource(seed)), // #nosec G404
+	}
+}
+
+// getNodeStakes returns the selection weight of every node and their sum
+// nodes without stake weight the mean stake of the nodes with stake, or 1 when no node has stake
+func getNodeStakes(nodes []*provider.Node) ([]*big.Int, *big.Int) {
+	stakes := make([]*big.Int, len(nodes))
+	total := big.NewInt(0)
+	known := int64(0)
+
+	for i, node := range nodes {
+		stake, ok := node.StakedTokens()
+		if ok {
+			stakes[i] = stake
+			total.Add(total, stake)
+			known++
+		}
+	}
+
+	mean := big.NewInt(1)
+	if known > 0 {
+		mean.Div(total, big.NewInt(known))
+	}
+
+	for i := range stakes {
+		if stakes[i] == nil {
+			stakes[i] = mean
+			total.Add(total, mean)
+		}
+	}
+
+	return stakes, total
+}
+
+// Select returns a node of given session chosen proportionally to its stake
+func (s *StakeWeightedSelector) Select(session *provider.Session, input *Input) (*provider.Node, error) {
+	if len(session.Nodes) == 0 {
+		return nil, ErrSessionHasNoNodes
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stakes, total := getNodeStakes(session.Nodes)
+
+	target, err := rand.Int(s.random, total)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, stake := range stakes {
+		if target.Cmp(stake) < 0 {
+			return session.Nodes[i], nil
+		}
+
+		target.Sub(target, stake)
+	}
+
+	return session.Nodes[len(session.Nodes)-1], nil
+}
@@ -0,0 +1,293 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/utils-go/mock-client"
+)
+
+type nodeSelectorMock struct {
+	node *provider.Node
+	err  error
+}
+
+func (s *nodeSelectorMock) Select(session *provider.Session, input *Input) (*provider.Node, error) {
+	return s.node, s.err
+}
+
+func newSelectorSession() *provider.Session {
+	return &provider.Session{
+		Header: &provider.SessionHeader{},
+		Nodes: []*provider.Node{
+			{PublicKey: "AOG", ServiceURL: "https://dummy.com"},
+			{PublicKey: "PJOG", ServiceURL: "https://dummy.com"},
+			{PublicKey: "OHANA", ServiceURL: "https://dummy.com"},
+		},
+	}
+}
+
+func TestRelayer_WithNodeSelector(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	selector := &nodeSelectorMock{node: &provider.Node{PublicKey: "FIU"}}
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}), WithNodeSelector(selector))
+	input := &Input{ViperAAT: &provider.ViperAAT{}, Session: newSelectorSession()}
+
+	relay, err := relayer.Relay(input, nil)
+	c.Equal(ErrNodeNotInSession, err)
+	c.Empty(relay)
+
+	selector.node = nil
+	selector.err = errors.New("no node")
+
+	relay, err = relayer.Relay(input, nil)
+	c.Equal(selector.err, err)
+	c.Empty(relay)
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", provider.ClientRelayRoute),
+		http.StatusOK, "../provider/samples/client_relay.json")
+
+	selector.node = input.Session.Nodes[1]
+	selector.err = nil
+
+	relay, err = relayer.Relay(input, nil)
+	c.NoError(err)
+	c.Equal("PJOG", relay.Node.PublicKey)
+}
+
+func TestRoundRobinSelector_Select(t *testing.T) {
+	c := require.New(t)
+
+	selector := NewRoundRobinSelector()
+	session := newSelectorSession()
+
+	for i := 0; i < 6; i++ {
+		node, err := selector.Select(session, nil)
+		c.NoError(err)
+		c.Equal(session.Nodes[i%3], node)
+	}
+
+	node, err := selector.Select(&provider.Session{}, nil)
+	c.Equal(ErrSessionHasNoNodes, err)
+	c.Empty(node)
+}
+
+func TestLeastRecentlyUsedSelector_Select(t *testing.T) {
+	c := require.New(t)
+
+	selector := NewLeastRecentlyUsedSelector()
+	session := newSelectorSession()
+
+	for i := 0; i < 3; i++ {
+		node, err := selector.Select(session, nil)
+		c.NoError(err)
+		c.Equal(session.Nodes[i], node)
+	}
+
+	session.Nodes[0], session.Nodes[2] = session.Nodes[2], session.Nodes[0]
+
+	node, err := selector.Select(session, nil)
+	c.NoError(err)
+	c.Equal("AOG", node.PublicKey)
+
+	node, err = selector.Select(session, nil)
+	c.NoError(err)
+	c.Equal("PJOG", node.PublicKey)
+
+	node, err = selector.Select(&provider.Session{}, nil)
+	c.Equal(ErrSessionHasNoNodes, err)
+	c.Empty(node)
+
+	for i := 0; i < DefaultSelectorMaxNodes+10; i++ {
+		_, err = selector.Select(&provider.Session{Nodes: []*provider.Node{{PublicKey: fmt.Sprintf("node-%d", i)}}}, nil)
+		c.NoError(err)
+	}
+
+	c.Len(selector.lastUsed.entries, DefaultSelectorMaxNodes)
+	c.Zero(selector.lastUsed.get("AOG"))
+	c.NotZero(selector.lastUsed.get(fmt.Sprintf("node-%d", DefaultSelectorMaxNodes+9)))
+}
+
+func TestStickySessionSelector_Select(t *testing.T) {
+	c := require.New(t)
+
+	selector := NewStickySessionSelector("", 2)
+	session := newSelectorSession()
+	session.Header = &provider.SessionHeader{AppPublicKey: "ABCD", Chain: "0021", SessionHeight: 21}
+
+	sticky, err := selector.Select(session, nil)
+	c.NoError(err)
+
+	for i := 0; i < 5; i++ {
+		node, err := selector.Select(session, nil)
+		c.NoError(err)
+		c.Equal(sticky, node)
+	}
+
+	sameSession := newSelectorSession()
+	sameSession.Header = &provider.SessionHeader{AppPublicKey: "ABCD", Chain: "0021", SessionHeight: 21}
+
+	node, err := NewStickySessionSelector("", 2).Select(sameSession, nil)
+	c.NoError(err)
+	c.Equal(sticky.PublicKey, node.PublicKey)
+
+	selector.MarkFailure(sticky)
+
+	node, err = selector.Select(session, nil)
+	c.NoError(err)
+	c.Equal(sticky, node)
+
+	selector.MarkFailure(sticky)
+
+	failover, err := selector.Select(session, nil)
+	c.NoError(err)
+	c.NotEqual(sticky, failover)
+
+	node, err = selector.Select(session, nil)
+	c.NoError(err)
+	c.Equal(failover, node)
+
+	selector.MarkSuccess(sticky)
+
+	node, err = selector.Select(session, nil)
+	c.NoError(err)
+	c.Equal(sticky, node)
+
+	for _, node := range session.Nodes {
+		selector.MarkFailure(node)
+		selector.MarkFailure(node)
+	}
+
+	node, err = selector.Select(session, nil)
+	c.NoError(err)
+	c.Equal(sticky, node)
+
+	node, err = selector.Select(&provider.Session{}, nil)
+	c.Equal(ErrSessionHasNoNodes, err)
+	c.Empty(node)
+
+	for i := 0; i < DefaultSelectorMaxNodes+10; i++ {
+		selector.MarkFailure(&provider.Node{PublicKey: fmt.Sprintf("node-%d", i)})
+	}
+
+	c.Len(selector.failures.entries, DefaultSelectorMaxNodes)
+}
+
+func TestStakeWeightedSelector_Select(t *testing.T) {
+	c := require.New(t)
+
+	session := newSelectorSession()
+	session.Nodes[0].Tokens = "1000000000000000000"
+	session.Nodes[1].Tokens = "1"
+	session.Nodes[2].Tokens = "1"
+
+	selector := NewStakeWeightedSelector()
+
+	for i := 0; i < 10; i++ {
+		node, err := selector.Select(session, nil)
+		c.NoError(err)
+		c.Equal("AOG", node.PublicKey)
+	}
+
+	first := NewSeededStakeWeightedSelector(21)
+	second := NewSeededStakeWeightedSelector(21)
+
+	session.Nodes[0].Tokens = "10"
+	session.Nodes[1].Tokens = "20"
+	session.Nodes[2].Tokens = "70"
+
+	selected := map[string]int{}
+
+	for i := 0; i < 1000; i++ {
+		node, err := first.Select(session, nil)
+		c.NoError(err)
+
+		sameNode, err := second.Select(session, nil)
+		c.NoError(err)
+		c.Equal(node, sameNode)
+
+		selected[node.PublicKey]++
+	}
+
+	c.Less(selected["AOG"], selected["PJOG"])
+	c.Less(selected["PJOG"], selected["OHANA"])
+
+	session.Nodes[1].Tokens = ""
+	selected = map[string]int{}
+
+	for i := 0; i < 300; i++ {
+		node, err := first.Select(session, nil)
+		c.NoError(err)
+
+		selected[node.PublicKey]++
+	}
+
+	c.Len(selected, 3)
+
+	_, err := selector.Select(&provider.Session{}, nil)
+	c.Equal(ErrSessionHasNoNodes, err)
+}
+
+func TestStakeWeightedSelector_Distribution(t *testing.T) {
+	c := require.New(t)
+
+	session := newSelectorSession()
+	session.Nodes = append(session.Nodes, &provider.Node{PublicKey: "FIU", ServiceURL: "https://dummy.com"})
+	session.Nodes[0].Tokens = "15000000000"
+	session.Nodes[1].Tokens = "150000000000"
+	session.Nodes[2].Tokens = "45000000000"
+
+	// FIU has no stake so it weights the mean stake of the others, 70000000000
+	expected := map[string]float64{
+		"AOG":   15.0 / 280,
+		"PJOG":  150.0 / 280,
+		"OHANA": 45.0 / 280,
+		"FIU":   70.0 / 280,
+	}
+
+	selector := NewSeededStakeWeightedSelector(21)
+	selected := map[string]int{}
+	draws := 50000
+
+	for i := 0; i < draws; i++ {
+		node, err := selector.Select(session, nil)
+		c.NoError(err)
+
+		selected[node.PublicKey]++
+	}
+
+	for publicKey, weight := range expected {
+		c.InDelta(weight, float64(selected[publicKey])/float64(draws), 0.01, publicKey)
+	}
+
+	for _, node := range session.Nodes {
+		node.Tokens = ""
+	}
+
+	selected = map[string]int{}
+
+	for i := 0; i < draws; i++ {
+		node, err := selector.Select(session, nil)
+		c.NoError(err)
+
+		selected[node.PublicKey]++
+	}
+
+	for _, node := range session.Nodes {
+		c.InDelta(0.25, float64(selected[node.PublicKey])/float64(draws), 0.01, node.PublicKey)
+	}
+}
@@ -0,0 +1,114 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// SessionProvider interface representing the source of fresh sessions used when a session rolls over
+type SessionProvider interface {
+	GetSession(appPubKey, chain string) (*provider.Session, error)
+}
+
+// WithSessionProvider refreshes the session of a relay that fails with a session error and retries it once
+// The refreshed session is returned in Output.RefreshedSession so callers can cache it, see IsSessionError
+func WithSessionProvider(sessionProvider SessionProvider) RelayerOption {
+	return func(r *Relayer) {
+		r.sessionProvider = sessionProvider
+	}
+}
+
+// IsSessionError returns if given error means the session of the relay is no longer valid
+// It is the case of the servicer invalid session, block height and out of sync errors and of SessionExpiredError
+func IsSessionError(err error) bool {
+	return errors.Is(err, ErrSessionExpired) ||
+		provider.IsErrorCode(provider.InvalidSessionError, err) ||
+		provider.IsErrorCode(provider.InvalidBlockHeightError, err) ||
+		provider.IsErrorCode(provider.OutOfSyncRequestError, err)
+}
+
+// refreshSession returns a copy of given input with a fresh session from the session provider
+// the input node is dropped when it is not part of the fresh session so one is selected again
+func (r *Relayer) refreshSession(input *Input) (*Input, error) {
+	session, err := r.sessionProvider.GetSession(input.ViperAAT.AppPubKey, input.Blockchain)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshedInput := *input
+	refreshedInput.Session = session
+
+	if session != nil && refreshedInput.Node != nil && !IsNodeInSession(session, refreshedInput.Node) {
+		refreshedInput.Node = nil
+	}
+
+	return &refreshedInput, nil
+}
+
+// relayWithSessionRefresh does the relay sending relay when it is not nil, the relay with a refreshed session
+// is always built again
+func (r *Relayer) relayWithSessionRefresh(ctx context.Context, input *Input, relay *provider.RelayInput,
+	options *provider.RelayRequestOptions) (*Output, error) {
+	output, relayErr := r.relayWithSession(ctx, input, relay, options)
+	if relayErr == nil || r.sessionProvider == nil || input.ViperAAT == nil || !IsSessionError(relayErr) {
+		return output, relayErr
+	}
+
+	refreshedInput, err := r.refreshSession(input)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err = r.relayWithSession(ctx, refreshedInput, nil, options)
+	if err != nil {
+		return nil, err
+	}
+
+	output.RefreshedSession = refreshedInput.Session
+
+	var nodeErr *NodeRelayError
+	if errors.As(relayErr, &nodeErr) {
+		output.Attempts++
+		output.TriedNodes = append([]string{nodeErr.Node.PublicKey}, output.TriedNodes...)
+	}
+
+	return output, nil
+}
+
+func (r *Relayer) relayWithSession(ctx context.Context, input *Input, relay *provider.RelayInput,
+	options *provider.RelayRequestOptions) (*Output, error) {
+	startTime := time.Now()
+
+	err := r.validateRelayRequest(input)
+	if err != nil {
+		return nil, err
+	}
+
+	validationDuration := time.Since(startTime)
+
+	node, err := r.getNode(input)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeSelectionDuration := time.Since(startTime) - validationDuration
+
+	output, err := r.relayToNode(ctx, input, node, relay, options, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	output.Attempts = 1
+	output.TriedNodes = []string{node.PublicKey}
+
+	if output.Trace != nil {
+		output.Trace.ValidationDuration = validationDuration
+		output.Trace.NodeSelectionDuration = nodeSelectionDuration
+		output.Trace.TotalDuration = time.Since(startTime)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,118 @@
+package relayer
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// SessionCache interface representing a store of dispatched sessions
+// Implementations must be safe for concurrent use
+type SessionCache interface {
+	Get(key string) (*provider.Session, bool)
+	Set(key string, session *provider.Session, ttl time.Duration)
+}
+
+// SessionCacheKey returns the key of the session of given app and chain
+func SessionCacheKey(appPubKey, chain string) string {
+	return appPubKey + "/" + chain
+}
+
+type sessionCacheEntry struct {
+	key        string
+	session    *provider.Session
+	insertedAt time.Time
+	ttl        time.Duration
+}
+
+func (e *sessionCacheEntry) isExpired(now time.Time) bool {
+	return e.ttl > 0 && now.Sub(e.insertedAt) >= e.ttl
+}
+
+// LRUSessionCache is an in memory SessionCache that evicts the least recently used session when full
+// Sessions are also evicted once their TTL passed, a TTL <= 0 never expires
+type LRUSessionCache struct {
+	mutex      sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+	now        func() time.Time
+}
+
+// NewLRUSessionCache returns instance of LRUSessionCache keeping at most maxEntries sessions
+// maxEntries <= 0 keeps at most one session
+func NewLRUSessionCache(maxEntries int) *LRUSessionCache {
+	if maxEntries <= 0 {
+		maxEntries = 1
+	}
+
+	return &LRUSessionCache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+// Get returns the session cached with given key and if it is cached and not expired
+func (c *LRUSessionCache) Get(key string) (*provider.Session, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*sessionCacheEntry)
+	if entry.isExpired(c.now()) {
+		c.remove(element)
+
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return entry.session, true
+}
+
+// Set caches given session with given key for ttl, evicting the least recently used session when full
+func (c *LRUSessionCache) Set(key string, session *provider.Session, ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry := &sessionCacheEntry{
+		key:        key,
+		session:    session,
+		insertedAt: c.now(),
+		ttl:        ttl,
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	if c.order.Len() >= c.maxEntries {
+		c.remove(c.order.Back())
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+}
+
+// Len returns the amount of cached sessions, including expired ones not evicted yet
+func (c *LRUSessionCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRUSessionCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*sessionCacheEntry).key)
+}
@@ -0,0 +1,57 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+
+	"github.com/stretchr/testify/require"
+)
+
+var _ SessionCache = &LRUSessionCache{}
+
+func TestLRUSessionCache(t *testing.T) {
+	c := require.New(t)
+
+	now := time.Unix(21, 0)
+
+	cache := NewLRUSessionCache(2)
+	cache.now = func() time.Time {
+		return now
+	}
+
+	firstSession := &provider.Session{Header: &provider.SessionHeader{SessionHeight: 1}}
+	secondSession := &provider.Session{Header: &provider.SessionHeader{SessionHeight: 2}}
+	thirdSession := &provider.Session{Header: &provider.SessionHeader{SessionHeight: 3}}
+
+	cache.Set(SessionCacheKey("ABCD", "0021"), firstSession, 0)
+	cache.Set(SessionCacheKey("ABCD", "0022"), secondSession, time.Minute)
+
+	session, ok := cache.Get(SessionCacheKey("ABCD", "0021"))
+	c.True(ok)
+	c.Equal(firstSession, session)
+
+	cache.Set(SessionCacheKey("ABCD", "0023"), thirdSession, time.Minute)
+	c.Equal(2, cache.Len())
+
+	_, ok = cache.Get(SessionCacheKey("ABCD", "0022"))
+	c.False(ok)
+
+	now = now.Add(time.Minute)
+
+	_, ok = cache.Get(SessionCacheKey("ABCD", "0023"))
+	c.False(ok)
+	c.Equal(1, cache.Len())
+
+	session, ok = cache.Get(SessionCacheKey("ABCD", "0021"))
+	c.True(ok)
+	c.Equal(firstSession, session)
+
+	cache.Set(SessionCacheKey("ABCD", "0021"), secondSession, time.Minute)
+
+	session, ok = cache.Get(SessionCacheKey("ABCD", "0021"))
+	c.True(ok)
+	c.Equal(secondSession, session)
+	c.Equal(1, cache.Len())
+}
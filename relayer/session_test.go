@@ -0,0 +1,97 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/utils-go/mock-client"
+)
+
+type sessionProviderMock struct {
+	session *provider.Session
+	err     error
+	calls   int
+}
+
+func (p *sessionProviderMock) GetSession(appPubKey, chain string) (*provider.Session, error) {
+	p.calls++
+
+	return p.session, p.err
+}
+
+func TestRelayer_WithSessionProvider(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	freshSession := &provider.Session{
+		Header: &provider.SessionHeader{SessionHeight: 25},
+		Nodes:  []*provider.Node{{PublicKey: "FRESH", ServiceURL: "https://fresh.com"}},
+	}
+	sessionProvider := &sessionProviderMock{session: freshSession}
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}),
+		WithSessionProvider(sessionProvider))
+
+	input := newConsensusInput()
+	input.Node = input.Session.Nodes[0]
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://aog.com", provider.ClientRelayRoute),
+		http.StatusBadRequest, `{"error": {"code": 14, "codespace": "vipercore", "message": "invalid session"}}`)
+	addMockedNodeRelay("https://fresh.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	output, err := relayer.Relay(input, nil)
+	c.NoError(err)
+	c.Equal(freshSession, output.RefreshedSession)
+	c.Equal("FRESH", output.Node.PublicKey)
+	c.Equal(25, output.Proof.SessionBlockHeight)
+	c.Equal(1, sessionProvider.calls)
+	c.Equal(1, httpmock.GetCallCountInfo()[fmt.Sprintf("POST %s%s", "https://aog.com", provider.ClientRelayRoute)])
+
+	input = newConsensusInput()
+	input.CurrentHeight = 26
+
+	output, err = relayer.Relay(input, nil)
+	c.NoError(err)
+	c.Equal(freshSession, output.RefreshedSession)
+	c.Equal(2, sessionProvider.calls)
+
+	output, err = relayer.Relay(&Input{Data: input.Data, ViperAAT: &provider.ViperAAT{}, Session: freshSession}, nil)
+	c.NoError(err)
+	c.Empty(output.RefreshedSession)
+	c.Equal(2, sessionProvider.calls)
+
+	sessionProvider.err = errors.New("dispatch failed")
+
+	output, err = relayer.Relay(input, nil)
+	c.Equal(sessionProvider.err, err)
+	c.Empty(output)
+
+	relayer = NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+
+	output, err = relayer.Relay(input, nil)
+	c.True(errors.Is(err, ErrSessionExpired))
+	c.Empty(output)
+}
+
+func TestIsSessionError(t *testing.T) {
+	c := require.New(t)
+
+	c.True(IsSessionError(&SessionExpiredError{}))
+	c.True(IsSessionError(&provider.RelayError{Code: provider.InvalidSessionError}))
+	c.True(IsSessionError(&provider.RelayError{Code: provider.InvalidBlockHeightError}))
+	c.True(IsSessionError(&provider.RelayError{Code: provider.OutOfSyncRequestError}))
+	c.False(IsSessionError(&provider.RelayError{Code: provider.EmptyPayloadDataError}))
+	c.False(IsSessionError(provider.Err5xxOnConnection))
+}
@@ -0,0 +1,142 @@
+package relayer
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// RelayStats represents a snapshot of the relays done by a Relayer
+// Every relay to a node counts as an attempt, including each try of RelayWithRetries and RelayWithConsensus
+type RelayStats struct {
+	TotalAttempts    int64
+	TotalSuccesses   int64
+	TotalFailures    int64
+	AverageLatencyMs float64
+	NodeStats        map[string]NodeStat
+}
+
+// NodeStat represents the relay statistics of a single node
+type NodeStat struct {
+	Attempts     int64
+	Successes    int64
+	Failures     int64
+	AvgLatencyMs float64
+}
+
+// relayCounters holds atomic counters, it is allocated on its own so 64 bit fields stay aligned on 32 bit platforms
+type relayCounters struct {
+	attempts  int64
+	successes int64
+	failures  int64
+	latency   int64
+}
+
+func (c *relayCounters) record(err error, duration time.Duration) {
+	atomic.AddInt64(&c.attempts, 1)
+	atomic.AddInt64(&c.latency, int64(duration))
+
+	if err != nil {
+		atomic.AddInt64(&c.failures, 1)
+	} else {
+		atomic.AddInt64(&c.successes, 1)
+	}
+}
+
+func (c *relayCounters) reset() {
+	atomic.StoreInt64(&c.attempts, 0)
+	atomic.StoreInt64(&c.successes, 0)
+	atomic.StoreInt64(&c.failures, 0)
+	atomic.StoreInt64(&c.latency, 0)
+}
+
+func (c *relayCounters) averageLatencyMs(attempts int64) float64 {
+	if attempts == 0 {
+		return 0
+	}
+
+	return float64(atomic.LoadInt64(&c.latency)) / float64(attempts) / float64(time.Millisecond)
+}
+
+type relayStats struct {
+	total *relayCounters
+
+	mutex sync.RWMutex
+	nodes map[string]*relayCounters
+}
+
+func newRelayStats() *relayStats {
+	return &relayStats{
+		total: &relayCounters{},
+		nodes: map[string]*relayCounters{},
+	}
+}
+
+func (s *relayStats) getNodeCounters(publicKey string) *relayCounters {
+	s.mutex.RLock()
+	counters, ok := s.nodes[publicKey]
+	s.mutex.RUnlock()
+
+	if ok {
+		return counters
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	counters, ok = s.nodes[publicKey]
+	if !ok {
+		counters = &relayCounters{}
+		s.nodes[publicKey] = counters
+	}
+
+	return counters
+}
+
+func (s *relayStats) record(node *provider.Node, err error, duration time.Duration) {
+	s.total.record(err, duration)
+	s.getNodeCounters(node.PublicKey).record(err, duration)
+}
+
+// Stats returns a snapshot of the relay statistics of the relayer
+// Latencies include the proof signing and the relay network call
+func (r *Relayer) Stats() RelayStats {
+	attempts := atomic.LoadInt64(&r.stats.total.attempts)
+
+	stats := RelayStats{
+		TotalAttempts:    attempts,
+		TotalSuccesses:   atomic.LoadInt64(&r.stats.total.successes),
+		TotalFailures:    atomic.LoadInt64(&r.stats.total.failures),
+		AverageLatencyMs: r.stats.total.averageLatencyMs(attempts),
+	}
+
+	r.stats.mutex.RLock()
+	defer r.stats.mutex.RUnlock()
+
+	stats.NodeStats = make(map[string]NodeStat, len(r.stats.nodes))
+
+	for publicKey, counters := range r.stats.nodes {
+		nodeAttempts := atomic.LoadInt64(&counters.attempts)
+
+		stats.NodeStats[publicKey] = NodeStat{
+			Attempts:     nodeAttempts,
+			Successes:    atomic.LoadInt64(&counters.successes),
+			Failures:     atomic.LoadInt64(&counters.failures),
+			AvgLatencyMs: counters.averageLatencyMs(nodeAttempts),
+		}
+	}
+
+	return stats
+}
+
+// ResetStats zeroes the relay statistics of the relayer, it can be called while relays are in flight
+// Relays finishing during the reset can be counted either before or after it
+func (r *Relayer) ResetStats() {
+	r.stats.mutex.Lock()
+	defer r.stats.mutex.Unlock()
+
+	r.stats.total.reset()
+	r.stats.nodes = map[string]*relayCounters{}
+}
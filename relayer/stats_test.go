@@ -0,0 +1,123 @@
+package relayer
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/utils-go/mock-client"
+)
+
+func TestRelayer_Stats(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+
+	stats := relayer.Stats()
+	c.Zero(stats.TotalAttempts)
+	c.Zero(stats.AverageLatencyMs)
+	c.Empty(stats.NodeStats)
+
+	input := newConsensusInput()
+	input.Node = input.Session.Nodes[0]
+
+	addMockedNodeRelay("https://aog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	for i := 0; i < 2; i++ {
+		_, err = relayer.Relay(input, nil)
+		c.NoError(err)
+	}
+
+	input.Node = input.Session.Nodes[1]
+
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://pjog.com", provider.ClientRelayRoute),
+		http.StatusInternalServerError, "{}")
+
+	_, err = relayer.Relay(input, nil)
+	c.Error(err)
+
+	_, err = relayer.Relay(&Input{}, nil)
+	c.Equal(ErrNoSession, err)
+
+	stats = relayer.Stats()
+	c.Equal(int64(3), stats.TotalAttempts)
+	c.Equal(int64(2), stats.TotalSuccesses)
+	c.Equal(int64(1), stats.TotalFailures)
+	c.Greater(stats.AverageLatencyMs, float64(0))
+	c.Len(stats.NodeStats, 2)
+
+	c.Equal(int64(2), stats.NodeStats["AOG"].Attempts)
+	c.Equal(int64(2), stats.NodeStats["AOG"].Successes)
+	c.Zero(stats.NodeStats["AOG"].Failures)
+	c.Greater(stats.NodeStats["AOG"].AvgLatencyMs, float64(0))
+
+	c.Equal(int64(1), stats.NodeStats["PJOG"].Attempts)
+	c.Zero(stats.NodeStats["PJOG"].Successes)
+	c.Equal(int64(1), stats.NodeStats["PJOG"].Failures)
+}
+
+func TestRelayer_ResetStats(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+
+	input := newConsensusInput()
+	input.Node = input.Session.Nodes[0]
+
+	addMockedNodeRelay("https://aog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, _ = relayer.Relay(input, nil)
+		}()
+
+		if i == 5 {
+			relayer.ResetStats()
+		}
+	}
+
+	wg.Wait()
+
+	relayer.ResetStats()
+
+	stats := relayer.Stats()
+	c.Zero(stats.TotalAttempts)
+	c.Zero(stats.TotalSuccesses)
+	c.Zero(stats.TotalFailures)
+	c.Zero(stats.AverageLatencyMs)
+	c.Empty(stats.NodeStats)
+
+	for i := 0; i < 3; i++ {
+		_, err = relayer.Relay(input, nil)
+		c.NoError(err)
+	}
+
+	stats = relayer.Stats()
+	c.Equal(int64(3), stats.TotalAttempts)
+	c.Equal(int64(3), stats.TotalSuccesses)
+	c.Equal(int64(3), stats.NodeStats["AOG"].Attempts)
+}
@@ -0,0 +1,133 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+const (
+	// ProofRequiredCloseCode is the WebSocket close code a servicer uses to ask for a new proof, RelaySubscribe
+	// connects again with a newly signed proof when the servicer closes with it
+	ProofRequiredCloseCode = 4001
+	// DefaultSubscriptionMaxResigns is the default amount of consecutive new proofs RelaySubscribe sends
+	// without receiving a message before giving up
+	DefaultSubscriptionMaxResigns = 5
+)
+
+var (
+	// ErrSubscriptionClosed error when the servicer closes a relay subscription
+	ErrSubscriptionClosed = errors.New("relay subscription closed")
+	// ErrRelaySubscribeNotSupported error when the relayer provider can not relay over WebSocket
+	ErrRelaySubscribeNotSupported = errors.New("provider does not support relay subscriptions")
+)
+
+// RelaySubscriber interface representing providers able to relay over WebSocket, like provider.Provider
+type RelaySubscriber interface {
+	RelaySubscribe(ctx context.Context, rpcURL string, input *provider.RelayInput, handler func(message []byte)) error
+}
+
+// SubscriptionClosedError represents the thrown error when the servicer closes a relay subscription
+type SubscriptionClosedError struct {
+	Node   *provider.Node
+	Code   int
+	Reason string
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *SubscriptionClosedError) Error() string {
+	return fmt.Sprintf("%s: code %d: %s with ServicerPubKey: %s", ErrSubscriptionClosed, e.Code, e.Reason, e.Node.PublicKey)
+}
+
+// Unwrap returns ErrSubscriptionClosed so the error can be checked with errors.Is
+func (e *SubscriptionClosedError) Unwrap() error {
+	return ErrSubscriptionClosed
+}
+
+// WithSubscriptionMaxResigns sets the amount of consecutive new proofs RelaySubscribe sends when the servicer
+// keeps closing with ProofRequiredCloseCode without any message in between, defaults to DefaultSubscriptionMaxResigns
+func WithSubscriptionMaxResigns(maxResigns int) RelayerOption {
+	return func(r *Relayer) {
+		r.subscriptionMaxResigns = maxResigns
+	}
+}
+
+// RelaySubscribe signs the relay of given input like Relay does and sends it over a WebSocket to the servicer
+// Every message of the servicer is given to handler until ctx is done, then the ctx error is returned
+// A close of the servicer is returned as a SubscriptionClosedError, also a connection dropped without close frame
+// with code provider.WebSocketCloseNoStatus, except with ProofRequiredCloseCode where the subscription is done again
+// to the same servicer with a new proof after the retry backoff, see WithSubscriptionMaxResigns
+// The subscription counts as a relay in flight for Drain until it returns
+func (r *Relayer) RelaySubscribe(ctx context.Context, input *Input, handler func(message []byte)) error {
+	defer r.trackRelay()()
+
+	subscriber, ok := r.provider.(RelaySubscriber)
+	if !ok {
+		return ErrRelaySubscribeNotSupported
+	}
+
+	resigns := 0
+
+	for {
+		relay, node, err := r.BuildRelay(input)
+		if err != nil {
+			return err
+		}
+
+		received := false
+
+		err = subscriber.RelaySubscribe(ctx, node.ServiceURL, relay, func(message []byte) {
+			received = true
+			handler(message)
+		})
+
+		closedErr := getSubscriptionClosedError(ctx, node, err)
+		if closedErr == nil {
+			return err
+		}
+
+		if closedErr.Code != ProofRequiredCloseCode {
+			return closedErr
+		}
+
+		if received {
+			resigns = 0
+		}
+
+		resigns++
+		if resigns > r.subscriptionMaxResigns {
+			return closedErr
+		}
+
+		err = waitBackoff(ctx, r.getRetryDelays(nil), resigns)
+		if err != nil {
+			return err
+		}
+
+		nodeInput := *input
+		nodeInput.Node = node
+		input = &nodeInput
+	}
+}
+
+// getSubscriptionClosedError returns the SubscriptionClosedError of a subscription error
+// or nil when ctx is done or the subscription failed before being established
+func getSubscriptionClosedError(ctx context.Context, node *provider.Node, err error) *SubscriptionClosedError {
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	var closeErr *provider.WebSocketCloseError
+	if errors.As(err, &closeErr) {
+		return &SubscriptionClosedError{Node: node, Code: closeErr.Code, Reason: closeErr.Reason}
+	}
+
+	if errors.Is(err, provider.ErrWebSocketConnectionLost) {
+		return &SubscriptionClosedError{Node: node, Code: provider.WebSocketCloseNoStatus, Reason: err.Error()}
+	}
+
+	return nil
+}
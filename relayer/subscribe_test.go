@@ -0,0 +1,133 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/stretchr/testify/require"
+)
+
+type relaySubscriberMock struct {
+	providerMock
+	relays   []*provider.RelayInput
+	rpcURLs  []string
+	closeErr []error
+	silent   bool
+	started  chan struct{}
+}
+
+func (p *relaySubscriberMock) RelaySubscribe(ctx context.Context, rpcURL string, input *provider.RelayInput,
+	handler func(message []byte)) error {
+	p.relays = append(p.relays, input)
+	p.rpcURLs = append(p.rpcURLs, rpcURL)
+
+	if p.started != nil {
+		close(p.started)
+		<-ctx.Done()
+
+		return ctx.Err()
+	}
+
+	if !p.silent {
+		handler([]byte(input.Proof.Signature))
+	}
+
+	err := p.closeErr[0]
+	p.closeErr = p.closeErr[1:]
+
+	return err
+}
+
+func TestRelayer_RelaySubscribe(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	input := newBatchInputs(1)[0]
+
+	err = NewRelayer(signer, &providerMock{}).RelaySubscribe(context.Background(), input, func(message []byte) {})
+	c.Equal(ErrRelaySubscribeNotSupported, err)
+
+	subscriber := &relaySubscriberMock{closeErr: []error{
+		&provider.WebSocketCloseError{Code: ProofRequiredCloseCode, Reason: "new proof"},
+		&provider.WebSocketCloseError{Code: 1001, Reason: "going away"},
+	}}
+
+	var messages []string
+
+	err = NewRelayer(signer, subscriber).RelaySubscribe(context.Background(), input, func(message []byte) {
+		messages = append(messages, string(message))
+	})
+	c.True(errors.Is(err, ErrSubscriptionClosed))
+
+	var closedErr *SubscriptionClosedError
+	c.ErrorAs(err, &closedErr)
+	c.Equal(1001, closedErr.Code)
+	c.Equal("going away", closedErr.Reason)
+
+	c.Len(subscriber.relays, 2)
+	c.Equal(subscriber.rpcURLs[0], subscriber.rpcURLs[1])
+	c.Equal(subscriber.relays[0].Proof.ServicerPubKey, subscriber.relays[1].Proof.ServicerPubKey)
+	c.Equal(closedErr.Node.PublicKey, subscriber.relays[1].Proof.ServicerPubKey)
+	c.NotEqual(subscriber.relays[0].Proof.Signature, subscriber.relays[1].Proof.Signature)
+	c.Equal([]string{subscriber.relays[0].Proof.Signature, subscriber.relays[1].Proof.Signature}, messages)
+	c.Nil(input.Node)
+
+	subscriber = &relaySubscriberMock{closeErr: []error{context.Canceled}}
+
+	err = NewRelayer(signer, subscriber).RelaySubscribe(context.Background(), input, func(message []byte) {})
+	c.Equal(context.Canceled, err)
+
+	subscriber = &relaySubscriberMock{closeErr: []error{fmt.Errorf("%w: EOF", provider.ErrWebSocketConnectionLost)}}
+
+	err = NewRelayer(signer, subscriber).RelaySubscribe(context.Background(), input, func(message []byte) {})
+	c.ErrorAs(err, &closedErr)
+	c.Equal(provider.WebSocketCloseNoStatus, closedErr.Code)
+	c.Contains(closedErr.Reason, "EOF")
+
+	proofRequiredErr := &provider.WebSocketCloseError{Code: ProofRequiredCloseCode, Reason: "new proof"}
+	subscriber = &relaySubscriberMock{silent: true, closeErr: []error{
+		proofRequiredErr, proofRequiredErr, proofRequiredErr, proofRequiredErr, proofRequiredErr,
+	}}
+
+	err = NewRelayer(signer, subscriber, WithSubscriptionMaxResigns(2), WithRetryBaseDelay(time.Millisecond)).
+		RelaySubscribe(context.Background(), input, func(message []byte) {})
+	c.ErrorAs(err, &closedErr)
+	c.Equal(ProofRequiredCloseCode, closedErr.Code)
+	c.Len(subscriber.relays, 3)
+}
+
+func TestRelayer_RelaySubscribeDrain(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	started := make(chan struct{})
+	relayer := NewRelayer(signer, &relaySubscriberMock{started: started})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() {
+		done <- relayer.RelaySubscribe(ctx, newBatchInputs(1)[0], func(message []byte) {})
+	}()
+
+	<-started
+
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer drainCancel()
+
+	c.Equal(context.DeadlineExceeded, relayer.Drain(drainCtx))
+
+	cancel()
+	c.Equal(context.Canceled, <-done)
+	c.NoError(relayer.Drain(context.Background()))
+}
@@ -0,0 +1,135 @@
+// Package relayertesting has random generators of relayer inputs for property based tests with testing/quick
+package relayertesting
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing/quick"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/relayer"
+)
+
+const (
+	// maxSessionNodes bounds the nodes of a generated session
+	maxSessionNodes = 24
+	// maxHeaders bounds the headers of a generated input
+	maxHeaders = 3
+)
+
+var (
+	quickChains  = []string{"0001", "0021", "0027", "0040"}
+	quickMethods = []string{"eth_blockNumber", "eth_chainId", "eth_getBalance", "net_version"}
+)
+
+var (
+	_ quick.Generator = QuickInput{}
+	_ quick.Generator = QuickNode{}
+)
+
+// QuickInput is a random valid relayer.Input generated by testing/quick, use it as argument of a property
+// The input has an AAT, a session of the input blockchain with the AAT app and between 1 and 24 nodes
+// serving the blockchain, and no node set so the relayer chooses one
+type QuickInput struct {
+	*relayer.Input
+}
+
+// Generate returns a random QuickInput, needed to implement quick.Generator
+func (QuickInput) Generate(rand *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(QuickInput{Input: RandomInput(rand, size)})
+}
+
+// QuickNode is a random staked provider.Node generated by testing/quick, use it as argument of a property
+type QuickNode struct {
+	*provider.Node
+}
+
+// Generate returns a random QuickNode, needed to implement quick.Generator
+func (QuickNode) Generate(rand *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(QuickNode{Node: RandomNode(rand, quickChains[rand.Intn(len(quickChains))])})
+}
+
+// RandomInput returns a random valid relayer.Input, size bounds the amount of session nodes and headers
+func RandomInput(rand *rand.Rand, size int) *relayer.Input {
+	blockchain := quickChains[rand.Intn(len(quickChains))]
+	aat := &provider.ViperAAT{
+		Version:      "0.0.1",
+		AppPubKey:    randomHex(rand, 32),
+		ClientPubKey: randomHex(rand, 32),
+		Signature:    randomHex(rand, 64),
+	}
+
+	nodes := make([]*provider.Node, 1+rand.Intn(boundSize(size, maxSessionNodes)))
+	for i := range nodes {
+		nodes[i] = RandomNode(rand, blockchain)
+	}
+
+	return &relayer.Input{
+		Blockchain: blockchain,
+		Data: fmt.Sprintf(`{"method":%q,"id":%d,"jsonrpc":"2.0"}`,
+			quickMethods[rand.Intn(len(quickMethods))], rand.Intn(1000000)),
+		Headers:  randomHeaders(rand, size),
+		Method:   "POST",
+		ViperAAT: aat,
+		Session: &provider.Session{
+			Header: &provider.SessionHeader{
+				AppPublicKey:  aat.AppPubKey,
+				Chain:         blockchain,
+				SessionHeight: 1 + rand.Intn(1000000),
+			},
+			Key:   randomHex(rand, 32),
+			Nodes: nodes,
+		},
+	}
+}
+
+// RandomNode returns a random staked provider.Node serving given blockchain
+func RandomNode(rand *rand.Rand, blockchain string) *provider.Node {
+	address := randomHex(rand, 20)
+
+	return &provider.Node{
+		Address:       address,
+		Chains:        []string{blockchain},
+		PublicKey:     randomHex(rand, 32),
+		ServiceURL:    fmt.Sprintf("https://%s.viper.network", address[:8]),
+		Status:        2,
+		Tokens:        fmt.Sprint(1 + rand.Int63()),
+		OutputAddress: address,
+	}
+}
+
+func randomHeaders(rand *rand.Rand, size int) provider.RelayHeaders {
+	count := rand.Intn(boundSize(size, maxHeaders) + 1)
+	if count == 0 {
+		return nil
+	}
+
+	headers := make(provider.RelayHeaders, count)
+	for i := 0; i < count; i++ {
+		headers[fmt.Sprintf("X-Quick-%d", i)] = randomHex(rand, 4)
+	}
+
+	return headers
+}
+
+// boundSize returns size in [1, limit]
+func boundSize(size, limit int) int {
+	if size < 1 {
+		return 1
+	}
+
+	if size > limit {
+		return limit
+	}
+
+	return size
+}
+
+func randomHex(rand *rand.Rand, length int) string {
+	bytes := make([]byte, length)
+	_, _ = rand.Read(bytes)
+
+	return hex.EncodeToString(bytes)
+}
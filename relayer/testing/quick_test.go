@@ -0,0 +1,43 @@
+package relayertesting
+
+import (
+	"testing"
+	"testing/quick"
+
+	"github.com/vishruthsk/viper-go/relayer"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuickInput(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayerWithSigner := relayer.NewRelayer(signer, nil)
+
+	err = quick.Check(func(input QuickInput) bool {
+		relay, node, err := relayerWithSigner.BuildRelay(input.Input)
+		if err != nil || !relayer.IsNodeInSession(input.Session, node) {
+			return false
+		}
+
+		proofBytes, err := relayer.GenerateProofBytes(relay.Proof)
+
+		return err == nil && len(proofBytes) != 0
+	}, nil)
+	c.NoError(err)
+}
+
+func TestQuickNode(t *testing.T) {
+	c := require.New(t)
+
+	err := quick.Check(func(node QuickNode) bool {
+		_, staked := node.StakedTokens()
+
+		return staked && len(node.PublicKey) == 64 && len(node.Chains) == 1
+	}, nil)
+	c.NoError(err)
+}
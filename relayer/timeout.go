@@ -0,0 +1,113 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// ErrTimeout error when a relay does not finish before the timeout of its input
+var ErrTimeout = errors.New("relay timed out")
+
+// RelayStage enum that represents how far a relay got
+type RelayStage string
+
+const (
+	// RelayStageStarted is a relay validating its input, choosing its node or signing its proof
+	RelayStageStarted RelayStage = "started"
+	// RelayStageSigned is a relay with a signed proof waiting to be sent
+	RelayStageSigned RelayStage = "signed"
+	// RelayStageSent is a relay sent to the node and waiting for its response
+	RelayStageSent RelayStage = "sent"
+)
+
+// TimeoutError represents the thrown error when a relay does not finish before Input.Timeout
+// Stage is how far the relay got when the timeout expired
+type TimeoutError struct {
+	Timeout time.Duration
+	Stage   RelayStage
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s: after %s with stage: %s", ErrTimeout, e.Timeout, e.Stage)
+}
+
+// Unwrap returns ErrTimeout so the error can be checked with errors.Is
+func (e *TimeoutError) Unwrap() error {
+	return ErrTimeout
+}
+
+type relayProgressKey struct{}
+
+// relayProgress tracks the stage reached by a relay, it is shared with the relay through its context
+type relayProgress struct {
+	stage atomic.Value
+}
+
+func newRelayProgress() *relayProgress {
+	progress := &relayProgress{}
+	progress.stage.Store(RelayStageStarted)
+
+	return progress
+}
+
+func (p *relayProgress) getStage() RelayStage {
+	return p.stage.Load().(RelayStage)
+}
+
+// setRelayStage records the stage reached by the relay of ctx, it does nothing for relays without timeout
+func setRelayStage(ctx context.Context, stage RelayStage) {
+	progress, ok := ctx.Value(relayProgressKey{}).(*relayProgress)
+	if !ok {
+		return
+	}
+
+	progress.stage.Store(stage)
+}
+
+// relayWithInputTimeout does the relay bounded by Input.Timeout, covering node selection, signing and the node call
+// the relay keeps running in background after a timeout and its result is discarded, it stays tracked for Drain
+// until it finishes
+func (r *Relayer) relayWithInputTimeout(ctx context.Context, input *Input, relay *provider.RelayInput,
+	options *provider.RelayRequestOptions) (*Output, error) {
+	if input.Timeout <= 0 {
+		return r.relayWithSessionRefresh(ctx, input, relay, options)
+	}
+
+	progress := newRelayProgress()
+
+	ctx, cancel := context.WithTimeout(context.WithValue(ctx, relayProgressKey{}, progress), input.Timeout)
+	defer cancel()
+
+	results := make(chan relayResult, 1)
+
+	done := r.trackRelay()
+
+	go func() {
+		defer done()
+
+		output, err := r.relayWithSessionRefresh(ctx, input, relay, options)
+		results <- relayResult{output: output, err: err}
+	}()
+
+	select {
+	case result := <-results:
+		if result.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, &TimeoutError{Timeout: input.Timeout, Stage: progress.getStage()}
+		}
+
+		return result.output, result.err
+	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ctx.Err()
+		}
+
+		return nil, &TimeoutError{Timeout: input.Timeout, Stage: progress.getStage()}
+	}
+}
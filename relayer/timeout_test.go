@@ -0,0 +1,68 @@
+package relayer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/stretchr/testify/require"
+)
+
+type slowSignerMock struct {
+	signer *signer.Signer
+	delay  time.Duration
+}
+
+func (s *slowSignerMock) Sign(payload []byte) (string, error) {
+	time.Sleep(s.delay)
+
+	return s.signer.Sign(payload)
+}
+
+func TestRelayer_InputTimeout(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	input := newBatchInputs(1)[0]
+	input.Timeout = 50 * time.Millisecond
+
+	relay, err := NewRelayer(signer, &providerMock{}).Relay(input, nil)
+	c.NoError(err)
+	c.NotNil(relay)
+
+	_, err = NewRelayer(&slowSignerMock{signer: signer, delay: 200 * time.Millisecond}, &providerMock{}).Relay(input, nil)
+	c.True(errors.Is(err, ErrTimeout))
+
+	var timeoutErr *TimeoutError
+	c.ErrorAs(err, &timeoutErr)
+	c.Equal(RelayStageStarted, timeoutErr.Stage)
+	c.Equal(50*time.Millisecond, timeoutErr.Timeout)
+
+	_, err = NewRelayer(signer, &providerMock{delay: 200 * time.Millisecond}).Relay(input, nil)
+	c.ErrorAs(err, &timeoutErr)
+	c.Equal(RelayStageSent, timeoutErr.Stage)
+
+	relayer := NewRelayer(signer, &providerMock{delay: 200 * time.Millisecond})
+	relayer.SetPerNodeConcurrencyLimit(1)
+
+	busyInput := *input
+	busyInput.Timeout = 0
+	busyInput.Node = input.Session.Nodes[0]
+
+	go func() {
+		_, _ = relayer.Relay(&busyInput, nil)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	nodeInput := *input
+	nodeInput.Node = input.Session.Nodes[0]
+
+	_, err = relayer.Relay(&nodeInput, nil)
+	c.ErrorAs(err, &timeoutErr)
+	c.Equal(RelayStageSigned, timeoutErr.Stage)
+}
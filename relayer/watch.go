@@ -0,0 +1,104 @@
+package relayer
+
+import (
+	"context"
+	"time"
+
+	"github.com/vishruthsk/viper-go/provider"
+)
+
+// relayErrorBufferSize is the number of events a WatchRelayErrors channel holds before dropping new ones
+const relayErrorBufferSize = 64
+
+// ErrorLevel represents the severity of a relay error
+type ErrorLevel int
+
+const (
+	// ErrorLevelTransient errors can succeed when the relay is done again, like timeouts or network errors
+	ErrorLevelTransient ErrorLevel = iota
+	// ErrorLevelPermanent errors fail again when the relay is done again, like proof or session errors
+	ErrorLevelPermanent
+)
+
+// GetErrorLevel returns the severity of given relay error, errors retryable by IsRetryableError are transient
+func GetErrorLevel(err error) ErrorLevel {
+	if IsRetryableError(err) {
+		return ErrorLevelTransient
+	}
+
+	return ErrorLevelPermanent
+}
+
+// RelayErrorEvent represents a failed relay to a node
+type RelayErrorEvent struct {
+	Err       error
+	Input     *Input
+	Node      *provider.Node
+	Timestamp time.Time
+}
+
+type relayErrorWatcher struct {
+	events   chan RelayErrorEvent
+	minLevel ErrorLevel
+}
+
+// WatchRelayErrors returns a channel receiving every failed relay to a node with an error of minLevel or higher
+// Events are dropped while the channel buffer is full so a slow consumer never blocks relays
+// The channel is closed when ctx is done
+func (r *Relayer) WatchRelayErrors(ctx context.Context, minLevel ErrorLevel) <-chan RelayErrorEvent {
+	watcher := &relayErrorWatcher{
+		events:   make(chan RelayErrorEvent, relayErrorBufferSize),
+		minLevel: minLevel,
+	}
+
+	r.watchersMutex.Lock()
+	r.errorWatchers = append(r.errorWatchers, watcher)
+	r.watchersMutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		r.removeErrorWatcher(watcher)
+	}()
+
+	return watcher.events
+}
+
+func (r *Relayer) removeErrorWatcher(watcher *relayErrorWatcher) {
+	r.watchersMutex.Lock()
+	defer r.watchersMutex.Unlock()
+
+	for i, errorWatcher := range r.errorWatchers {
+		if errorWatcher == watcher {
+			r.errorWatchers = append(r.errorWatchers[:i:i], r.errorWatchers[i+1:]...)
+
+			break
+		}
+	}
+
+	close(watcher.events)
+}
+
+// notifyRelayError sends the failed relay to the watchers whose minimum level it reaches without blocking
+func (r *Relayer) notifyRelayError(input *Input, node *provider.Node, err error) {
+	r.watchersMutex.RLock()
+	defer r.watchersMutex.RUnlock()
+
+	if len(r.errorWatchers) == 0 {
+		return
+	}
+
+	level := GetErrorLevel(err)
+	event := RelayErrorEvent{Err: err, Input: input, Node: node, Timestamp: time.Now()}
+
+	for _, watcher := range r.errorWatchers {
+		if level < watcher.minLevel {
+			continue
+		}
+
+		select {
+		case watcher.events <- event:
+		default:
+		}
+	}
+}
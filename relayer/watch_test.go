@@ -0,0 +1,94 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/vishruthsk/viper-go/provider"
+	"github.com/vishruthsk/viper-go/signer"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishruthsk/utils-go/mock-client"
+)
+
+func TestRelayer_WatchRelayErrors(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	addMockedNodeRelay("https://aog.com", `{"id":1,"jsonrpc":"2.0","result":"0xdd03e4"}`)
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://pjog.com", provider.ClientRelayRoute),
+		http.StatusInternalServerError, `{"error": "internal"}`)
+	mock.AddMockedResponse(http.MethodPost, fmt.Sprintf("%s%s", "https://ohana.com", provider.ClientRelayRoute),
+		http.StatusBadRequest, `{"error": {"code": 14, "codespace": "vipercore", "message": "invalid session"}}`)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	relayer := NewRelayer(signer, provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	allErrors := relayer.WatchRelayErrors(ctx, ErrorLevelTransient)
+	permanentErrors := relayer.WatchRelayErrors(ctx, ErrorLevelPermanent)
+
+	input := newConsensusInput()
+
+	input.Node = input.Session.Nodes[0]
+	_, err = relayer.Relay(input, nil)
+	c.NoError(err)
+	c.Empty(allErrors)
+
+	input.Node = input.Session.Nodes[1]
+	_, err = relayer.Relay(input, nil)
+	c.True(errors.Is(err, provider.Err5xxOnConnection))
+
+	event := <-allErrors
+	c.True(errors.Is(event.Err, provider.Err5xxOnConnection))
+	c.Equal(input, event.Input)
+	c.Equal("PJOG", event.Node.PublicKey)
+	c.False(event.Timestamp.IsZero())
+	c.Empty(permanentErrors)
+
+	input.Node = input.Session.Nodes[2]
+	_, err = relayer.Relay(input, nil)
+
+	var relayErr *provider.RelayError
+	c.True(errors.As(err, &relayErr))
+
+	event = <-allErrors
+	c.Equal("OHANA", event.Node.PublicKey)
+
+	event = <-permanentErrors
+	c.True(errors.As(event.Err, &relayErr))
+	c.Equal("OHANA", event.Node.PublicKey)
+
+	input.Node = input.Session.Nodes[1]
+
+	for i := 0; i < relayErrorBufferSize+10; i++ {
+		_, err = relayer.Relay(input, nil)
+		c.Error(err)
+	}
+
+	c.Len(allErrors, relayErrorBufferSize)
+
+	cancel()
+
+	received := 0
+	for range allErrors {
+		received++
+	}
+
+	c.Equal(relayErrorBufferSize, received)
+
+	_, ok := <-permanentErrors
+	c.False(ok)
+
+	c.Equal(ErrorLevelTransient, GetErrorLevel(context.DeadlineExceeded))
+	c.Equal(ErrorLevelPermanent, GetErrorLevel(ErrNoSession))
+}
@@ -0,0 +1,45 @@
+package signer
+
+import (
+	"encoding/hex"
+	"errors"
+)
+
+// ErrNoSignFunc error when a RemoteSigner has no signing callback
+var ErrNoSignFunc = errors.New("no sign function provided")
+
+// PayloadSigner interface representing anything able to sign relay payloads, it matches relayer.Signer
+// It can be implemented outside of this package so private keys never need to be loaded in the process
+type PayloadSigner interface {
+	Sign(payload []byte) (string, error)
+}
+
+// SignFunc signs a payload with a key kept outside of the process, like in an HSM or a remote KMS
+// It must return the raw ed25519 signature
+type SignFunc func(payload []byte) ([]byte, error)
+
+// RemoteSigner is a PayloadSigner delegating the signature to a user provided callback
+type RemoteSigner struct {
+	signFunc SignFunc
+}
+
+// NewRemoteSigner returns instance of RemoteSigner with given signing callback
+func NewRemoteSigner(signFunc SignFunc) *RemoteSigner {
+	return &RemoteSigner{
+		signFunc: signFunc,
+	}
+}
+
+// Sign returns the hex encoded signature made by the signing callback
+func (s *RemoteSigner) Sign(payload []byte) (string, error) {
+	if s.signFunc == nil {
+		return "", ErrNoSignFunc
+	}
+
+	signature, err := s.signFunc(payload)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(signature), nil
+}
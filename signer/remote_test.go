@@ -0,0 +1,48 @@
+package signer
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteSigner_Sign(t *testing.T) {
+	c := require.New(t)
+
+	localSigner, err := NewRandomSigner()
+	c.NoError(err)
+
+	var remoteSigner PayloadSigner = NewRemoteSigner(localSigner.SignBytes)
+
+	signature, err := remoteSigner.Sign([]byte("deadbeef"))
+	c.NoError(err)
+
+	localSignature, err := localSigner.Sign([]byte("deadbeef"))
+	c.NoError(err)
+	c.Equal(localSignature, signature)
+
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	c.NoError(err)
+
+	remoteSigner = NewRemoteSigner(func(payload []byte) ([]byte, error) {
+		return ed25519.Sign(privateKey, payload), nil
+	})
+
+	signature, err = remoteSigner.Sign([]byte("deadbeef"))
+	c.NoError(err)
+	c.Len(signature, ed25519.SignatureSize*2)
+
+	kmsErr := errors.New("kms unavailable")
+
+	signature, err = NewRemoteSigner(func(payload []byte) ([]byte, error) {
+		return nil, kmsErr
+	}).Sign([]byte("deadbeef"))
+	c.Equal(kmsErr, err)
+	c.Empty(signature)
+
+	signature, err = NewRemoteSigner(nil).Sign([]byte("deadbeef"))
+	c.Equal(ErrNoSignFunc, err)
+	c.Empty(signature)
+}
@@ -2,6 +2,7 @@
 package signer
 
 import (
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/ed25519"
@@ -9,8 +10,10 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/vishruthsk/viper-go/utils"
 
@@ -30,8 +33,14 @@ const (
 var (
 	// ErrInvalidPrivateKey error when private key is invalid
 	ErrInvalidPrivateKey = errors.New("invalid private key")
+	// ErrInvalidPrivateKeyLength error when private key is not 64 bytes long
+	ErrInvalidPrivateKeyLength = fmt.Errorf("%w: must be 64 bytes (128 hex characters)", ErrInvalidPrivateKey)
+	// ErrInvalidPrivateKeyHex error when private key is not a valid hex string
+	ErrInvalidPrivateKeyHex = fmt.Errorf("%w: must be hex encoded", ErrInvalidPrivateKey)
 	// ErrInvalidPPK error when PPK is invalid
 	ErrInvalidPPK = errors.New("invalid ppk")
+	// ErrAddressMismatch error when the address derived from the key is not the expected one
+	ErrAddressMismatch = errors.New("address does not match key")
 
 	base64Regex = regexp.MustCompile("^(?:[A-Za-z0-9+/]{4})*(?:[A-Za-z0-9+/]{2}==|[A-Za-z0-9+/]{3}=)?$")
 	hexRegex    = regexp.MustCompile("^[a-fA-F0-9]+$")
@@ -44,6 +53,21 @@ type Signer struct {
 	privateKey string
 }
 
+// SignerOption represents an optional check or setting applied when a Signer is created from a key
+type SignerOption func(*Signer) error
+
+// WithExpectedAddress fails the Signer creation with ErrAddressMismatch
+// when the address derived from the key is not expectedAddress
+func WithExpectedAddress(expectedAddress string) SignerOption {
+	return func(s *Signer) error {
+		if !s.MatchesAddress(expectedAddress) {
+			return fmt.Errorf("%w: expected %s, got %s", ErrAddressMismatch, expectedAddress, s.address)
+		}
+
+		return nil
+	}
+}
+
 // NewRandomSigner returns a Signer with random keys
 func NewRandomSigner() (*Signer, error) {
 	publicKey, privateKey, err := ed25519.GenerateKey(nil)
@@ -63,10 +87,31 @@ func NewRandomSigner() (*Signer, error) {
 	}, nil
 }
 
-// NewSignerFromPrivateKey returns Signer from private key
-func NewSignerFromPrivateKey(privateKey string) (*Signer, error) {
-	if !utils.ValidatePrivateKey(privateKey) {
-		return nil, ErrInvalidPrivateKey
+func validatePrivateKey(privateKey string) error {
+	if len(privateKey) != ed25519.PrivateKeySize*2 {
+		return ErrInvalidPrivateKeyLength
+	}
+
+	decodedKey, err := hex.DecodeString(privateKey)
+	if err != nil {
+		return ErrInvalidPrivateKeyHex
+	}
+
+	expectedKey := ed25519.NewKeyFromSeed(decodedKey[:ed25519.SeedSize])
+	if !bytes.Equal(expectedKey, decodedKey) {
+		return ErrInvalidPrivateKey
+	}
+
+	return nil
+}
+
+// NewSignerFromPrivateKey returns Signer from a hex encoded 64 bytes long ed25519 private key
+// returns ErrInvalidPrivateKeyLength or ErrInvalidPrivateKeyHex for malformed keys, both wrap ErrInvalidPrivateKey
+// options are checked once the signer is derived from the key, like WithExpectedAddress
+func NewSignerFromPrivateKey(privateKey string, options ...SignerOption) (*Signer, error) {
+	err := validatePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
 	}
 
 	publicKey := utils.PublicKeyFromPrivate(privateKey)
@@ -76,11 +121,20 @@ func NewSignerFromPrivateKey(privateKey string) (*Signer, error) {
 		return nil, err
 	}
 
-	return &Signer{
+	signer := &Signer{
 		address:    address,
 		publicKey:  publicKey,
 		privateKey: privateKey,
-	}, nil
+	}
+
+	for _, option := range options {
+		err = option(signer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return signer, nil
 }
 
 func getAESGCMValues(password, saltBytes []byte) ([]byte, cipher.AEAD, error) {
@@ -103,7 +157,7 @@ func getAESGCMValues(password, saltBytes []byte) ([]byte, cipher.AEAD, error) {
 }
 
 // NewSignerFromPPK returns Signer from PPK and its password
-func NewSignerFromPPK(password string, ppk *PPK) (*Signer, error) {
+func NewSignerFromPPK(password string, ppk *PPK, options ...SignerOption) (*Signer, error) {
 	if !ppk.Validate() {
 		return nil, ErrInvalidPPK
 	}
@@ -128,7 +182,7 @@ func NewSignerFromPPK(password string, ppk *PPK) (*Signer, error) {
 		return nil, err
 	}
 
-	return NewSignerFromPrivateKey(string(privateKey))
+	return NewSignerFromPrivateKey(string(privateKey), options...)
 }
 
 // Sign returns a signed request as encoded hex string
@@ -156,6 +210,11 @@ func (s *Signer) GetAddress() string {
 	return s.address
 }
 
+// MatchesAddress returns if the address derived from the signer key is expectedAddress, ignoring hex case
+func (s *Signer) MatchesAddress(expectedAddress string) bool {
+	return strings.EqualFold(s.address, expectedAddress)
+}
+
 // GetPublicKey returns public key value
 func (s *Signer) GetPublicKey() string {
 	return s.publicKey
@@ -54,7 +54,7 @@ func TestSigner_Sign(t *testing.T) {
 	c := require.New(t)
 
 	signer, err := NewSignerFromPrivateKey("")
-	c.Equal(ErrInvalidPrivateKey, err)
+	c.ErrorIs(err, ErrInvalidPrivateKey)
 	c.Empty(signer)
 
 	privateKey := "1f8cbde30ef5a9db0a5a9d5eb40536fc9defc318b8581d543808b7504e0902bcb243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3"
@@ -76,6 +76,31 @@ func TestSigner_Sign(t *testing.T) {
 	c.Equal(expectedSignature, hex.EncodeToString(signatureBytes))
 }
 
+func TestNewSignerFromPrivateKey(t *testing.T) {
+	c := require.New(t)
+
+	privateKey := "1f8cbde30ef5a9db0a5a9d5eb40536fc9defc318b8581d543808b7504e0902bcb243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3"
+
+	signer, err := NewSignerFromPrivateKey(privateKey[2:])
+	c.Equal(ErrInvalidPrivateKeyLength, err)
+	c.ErrorIs(err, ErrInvalidPrivateKey)
+	c.Empty(signer)
+
+	signer, err = NewSignerFromPrivateKey("zz" + privateKey[2:])
+	c.Equal(ErrInvalidPrivateKeyHex, err)
+	c.ErrorIs(err, ErrInvalidPrivateKey)
+	c.Empty(signer)
+
+	signer, err = NewSignerFromPrivateKey(privateKey[:64] + "a243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3")
+	c.Equal(ErrInvalidPrivateKey, err)
+	c.Empty(signer)
+
+	signer, err = NewSignerFromPrivateKey(privateKey)
+	c.NoError(err)
+	c.Equal("b243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3", signer.GetPublicKey())
+	c.Equal("b50a6e20d3733fb89631ae32385b3c85c533c560", signer.GetAddress())
+}
+
 func TestSigner_GetAccount(t *testing.T) {
 	c := require.New(t)
 
@@ -89,3 +114,38 @@ func TestSigner_GetAccount(t *testing.T) {
 	c.Equal(signer.GetPrivateKey(), account.PrivateKey)
 	c.Equal(signer.GetPublicKey(), account.PublicKey)
 }
+
+func TestSigner_MatchesAddress(t *testing.T) {
+	c := require.New(t)
+
+	privateKey := "1f8cbde30ef5a9db0a5a9d5eb40536fc9defc318b8581d543808b7504e0902bcb243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3"
+	address := "b50a6e20d3733fb89631ae32385b3c85c533c560"
+
+	signer, err := NewSignerFromPrivateKey(privateKey)
+	c.NoError(err)
+	c.True(signer.MatchesAddress(address))
+	c.True(signer.MatchesAddress("B50A6E20D3733FB89631AE32385B3C85C533C560"))
+	c.False(signer.MatchesAddress("a50a6e20d3733fb89631ae32385b3c85c533c560"))
+	c.False(signer.MatchesAddress(""))
+
+	signer, err = NewSignerFromPrivateKey(privateKey, WithExpectedAddress(address))
+	c.NoError(err)
+	c.Equal(address, signer.GetAddress())
+
+	signer, err = NewSignerFromPrivateKey(privateKey, WithExpectedAddress("a50a6e20d3733fb89631ae32385b3c85c533c560"))
+	c.ErrorIs(err, ErrAddressMismatch)
+	c.Empty(signer)
+
+	password := "bebitofiufiu"
+
+	ppk, err := NewPPK(privateKey, password, "fiufiu")
+	c.NoError(err)
+
+	signer, err = NewSignerFromPPK(password, ppk, WithExpectedAddress(address))
+	c.NoError(err)
+	c.Equal(address, signer.GetAddress())
+
+	signer, err = NewSignerFromPPK(password, ppk, WithExpectedAddress("a50a6e20d3733fb89631ae32385b3c85c533c560"))
+	c.ErrorIs(err, ErrAddressMismatch)
+	c.Empty(signer)
+}
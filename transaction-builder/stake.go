@@ -0,0 +1,128 @@
+package transactionbuilder
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultMinAppStake is the minimum uvip an app must stake, the application/ApplicationStakeMinimum param
+	DefaultMinAppStake = int64(1000000)
+	// DefaultMinNodeStake is the minimum uvip a node must stake, the pos/StakeMinimum param
+	DefaultMinNodeStake = int64(15000000000)
+)
+
+// StakeMsgType enum that represents the kinds of stake messages
+type StakeMsgType int
+
+const (
+	// StakeMsgTypeApp represents a message built with NewStakeApp
+	StakeMsgTypeApp StakeMsgType = iota + 1
+	// StakeMsgTypeNode represents a message built with NewStakeNode
+	StakeMsgTypeNode
+)
+
+// ErrUnknownStakeMsgType error when the stake message type is not supported
+var ErrUnknownStakeMsgType = errors.New("unknown stake message type")
+
+// ErrBelowMinimumStake error when a stake amount is below the minimum of its message type
+type ErrBelowMinimumStake struct {
+	MinAmount int64
+	Provided  int64
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *ErrBelowMinimumStake) Error() string {
+	return fmt.Sprintf("stake amount %d is below minimum %d", e.Provided, e.MinAmount)
+}
+
+// ErrDuplicateChain error when a chain ID is repeated in the chains of a stake message
+type ErrDuplicateChain struct {
+	ChainID string
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *ErrDuplicateChain) Error() string {
+	return fmt.Sprintf("duplicate chain id %q", e.ChainID)
+}
+
+// ErrInvalidChainID error when a chain ID of a stake message is not a 4 characters hex string, like 0021
+type ErrInvalidChainID struct {
+	ChainID string
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e *ErrInvalidChainID) Error() string {
+	return fmt.Sprintf("invalid chain id %q, expected 4 hex characters", e.ChainID)
+}
+
+// chainIDLength is the length of a Viper blockchain ID
+const chainIDLength = 4
+
+// validateChains returns *ErrInvalidChainID for the first malformed chain ID and *ErrDuplicateChain
+// for the first repeated one
+func validateChains(chains []string) error {
+	seen := make(map[string]bool, len(chains))
+
+	for _, chainID := range chains {
+		if _, err := hex.DecodeString(chainID); err != nil || len(chainID) != chainIDLength {
+			return &ErrInvalidChainID{ChainID: chainID}
+		}
+
+		if seen[chainID] {
+			return &ErrDuplicateChain{ChainID: chainID}
+		}
+
+		seen[chainID] = true
+	}
+
+	return nil
+}
+
+// StakeValidationOption represents an optional parameter of ValidateStakeAmount
+type StakeValidationOption func(*stakeValidationConfig)
+
+type stakeValidationConfig struct {
+	minAmount *int64
+}
+
+// WithMinStake overrides the default minimum of the message type, use it when the chain params differ
+// from DefaultMinAppStake or DefaultMinNodeStake
+func WithMinStake(minAmount int64) StakeValidationOption {
+	return func(c *stakeValidationConfig) {
+		c.minAmount = &minAmount
+	}
+}
+
+// ValidateStakeAmount returns *ErrBelowMinimumStake when amount is below the minimum stake of given message type
+func ValidateStakeAmount(amount int64, msgType StakeMsgType, options ...StakeValidationOption) error {
+	var minAmount int64
+
+	switch msgType {
+	case StakeMsgTypeApp:
+		minAmount = DefaultMinAppStake
+	case StakeMsgTypeNode:
+		minAmount = DefaultMinNodeStake
+	default:
+		return ErrUnknownStakeMsgType
+	}
+
+	config := &stakeValidationConfig{}
+	for _, option := range options {
+		option(config)
+	}
+
+	if config.minAmount != nil {
+		minAmount = *config.minAmount
+	}
+
+	if amount < minAmount {
+		return &ErrBelowMinimumStake{MinAmount: minAmount, Provided: amount}
+	}
+
+	return nil
+}
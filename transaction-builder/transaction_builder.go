@@ -42,6 +42,23 @@ const (
 	Localnet ChainID = "localnet"
 )
 
+// Encoding enum that represents the possible encodings of transactions
+type Encoding string
+
+const (
+	// EncodingAmino use for nodes expecting legacy amino encoded transactions
+	EncodingAmino Encoding = "amino"
+	// EncodingProto use for nodes expecting proto encoded transactions, it is the default
+	EncodingProto Encoding = "proto"
+)
+
+// encodingHeights maps every encoding to the height given to the transaction encoder
+// viper-network encodes with amino before the codec upgrade height and with proto after it, -1 is the latest height
+var encodingHeights = map[Encoding]int64{
+	EncodingAmino: 0,
+	EncodingProto: -1,
+}
+
 var (
 	// ErrNoSigner error when no signer is provided
 	ErrNoSigner = errors.New("no signer provided")
@@ -51,6 +68,20 @@ var (
 	ErrNoChainID = errors.New("no chain id provided")
 	// ErrNoTransactionMessage error when no Transaction Message is provided
 	ErrNoTransactionMessage = errors.New("no transaction message provided")
+	// ErrUnsupportedEncoding error when the transaction encoding is not supported
+	ErrUnsupportedEncoding = errors.New("unsupported transaction encoding")
+	// ErrInvalidServiceURL error when the node service URL is not an https URL
+	ErrInvalidServiceURL = errors.New("invalid service url")
+	// ErrMissingHost error when the node service URL has no host
+	ErrMissingHost = errors.New("service url has no host")
+	// ErrGeoZoneNotSupported error when the node stake message of the network has no geo zone, use NewStakeNode
+	ErrGeoZoneNotSupported = errors.New("geo zone not supported by network, use NewStakeNode")
+	// ErrInvalidAddress error when an address is not a 20 bytes hex address
+	ErrInvalidAddress = errors.New("invalid address")
+	// ErrTransferToSameApp error when an app transfer moves the stake to the public key of the current app
+	ErrTransferToSameApp = errors.New("app transfer to the same app")
+	// ErrURLFieldNotSupported error when the app stake message of the network has no service URL, use NewStakeApp
+	ErrURLFieldNotSupported = errors.New("app service url not supported by network, use NewStakeApp")
 )
 
 // Provider interface representing provider functions necessary for Transaction Builder Package
@@ -72,10 +103,12 @@ type TransactionBuilder struct {
 }
 
 // TransactionOptions represents optional parameters for transaction request
+// Encoding defaults to EncodingProto
 type TransactionOptions struct {
 	Memo      string
 	Fee       int64
 	CoinDenom CoinDenom
+	Encoding  Encoding
 }
 
 // NewTransactionBuilder returns an instance of TransactionBuilder
@@ -106,6 +139,21 @@ func getOptionalParams(options *TransactionOptions) (string, string, int64) {
 	return memo, string(coinDenom), fee
 }
 
+func getEncodingHeight(options *TransactionOptions) (int64, error) {
+	encoding := EncodingProto
+
+	if options != nil && options.Encoding != "" {
+		encoding = options.Encoding
+	}
+
+	height, ok := encodingHeights[encoding]
+	if !ok {
+		return 0, ErrUnsupportedEncoding
+	}
+
+	return height, nil
+}
+
 func (t *TransactionBuilder) validateTransactionRequest(chainID ChainID, txMsg TransactionMessage) error {
 	if t.provider == nil {
 		return ErrNoProvider
@@ -126,7 +174,7 @@ func (t *TransactionBuilder) validateTransactionRequest(chainID ChainID, txMsg T
 	return nil
 }
 
-func (t *TransactionBuilder) signTransaction(chainID, memo, coinDenom string, fee int64, txMsg TransactionMessage) (string, error) {
+func (t *TransactionBuilder) signTransaction(chainID, memo, coinDenom string, fee, encodingHeight int64, txMsg TransactionMessage) (string, error) {
 	feeStruct := coreTypes.Coins{
 		coreTypes.Coin{
 			Amount: coreTypes.NewInt(fee),
@@ -158,7 +206,7 @@ func (t *TransactionBuilder) signTransaction(chainID, memo, coinDenom string, fe
 
 	tx := authTypes.NewTx(txMsg, feeStruct, signatureStruct, memo, entropy.Int64())
 
-	txBytes, err := auth.DefaultTxEncoder(app.Codec())(tx, -1)
+	txBytes, err := auth.DefaultTxEncoder(app.Codec())(tx, encodingHeight)
 	if err != nil {
 		return "", err
 	}
@@ -175,7 +223,12 @@ func (t *TransactionBuilder) CreateTransaction(chainID ChainID, txMsg Transactio
 
 	memo, coinDenom, fee := getOptionalParams(options)
 
-	signedTX, err := t.signTransaction(string(chainID), memo, coinDenom, fee, txMsg)
+	encodingHeight, err := getEncodingHeight(options)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTX, err := t.signTransaction(string(chainID), memo, coinDenom, fee, encodingHeight, txMsg)
 	if err != nil {
 		return nil, err
 	}
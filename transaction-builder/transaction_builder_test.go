@@ -1,8 +1,12 @@
 package transactionbuilder
 
 import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/vishruthsk/viper-go/signer"
@@ -12,6 +16,10 @@ import (
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/require"
 	"github.com/vishruthsk/utils-go/mock-client"
+	"github.com/vishruthsk/viper-network/app"
+	appsType "github.com/vishruthsk/viper-network/x/apps/types"
+	"github.com/vishruthsk/viper-network/x/auth"
+	nodesTypes "github.com/vishruthsk/viper-network/x/nodes/types"
 )
 
 func TestTransactionBuilder_SubmitError(t *testing.T) {
@@ -108,6 +116,51 @@ func TestTransactionBuilder_SubmitStakeApp(t *testing.T) {
 	c.Equal(provider.Err5xxOnConnection, err)
 }
 
+func TestTransactionBuilder_SubmitTransferApp(t *testing.T) {
+	c := require.New(t)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	txBuilder := NewTransactionBuilder(provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}), signer)
+
+	transferApp, err := NewTransferApp(signer.GetAddress(), "b243b27b")
+	c.Error(err)
+	c.Empty(transferApp)
+
+	transferApp, err = NewTransferApp("not hex", "b243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3")
+	c.Error(err)
+	c.Empty(transferApp)
+
+	transferApp, err = NewTransferApp("b243b27b", "b243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3")
+	c.True(errors.Is(err, ErrInvalidAddress))
+	c.Empty(transferApp)
+
+	transferApp, err = NewTransferApp(signer.GetAddress(), signer.GetPublicKey())
+	c.Equal(ErrTransferToSameApp, err)
+	c.Empty(transferApp)
+
+	transferApp, err = NewTransferApp(signer.GetAddress(), "b243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3")
+	c.NoError(err)
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", provider.ClientRawTXRoute),
+		http.StatusOK, "../provider/samples/client_raw_tx.json")
+
+	output, err := txBuilder.Submit(Mainnet, transferApp, nil)
+	c.NoError(err)
+	c.NotEmpty(output)
+
+	mock.AddMockedResponseFromFile(http.MethodPost, fmt.Sprintf("%s%s", "https://dummy.com", provider.ClientRawTXRoute),
+		http.StatusInternalServerError, "../provider/samples/client_raw_tx.json")
+
+	output, err = txBuilder.Submit(Mainnet, transferApp, nil)
+	c.Empty(output)
+	c.Equal(provider.Err5xxOnConnection, err)
+}
+
 func TestTransactionBuilder_SubmitUnstakeApp(t *testing.T) {
 	c := require.New(t)
 
@@ -196,6 +249,192 @@ func TestTransactionBuilder_SubmitStakeNode(t *testing.T) {
 	c.Equal(provider.Err5xxOnConnection, err)
 }
 
+func TestNewStakeNode_ServiceURL(t *testing.T) {
+	c := require.New(t)
+
+	publicKey := "b243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3"
+	outputAddress := "b50a6e20d3733fb89631ae32385b3c85c533c560"
+
+	stakeNode, err := NewStakeNode(publicKey, "https://dummy.com:443/", outputAddress, []string{"0021"}, 21)
+	c.NoError(err)
+	c.Equal("https://dummy.com:443", stakeNode.(*nodesTypes.MsgStake).ServiceUrl)
+
+	stakeNode, err = NewStakeNode(publicKey, "https://10.0.0.21:8081", outputAddress, []string{"0021"}, 21)
+	c.NoError(err)
+	c.Equal("https://10.0.0.21:8081", stakeNode.(*nodesTypes.MsgStake).ServiceUrl)
+
+	stakeNode, err = NewStakeNode(publicKey, "https://dummy.com/", outputAddress, []string{"0021"}, 21)
+	c.NoError(err)
+	c.Equal("https://dummy.com:443", stakeNode.(*nodesTypes.MsgStake).ServiceUrl)
+
+	stakeNode, err = NewStakeNode(publicKey, "https://[::1]", outputAddress, []string{"0021"}, 21)
+	c.NoError(err)
+	c.Equal("https://[::1]:443", stakeNode.(*nodesTypes.MsgStake).ServiceUrl)
+
+	for _, serviceURL := range []string{"", "/v1/client/relay", "dummy.com:443", "http://dummy.com:443",
+		"ftp://dummy.com:21", "https://dummy.com:443/%zz"} {
+		stakeNode, err = NewStakeNode(publicKey, serviceURL, outputAddress, []string{"0021"}, 21)
+		c.True(errors.Is(err, ErrInvalidServiceURL), serviceURL)
+		c.Empty(stakeNode)
+	}
+
+	stakeNode, err = NewStakeNode(publicKey, "https://:443", outputAddress, []string{"0021"}, 21)
+	c.Equal(ErrMissingHost, err)
+	c.Empty(stakeNode)
+}
+
+func TestValidateServiceURL(t *testing.T) {
+	c := require.New(t)
+
+	advisory, err := ValidateServiceURL("https://node.viper.network:443")
+	c.NoError(err)
+	c.Empty(advisory)
+
+	advisory, err = ValidateServiceURL("https://10.0.0.21:443")
+	c.NoError(err)
+	c.Contains(advisory, "10.0.0.21 is an IP address")
+
+	advisory, err = ValidateServiceURL("https://[::1]:443")
+	c.NoError(err)
+	c.Contains(advisory, "::1 is an IP address")
+	c.NotContains(advisory, "no port")
+
+	advisory, err = ValidateServiceURL("https://node.viper.network")
+	c.NoError(err)
+	c.Equal("service url has no port, the default port 443 is used", advisory)
+
+	advisory, err = ValidateServiceURL("https://10.0.0.21")
+	c.NoError(err)
+	c.Contains(advisory, "10.0.0.21 is an IP address")
+	c.Contains(advisory, "no port")
+
+	advisory, err = ValidateServiceURL("http://node.viper.network:80")
+	c.True(errors.Is(err, ErrInvalidServiceURL))
+	c.Empty(advisory)
+
+	advisory, err = ValidateServiceURL("/v1/client/relay")
+	c.True(errors.Is(err, ErrInvalidServiceURL))
+	c.Empty(advisory)
+
+	advisory, err = ValidateServiceURL("https:///v1/client/relay")
+	c.Equal(ErrMissingHost, err)
+	c.Empty(advisory)
+}
+
+func TestNewStakeNodeV2(t *testing.T) {
+	c := require.New(t)
+
+	publicKey := "b243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3"
+	outputAddress := "b50a6e20d3733fb89631ae32385b3c85c533c560"
+
+	stakeNode, err := NewStakeNodeV2(publicKey, "http://dummy.com:443", outputAddress, "us-east", []string{"0021"}, 21)
+	c.True(errors.Is(err, ErrInvalidServiceURL))
+	c.Empty(stakeNode)
+
+	stakeNode, err = NewStakeNodeV2(publicKey, "https://dummy.com:443", outputAddress, "us-east", []string{"0021"}, 21)
+
+	_, hasGeoZone := reflect.TypeOf(nodesTypes.MsgStake{}).FieldByName(geoZoneField)
+	if !hasGeoZone {
+		c.Equal(ErrGeoZoneNotSupported, err)
+		c.Empty(stakeNode)
+
+		return
+	}
+
+	c.NoError(err)
+
+	marshaledMessage, err := json.Marshal(stakeNode)
+	c.NoError(err)
+
+	message := map[string]any{}
+	c.NoError(json.Unmarshal(marshaledMessage, &message))
+	c.Equal("us-east", message["geo_zone"])
+}
+
+func TestNewStakeAppWithURL(t *testing.T) {
+	c := require.New(t)
+
+	publicKey := "b243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3"
+
+	stakeApp, err := NewStakeAppWithURL(publicKey, []string{"0021"}, 21, "http://dummy.com:443")
+	c.True(errors.Is(err, ErrInvalidServiceURL))
+	c.Empty(stakeApp)
+
+	stakeApp, err = NewStakeAppWithURL(publicKey, []string{"21"}, 21, "https://dummy.com:443")
+	c.Equal(&ErrInvalidChainID{ChainID: "21"}, err)
+	c.Empty(stakeApp)
+
+	stakeApp, err = NewStakeAppWithURL(publicKey, []string{"0021"}, 21, "https://dummy.com:443/")
+
+	_, hasServiceURL := reflect.TypeOf(appsType.MsgStake{}).FieldByName(appServiceURLField)
+	if !hasServiceURL {
+		c.Equal(ErrURLFieldNotSupported, err)
+		c.Empty(stakeApp)
+
+		return
+	}
+
+	c.NoError(err)
+
+	marshaledMessage, err := json.Marshal(stakeApp)
+	c.NoError(err)
+
+	message := map[string]any{}
+	c.NoError(json.Unmarshal(marshaledMessage, &message))
+	c.Equal("https://dummy.com:443", message["service_url"])
+}
+
+func TestSetStringField(t *testing.T) {
+	c := require.New(t)
+
+	supported := &struct {
+		GeoZone string
+	}{}
+	c.True(setStringField(supported, geoZoneField, "us-east"))
+	c.Equal("us-east", supported.GeoZone)
+
+	unsupported := &struct {
+		ServiceURL string
+	}{}
+	c.False(setStringField(unsupported, geoZoneField, "us-east"))
+	c.Empty(unsupported.ServiceURL)
+
+	wrongKind := &struct {
+		GeoZone int
+	}{}
+	c.False(setStringField(wrongKind, geoZoneField, "us-east"))
+	c.Zero(wrongKind.GeoZone)
+}
+
+func TestNewStake_Chains(t *testing.T) {
+	c := require.New(t)
+
+	publicKey := "b243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3"
+	outputAddress := "b50a6e20d3733fb89631ae32385b3c85c533c560"
+
+	stakeNode, err := NewStakeNode(publicKey, "https://dummy.com:443", outputAddress, []string{"0001", "0021", "00AB"}, 21)
+	c.NoError(err)
+	c.Equal([]string{"0001", "0021", "00AB"}, stakeNode.(*nodesTypes.MsgStake).Chains)
+
+	stakeApp, err := NewStakeApp(publicKey, []string{"0021", "0040", "0021"}, 21)
+	c.Equal(&ErrDuplicateChain{ChainID: "0021"}, err)
+	c.Empty(stakeApp)
+
+	stakeNode, err = NewStakeNode(publicKey, "https://dummy.com:443", outputAddress, []string{"0001", "0001"}, 21)
+	c.Equal(&ErrDuplicateChain{ChainID: "0001"}, err)
+	c.Empty(stakeNode)
+
+	for _, chainID := range []string{"", "21", "00210", "002G", "0x21"} {
+		stakeApp, err = NewStakeApp(publicKey, []string{"0021", chainID}, 21)
+		c.Equal(&ErrInvalidChainID{ChainID: chainID}, err)
+		c.Empty(stakeApp)
+
+		stakeNode, err = NewStakeNode(publicKey, "https://dummy.com:443", outputAddress, []string{chainID}, 21)
+		c.Equal(&ErrInvalidChainID{ChainID: chainID}, err)
+		c.Empty(stakeNode)
+	}
+}
+
 func TestTransactionBuilder_SubmitUnstakeNode(t *testing.T) {
 	c := require.New(t)
 
@@ -253,3 +492,121 @@ func TestTransactionBuilder_SubmitUnjailNode(t *testing.T) {
 	c.Empty(output)
 	c.Equal(provider.Err5xxOnConnection, err)
 }
+
+func TestTransactionBuilder_CreateTransactionEncoding(t *testing.T) {
+	c := require.New(t)
+
+	signer, err := signer.NewRandomSigner()
+	c.NoError(err)
+
+	txBuilder := NewTransactionBuilder(provider.NewProvider("https://dummy.com", []string{"https://dummy.com"}), signer)
+
+	msgSend, err := NewSend("b50a6e20d3733fb89631ae32385b3c85c533c560", "b50a6e20d3733fb89631ae32385b3c85c533c561", 21)
+	c.NoError(err)
+
+	encodedTxs := map[Encoding][]byte{}
+
+	for _, encoding := range []Encoding{EncodingAmino, EncodingProto} {
+		input, err := txBuilder.CreateTransaction(Mainnet, msgSend, &TransactionOptions{Memo: "ohana", Encoding: encoding})
+		c.NoError(err)
+
+		txBytes, err := hex.DecodeString(input.RawHexBytes)
+		c.NoError(err)
+
+		tx, decodeErr := auth.DefaultTxDecoder(app.Codec())(txBytes, encodingHeights[encoding])
+		c.Nil(decodeErr)
+		c.Equal(msgSend.GetSignBytes(), tx.GetMsg().GetSignBytes())
+
+		encodedTxs[encoding] = txBytes
+	}
+
+	c.NotEqual(encodedTxs[EncodingAmino], encodedTxs[EncodingProto])
+
+	_, decodeErr := auth.DefaultTxDecoder(app.Codec())(encodedTxs[EncodingAmino], encodingHeights[EncodingProto])
+	c.NotNil(decodeErr)
+
+	_, decodeErr = auth.DefaultTxDecoder(app.Codec())(encodedTxs[EncodingProto], encodingHeights[EncodingAmino])
+	c.NotNil(decodeErr)
+
+	input, err := txBuilder.CreateTransaction(Mainnet, msgSend, &TransactionOptions{Encoding: "json"})
+	c.Equal(ErrUnsupportedEncoding, err)
+	c.Empty(input)
+}
+
+func TestValidateStakeAmount(t *testing.T) {
+	c := require.New(t)
+
+	c.NoError(ValidateStakeAmount(DefaultMinAppStake, StakeMsgTypeApp))
+	c.NoError(ValidateStakeAmount(DefaultMinNodeStake, StakeMsgTypeNode))
+	c.NoError(ValidateStakeAmount(DefaultMinAppStake, StakeMsgTypeNode, WithMinStake(DefaultMinAppStake)))
+
+	err := ValidateStakeAmount(DefaultMinAppStake-1, StakeMsgTypeApp)
+	c.Equal(&ErrBelowMinimumStake{MinAmount: DefaultMinAppStake, Provided: DefaultMinAppStake - 1}, err)
+
+	err = ValidateStakeAmount(DefaultMinNodeStake-1, StakeMsgTypeNode)
+	c.Equal(&ErrBelowMinimumStake{MinAmount: DefaultMinNodeStake, Provided: DefaultMinNodeStake - 1}, err)
+
+	err = ValidateStakeAmount(DefaultMinAppStake, StakeMsgTypeApp, WithMinStake(DefaultMinAppStake+1))
+	c.Equal(&ErrBelowMinimumStake{MinAmount: DefaultMinAppStake + 1, Provided: DefaultMinAppStake}, err)
+
+	c.Equal(ErrUnknownStakeMsgType, ValidateStakeAmount(DefaultMinNodeStake, StakeMsgType(0)))
+}
+
+func TestTransactionValidator_Validate(t *testing.T) {
+	c := require.New(t)
+
+	fromAddress := "b50a6e20d3733fb89631ae32385b3c85c533c560"
+	toAddress := "b50a6e20d3733fb89631ae32385b3c85c533c561"
+
+	validator := NewTransactionValidator(ValidatePositiveAmount, ValidateNonEmptyChains, ValidateHexAddress)
+
+	msgSend, err := NewSend(fromAddress, toAddress, 21)
+	c.NoError(err)
+	c.Empty(validator.Validate(msgSend, ValidateSignerAddress(fromAddress)))
+
+	errs := validator.Validate(msgSend, ValidateSignerAddress(toAddress))
+	c.Len(errs, 1)
+	c.Equal("signer_address", errs[0].Rule)
+
+	msgSend, err = NewSend(fromAddress, toAddress, 0)
+	c.NoError(err)
+	errs = validator.Validate(msgSend)
+	c.Len(errs, 1)
+	c.Equal("positive_amount", errs[0].Rule)
+
+	msgSend.(*nodesTypes.MsgSend).ToAddress = msgSend.(*nodesTypes.MsgSend).ToAddress[:19]
+	errs = validator.Validate(msgSend)
+	c.Len(errs, 2)
+	c.Equal("hex_address", errs[1].Rule)
+	c.Equal("hex_address: to_address has 19 bytes, expected 20", errs[1].Error())
+
+	stakeNode, err := NewStakeNode("b243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3", "https://dummy.com:443", fromAddress, []string{"0021"}, 21)
+	c.NoError(err)
+	c.Empty(validator.Validate(stakeNode))
+
+	stakeNode.(*nodesTypes.MsgStake).Chains = nil
+	errs = validator.Validate(stakeNode)
+	c.Len(errs, 1)
+	c.Equal("non_empty_chains", errs[0].Rule)
+
+	customRule := func(msg TransactionMessage) *ValidationError {
+		return &ValidationError{Rule: "custom", Message: "always fails"}
+	}
+	errs = NewTransactionValidator().Validate(stakeNode, customRule)
+	c.Equal([]ValidationError{{Rule: "custom", Message: "always fails"}}, errs)
+
+	errs = validator.Validate(nil)
+	c.Len(errs, 1)
+	c.Equal("message", errs[0].Rule)
+
+	transferApp, err := NewTransferApp("1f32488b1db60fe528ab21e3cc26c96696be3faa",
+		"b243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3")
+	c.NoError(err)
+	c.Empty(validator.Validate(transferApp, ValidateSignerAddress(transferApp.GetSigners()[0].String())))
+
+	stakeApp, err := NewStakeApp("b243b27bc9fbe5580457a46370ae5f03a6f6753633e51efdaf2cf534fdc26cc3", []string{"0021"}, 0)
+	c.NoError(err)
+	errs = validator.Validate(stakeApp)
+	c.Len(errs, 1)
+	c.Equal("positive_amount", errs[0].Rule)
+}
@@ -1,7 +1,13 @@
 package transactionbuilder
 
 import (
+	"bytes"
 	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
 
 	"github.com/vishruthsk/viper-network/crypto"
 	coreTypes "github.com/vishruthsk/viper-network/types"
@@ -34,7 +40,13 @@ func NewSend(fromAddress, toAddress string, amount int64) (TransactionMessage, e
 }
 
 // NewStakeApp returns message for Stake App transaction
+// Every chain must be a 4 characters hex ID listed once, see ErrInvalidChainID and ErrDuplicateChain
 func NewStakeApp(publicKey string, chains []string, amount int64) (TransactionMessage, error) {
+	err := validateChains(chains)
+	if err != nil {
+		return nil, err
+	}
+
 	cryptoPublicKey, err := crypto.NewPublicKey(publicKey)
 	if err != nil {
 		return nil, err
@@ -47,6 +59,63 @@ func NewStakeApp(publicKey string, chains []string, amount int64) (TransactionMe
 	}, nil
 }
 
+// appServiceURLField is the app stake message field of the network versions staking apps with a service URL
+const appServiceURLField = "ServiceUrl"
+
+// NewStakeAppWithURL returns message for Stake App transaction with the service URL of the app
+// The service URL is validated and normalized like the one of NewStakeNode
+// It is only for network versions staking apps with a service URL, ErrURLFieldNotSupported is returned otherwise
+func NewStakeAppWithURL(publicKey string, chains []string, amount int64, serviceURL string) (TransactionMessage, error) {
+	serviceURL, err := normalizeServiceURL(serviceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := NewStakeApp(publicKey, chains, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if !setStringField(message, appServiceURLField, serviceURL) {
+		return nil, ErrURLFieldNotSupported
+	}
+
+	return message, nil
+}
+
+// NewTransferApp returns message for Transfer App transaction, moving the stake of the app of fromAddress
+// to newAppPublicKey
+// fromAddress must be a 20 bytes hex address other than the one of newAppPublicKey, see ErrInvalidAddress and
+// ErrTransferToSameApp
+// The transfer is an app stake of the new public key without chains nor value, the message does not hold the current app
+// so the transaction must be signed with the key of fromAddress, the signer of the TransactionBuilder
+func NewTransferApp(fromAddress, newAppPublicKey string) (TransactionMessage, error) {
+	decodedFromAddress, err := hex.DecodeString(fromAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decodedFromAddress) != addressLength {
+		return nil, fmt.Errorf("%w: %s has %d bytes, expected %d", ErrInvalidAddress, fromAddress,
+			len(decodedFromAddress), addressLength)
+	}
+
+	cryptoPublicKey, err := crypto.NewPublicKey(newAppPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.Equal(decodedFromAddress, cryptoPublicKey.Address()) {
+		return nil, ErrTransferToSameApp
+	}
+
+	return &appsType.MsgStake{
+		PubKey: cryptoPublicKey,
+		Chains: nil,
+		Value:  coreTypes.ZeroInt(),
+	}, nil
+}
+
 // NewUnstakeApp returns message for Unstake App transaction
 func NewUnstakeApp(address string) (TransactionMessage, error) {
 	decodedAddress, err := hex.DecodeString(address)
@@ -72,7 +141,20 @@ func NewUnjailApp(address string) (TransactionMessage, error) {
 }
 
 // NewStakeNode returns message for Stake Node transaction
+// The service URL must be an https URL with host, a missing port defaults to 443 and a trailing slash is removed
+// Returns the error of ValidateServiceURL otherwise, call it to also get the advisory of the URL
+// Every chain must be a 4 characters hex ID listed once, see ErrInvalidChainID and ErrDuplicateChain
 func NewStakeNode(publicKey, serviceURL, outputAddress string, chains []string, amount int64) (TransactionMessage, error) {
+	serviceURL, err := normalizeServiceURL(serviceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateChains(chains)
+	if err != nil {
+		return nil, err
+	}
+
 	cryptoPublicKey, err := crypto.NewPublicKey(publicKey)
 	if err != nil {
 		return nil, err
@@ -92,6 +174,94 @@ func NewStakeNode(publicKey, serviceURL, outputAddress string, chains []string,
 	}, nil
 }
 
+// geoZoneField is the node stake message field of the network versions with geographic staking
+const geoZoneField = "GeoZone"
+
+// NewStakeNodeV2 returns message for Stake Node transaction with the geographic zone of the node
+// It is only for network versions with geographic staking, ErrGeoZoneNotSupported is returned otherwise
+func NewStakeNodeV2(publicKey, serviceURL, outputAddress, geoZone string, chains []string,
+	amount int64) (TransactionMessage, error) {
+	message, err := NewStakeNode(publicKey, serviceURL, outputAddress, chains, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if !setStringField(message, geoZoneField, geoZone) {
+		return nil, ErrGeoZoneNotSupported
+	}
+
+	return message, nil
+}
+
+// setStringField sets the string field of given message, returns false when the message has no such field
+// The field is set by reflection so the package builds against network versions without it
+func setStringField(message any, name, value string) bool {
+	field := reflect.ValueOf(message).Elem().FieldByName(name)
+	if !field.IsValid() || field.Kind() != reflect.String || !field.CanSet() {
+		return false
+	}
+
+	field.SetString(value)
+
+	return true
+}
+
+// defaultServiceURLPort is the port of service URLs staked without port
+const defaultServiceURLPort = "443"
+
+// ValidateServiceURL checks the service URL is an https URL with host, as required to stake a node
+// Returns ErrInvalidServiceURL wrapped with the reason or ErrMissingHost otherwise
+// The advisory is not empty when the URL is valid but likely a mistake, like a bare IP address host
+// or a missing port, staked with the default port 443
+func ValidateServiceURL(serviceURL string) (string, error) {
+	parsedURL, err := url.Parse(serviceURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidServiceURL, err)
+	}
+
+	if parsedURL.Scheme != "https" {
+		return "", fmt.Errorf("%w: scheme must be https", ErrInvalidServiceURL)
+	}
+
+	if parsedURL.Hostname() == "" {
+		return "", ErrMissingHost
+	}
+
+	var advisories []string
+
+	if net.ParseIP(parsedURL.Hostname()) != nil {
+		advisories = append(advisories, fmt.Sprintf("service url host %s is an IP address, "+
+			"use a domain name with a certificate issued for it", parsedURL.Hostname()))
+	}
+
+	if parsedURL.Port() == "" {
+		advisories = append(advisories, fmt.Sprintf("service url has no port, the default port %s is used",
+			defaultServiceURLPort))
+	}
+
+	return strings.Join(advisories, "; "), nil
+}
+
+// normalizeServiceURL returns the service URL with port and without trailing slash if it is valid for staking
+func normalizeServiceURL(serviceURL string) (string, error) {
+	_, err := ValidateServiceURL(serviceURL)
+	if err != nil {
+		return "", err
+	}
+
+	parsedURL, err := url.Parse(serviceURL)
+	if err != nil {
+		return "", err
+	}
+
+	if parsedURL.Port() == "" {
+		parsedURL.Host = net.JoinHostPort(parsedURL.Hostname(), defaultServiceURLPort)
+		serviceURL = parsedURL.String()
+	}
+
+	return strings.TrimSuffix(serviceURL, "/"), nil
+}
+
 // NewUnstakeNode returns message for Unstake Node transaction
 func NewUnstakeNode(fromAddress, operatorAddress string) (TransactionMessage, error) {
 	decodedFromAddress, err := hex.DecodeString(fromAddress)
@@ -0,0 +1,166 @@
+package transactionbuilder
+
+import (
+	"fmt"
+	"strings"
+
+	coreTypes "github.com/vishruthsk/viper-network/types"
+	appsType "github.com/vishruthsk/viper-network/x/apps/types"
+	nodesTypes "github.com/vishruthsk/viper-network/x/nodes/types"
+)
+
+// addressLength is the length in bytes of a Viper address
+const addressLength = 20
+
+// ValidationError represents a transaction message failing a ValidationRule
+type ValidationError struct {
+	Rule    string
+	Message string
+}
+
+// Error returns string representation of error
+// needed to implement error interface
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Rule, e.Message)
+}
+
+// ValidationRule represents a pre-send check of a transaction message, it returns nil when the message passes it
+// Rules not applying to the message type, like an amount check of an unstake message, must pass
+type ValidationRule func(msg TransactionMessage) *ValidationError
+
+// TransactionValidator runs validation rules on transaction messages before they are sent
+type TransactionValidator struct {
+	rules []ValidationRule
+}
+
+// NewTransactionValidator returns instance of TransactionValidator running given rules on every message
+func NewTransactionValidator(rules ...ValidationRule) *TransactionValidator {
+	return &TransactionValidator{
+		rules: rules,
+	}
+}
+
+// Validate returns the errors of the validator rules plus given rules that the message fails, empty when it passes
+func (v *TransactionValidator) Validate(msg TransactionMessage, rules ...ValidationRule) []ValidationError {
+	if msg == nil {
+		return []ValidationError{{Rule: "message", Message: ErrNoTransactionMessage.Error()}}
+	}
+
+	errs := []ValidationError{}
+
+	for _, rule := range append(append([]ValidationRule{}, v.rules...), rules...) {
+		if err := rule(msg); err != nil {
+			errs = append(errs, *err)
+		}
+	}
+
+	return errs
+}
+
+// ValidateSignerAddress returns the rule checking the hex address is one of the signers of the message,
+// a transaction signed by another account is rejected on-chain
+func ValidateSignerAddress(address string) ValidationRule {
+	return func(msg TransactionMessage) *ValidationError {
+		for _, signer := range msg.GetSigners() {
+			if strings.EqualFold(signer.String(), address) {
+				return nil
+			}
+		}
+
+		return &ValidationError{Rule: "signer_address", Message: fmt.Sprintf("%s is not a signer of the message", address)}
+	}
+}
+
+// isTransferApp returns whether the app stake message is a transfer, see NewTransferApp
+func isTransferApp(msg *appsType.MsgStake) bool {
+	return msg.Chains == nil && msg.Value.IsZero()
+}
+
+// ValidatePositiveAmount checks the amount of send and stake messages is positive, app transfers are exempt
+func ValidatePositiveAmount(msg TransactionMessage) *ValidationError {
+	var amount coreTypes.BigInt
+
+	switch typedMsg := msg.(type) {
+	case *nodesTypes.MsgSend:
+		amount = typedMsg.Amount
+	case *appsType.MsgStake:
+		if isTransferApp(typedMsg) {
+			return nil
+		}
+
+		amount = typedMsg.Value
+	case *nodesTypes.MsgStake:
+		amount = typedMsg.Value
+	default:
+		return nil
+	}
+
+	if !amount.IsPositive() {
+		return &ValidationError{Rule: "positive_amount", Message: fmt.Sprintf("amount %s is not positive", amount)}
+	}
+
+	return nil
+}
+
+// ValidateNonEmptyChains checks stake messages have at least one chain, app transfers are exempt
+func ValidateNonEmptyChains(msg TransactionMessage) *ValidationError {
+	var chains []string
+
+	switch typedMsg := msg.(type) {
+	case *appsType.MsgStake:
+		if isTransferApp(typedMsg) {
+			return nil
+		}
+
+		chains = typedMsg.Chains
+	case *nodesTypes.MsgStake:
+		chains = typedMsg.Chains
+	default:
+		return nil
+	}
+
+	if len(chains) == 0 {
+		return &ValidationError{Rule: "non_empty_chains", Message: "stake message has no chains"}
+	}
+
+	return nil
+}
+
+// ValidateHexAddress checks every address of the message is a 20 bytes address
+func ValidateHexAddress(msg TransactionMessage) *ValidationError {
+	for _, field := range getMessageAddresses(msg) {
+		if len(field.address) != addressLength {
+			return &ValidationError{
+				Rule:    "hex_address",
+				Message: fmt.Sprintf("%s has %d bytes, expected %d", field.name, len(field.address), addressLength),
+			}
+		}
+	}
+
+	return nil
+}
+
+type addressField struct {
+	name    string
+	address []byte
+}
+
+// getMessageAddresses returns the address fields of the message in declaration order
+func getMessageAddresses(msg TransactionMessage) []addressField {
+	switch typedMsg := msg.(type) {
+	case *nodesTypes.MsgSend:
+		return []addressField{{"from_address", typedMsg.FromAddress}, {"to_address", typedMsg.ToAddress}}
+	case *nodesTypes.MsgStake:
+		return []addressField{{"output_address", typedMsg.Output}}
+	case *nodesTypes.MsgBeginUnstake:
+		return []addressField{{"address", typedMsg.Address}, {"signer_address", typedMsg.Signer}}
+	case *nodesTypes.MsgUnjail:
+		return []addressField{{"address", typedMsg.ValidatorAddr}, {"signer_address", typedMsg.Signer}}
+	case *appsType.MsgBeginUnstake:
+		return []addressField{{"address", typedMsg.Address}}
+	case *appsType.MsgUnjail:
+		return []addressField{{"address", typedMsg.AppAddr}}
+	default:
+		return nil
+	}
+}